@@ -0,0 +1,75 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// reAbbreviation は定義中の【略】に続く略語の並びに一致する
+// 略語の並びは次のラベル・補足説明・用例・注釈の手前までとする (例: "【略】AIDS" -> "AIDS", "【略】UN、U.N." -> "UN、U.N.")
+var reAbbreviation = regexp.MustCompile(`【略】\s*([^【◆■《〔（(；;]+)`)
+
+// extractAbbreviations は定義中の【略】に続く略語を、出現順に重複なく返す
+// 読点やカンマで区切られた複数の略語に対応し、日本語を含むもの (説明文など) は略語とみなさない
+// 例: "国際連合◆【略】UN、U.N." -> ["UN", "U.N."]
+func extractAbbreviations(definition string) []string {
+	var abbreviations []string
+	for _, match := range reAbbreviation.FindAllStringSubmatch(definition, -1) {
+		for _, part := range strings.FieldsFunc(match[1], func(r rune) bool {
+			return strings.ContainsRune("、，,／/", r)
+		}) {
+			abbreviation := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), "＝="))
+			abbreviation = strings.TrimRight(abbreviation, "。 ")
+			if abbreviation == "" || !isAbbreviation(abbreviation) {
+				continue
+			}
+			abbreviations = appendUnique(abbreviations, abbreviation)
+		}
+	}
+	return abbreviations
+}
+
+// isAbbreviation は英字を含み、日本語の文字を含まない文字列かどうかを判断する
+func isAbbreviation(s string) bool {
+	hasLetter := false
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	return hasLetter
+}
+
+// abbreviationLinker は【略】の略語から元の見出し語へのリンクのエントリを集める
+// 同じ見出し語の複数の行に同じ略語がある場合も、リンクは一つだけ作る
+type abbreviationLinker struct {
+	entries []DictionaryEntry
+	seen    map[string]bool
+}
+
+// add は定義中の【略】の略語から、見出し語 headword へのリンクを加える
+// 略語が見出し語と同じ場合 (大文字・小文字の違いを除く) は、自分自身へのリンクになるため加えない
+func (l *abbreviationLinker) add(definition, headword string, line int) {
+	for _, abbreviation := range extractAbbreviations(definition) {
+		if strings.EqualFold(abbreviation, headword) {
+			continue
+		}
+		key := abbreviation + "\x00" + headword
+		if l.seen[key] {
+			continue
+		}
+		if l.seen == nil {
+			l.seen = make(map[string]bool)
+		}
+		l.seen[key] = true
+		l.entries = append(l.entries, DictionaryEntry{
+			Headword:   abbreviation,
+			Definition: "@@@LINK=" + headword,
+			SourceLine: line,
+		})
+	}
+}
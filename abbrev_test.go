@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExtractAbbreviations は定義中の【略】に続く略語が取り出されることを検証します。
+func TestExtractAbbreviations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"【略】なし", "後天性免疫不全症候群", nil},
+		{"単一の略語", "《病理》後天性免疫不全症候群◆【略】AIDS", []string{"AIDS"}},
+		{"読点で区切られた略語", "国際連合◆【略】UN、U.N.", []string{"UN", "U.N."}},
+		{"後続のラベルの手前まで", "博士号【略】Ph.D.【レベル】5", []string{"Ph.D."}},
+		{"注釈の手前まで", "午前【略】a.m. (ante meridiemの略)", []string{"a.m."}},
+		{"日本語の説明は除く", "【略】エイズ", nil},
+		{"重複", "【略】UN◆【略】UN", []string{"UN"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := extractAbbreviations(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithAbbreviationLinks は【略】の略語から元の見出し語へのリンクが生成され、略語で定義を引けることを検証します。
+func TestParseWithAbbreviationLinks(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■acquired immune deficiency syndrome {名} : 《病理》後天性免疫不全症候群◆【略】AIDS",
+		"◆【略】AIDS",
+		"■United Nations {名} : 国際連合【略】UN、U.N.",
+		"■UN {略} : 国連",
+	}, "\n"))
+
+	entries, stats, err := parseEijiroWithStats(path, ParseOptions{AbbreviationLinks: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if stats.AbbreviationLinks != 3 || stats.LinkEntries != 3 || stats.Headwords != 3 {
+		t.Errorf("内訳が不正です: %+v", stats)
+	}
+
+	// マージ後の見出し語は小文字に統一される
+	final := resolveAndMergeEntries(entries)
+	definitions := make(map[string]string)
+	for _, entry := range final {
+		definitions[entry.Headword] = entry.Definition
+	}
+	if !strings.Contains(definitions["aids"], "後天性免疫不全症候群") {
+		t.Errorf("略語から元の見出し語の定義を引けません: %q", definitions["aids"])
+	}
+	if !strings.Contains(definitions["u.n."], "国際連合") {
+		t.Errorf("略語から元の見出し語の定義を引けません: %q", definitions["u.n."])
+	}
+	// 略語が既に見出し語にある場合は、その定義に元の見出し語の定義が加わる
+	if !strings.Contains(definitions["un"], "国連") || !strings.Contains(definitions["un"], "国際連合") {
+		t.Errorf("略語の見出し語の定義が不正です: %q", definitions["un"])
+	}
+
+	// オプションを指定しない場合はリンクを生成しない
+	entries, err = parseEijiro(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("エントリ数が不正です: %d", len(entries))
+	}
+}
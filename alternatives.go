@@ -0,0 +1,91 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// reAlternative は訳語中の言い換え (例: "追い払う[追い出す]" の "[追い出す]")
+var reAlternative = regexp.MustCompile(`[\[［]([^\[\]［］]+)[\]］]`)
+
+// alternativeDelimiters は言い換えの対象となる語句の区切りとみなす文字
+const alternativeDelimiters = "、,〕》」』）) 　"
+
+// expandAlternatives は "〔人・動物〕を追い払う[追い出す]" のような言い換えを含む訳語を、
+// ["〔人・動物〕を追い払う", "〔人・動物〕を追い出す"] のように列挙した訳語に展開する
+// 言い換えが置き換える範囲は、直前の語句の中で言い換えの先頭の文字が最後に現れる位置からとし、
+// 見つからない場合は直前の語(漢字・カタカナとそれに続くひらがな)の先頭からとする
+// 訳語が読点で区切られている場合は、それぞれを別の訳語として扱う
+func expandAlternatives(gloss string) []string {
+	var expanded []string
+	for _, item := range reReverseSplit.Split(gloss, -1) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		expanded = appendUnique(expanded, expandItemAlternatives(item)...)
+	}
+	return expanded
+}
+
+// expandItemAlternatives は読点を含まない一つの訳語を展開する
+func expandItemAlternatives(item string) []string {
+	matches := reAlternative.FindAllStringSubmatchIndex(item, -1)
+	if matches == nil {
+		return []string{item}
+	}
+
+	// 言い換えを取り除いた元の訳語
+	base := reAlternative.ReplaceAllString(item, "")
+	variants := []string{base}
+
+	// 言い換えごとに、元の訳語の該当部分を置き換えた訳語を作る
+	removed := 0 // 先行する言い換えを取り除いたことによる位置のずれ
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		alternative := item[m[2]:m[3]]
+		pos := start - removed // base における言い換えの位置
+		removed += end - start
+
+		before := base[:pos]
+		replaceFrom := strings.LastIndexAny(before, alternativeDelimiters)
+		if replaceFrom >= 0 {
+			// 区切り文字自体は残す
+			replaceFrom += len(string([]rune(before[replaceFrom:])[0]))
+		} else {
+			replaceFrom = 0
+		}
+		first := string([]rune(alternative)[0])
+		if i := strings.LastIndex(before, first); i >= replaceFrom {
+			replaceFrom = i
+		} else if i := lastWordStart(before); i > replaceFrom {
+			replaceFrom = i
+		}
+		variants = appendUnique(variants, before[:replaceFrom]+alternative+base[pos:])
+	}
+	return variants
+}
+
+// lastWordStart は文字列の末尾にある語の開始位置を返す
+// 末尾のひらがな(送り仮名)を読み飛ばし、その前に続く漢字・カタカナの並びの先頭を語の開始とみなす
+// 例: "速く走る" -> "走る" の位置
+func lastWordStart(s string) int {
+	i := len(s)
+	for i > 0 {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		if !unicode.Is(unicode.Hiragana, r) {
+			break
+		}
+		i -= size
+	}
+	for i > 0 {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		if !unicode.In(r, unicode.Han, unicode.Katakana) && r != 'ー' {
+			break
+		}
+		i -= size
+	}
+	return i
+}
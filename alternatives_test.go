@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExpandAlternatives は言い換えを含む訳語の展開を検証します。
+func TestExpandAlternatives(t *testing.T) {
+	testCases := []struct {
+		name     string
+		gloss    string
+		expected []string
+	}{
+		{
+			name:     "先頭の文字が共通する言い換え",
+			gloss:    "〔人・動物〕を追い払う[追い出す]",
+			expected: []string{"〔人・動物〕を追い払う", "〔人・動物〕を追い出す"},
+		},
+		{
+			name:     "区切り文字の後ろからの置き換え",
+			gloss:    "扉[ドア]、〔建物の〕入り口",
+			expected: []string{"扉", "ドア", "〔建物の〕入り口"},
+		},
+		{
+			name:     "複数の言い換え",
+			gloss:    "速く走る[歩く]人[動物]",
+			expected: []string{"速く走る人", "速く歩く人", "速く走る動物"},
+		},
+		{
+			name:     "全角の括弧",
+			gloss:    "手紙を書く［送る］",
+			expected: []string{"手紙を書く", "手紙を送る"},
+		},
+		{
+			name:     "言い換えがない訳語",
+			gloss:    "運転する",
+			expected: []string{"運転する"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := expandAlternatives(tc.gloss); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestApplySenseAlternatives は言い換えの展開が構造化データと逆引きの索引に反映されることを検証します。
+func TestApplySenseAlternatives(t *testing.T) {
+	var entry DictionaryEntry
+	applySense(&entry, "{動}", "〔人・動物〕を追い払う[追い出す]", ParseOptions{ExpandAlternatives: true})
+	expected := []string{"〔人・動物〕を追い払う", "〔人・動物〕を追い出す"}
+	if got := entry.lastSense().Alternatives; !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+
+	entries := []DictionaryEntry{{Headword: "drive", Definition: "{動} 〔人・動物〕を追い払う[追い出す]"}}
+	keys := make(map[string]bool)
+	for _, reverse := range buildReverseEntries(entries, true) {
+		keys[reverse.Headword] = true
+	}
+	if !keys["を追い払う"] || !keys["を追い出す"] || keys["を追い払う[追い出す]"] {
+		t.Errorf("逆引きの索引が展開されていません: %v", keys)
+	}
+}
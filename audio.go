@@ -0,0 +1,93 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// audioExtensions は発音音声として扱うファイルの拡張子
+var audioExtensions = map[string]bool{
+	".mp3":  true,
+	".ogg":  true,
+	".opus": true,
+	".spx":  true,
+	".wav":  true,
+	".m4a":  true,
+}
+
+// resourceDirName はStarDictの辞書ファイルと同じ階層に置くリソースディレクトリ名
+const resourceDirName = "res"
+
+// findAudioFiles はディレクトリ内の音声ファイルを探し、見出し語からファイル名へのマップを返す
+// 見出し語は小文字に統一し、ファイル名中の "_" は空白とみなす (例: "Kick_the_bucket.mp3" -> "kick the bucket")
+func findAudioFiles(dir string) (map[string]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	audioFiles := make(map[string]string)
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+		if file.IsDir() || !audioExtensions[ext] {
+			continue
+		}
+		word := strings.ToLower(strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())))
+		word = strings.ReplaceAll(word, "_", " ")
+		// 同じ見出し語に複数の形式がある場合は、最初に見つかったものを使う
+		if _, exists := audioFiles[word]; !exists {
+			audioFiles[word] = file.Name()
+		}
+	}
+	return audioFiles, nil
+}
+
+// attachAudioFiles は見出し語に一致する音声ファイルをエントリに対応付け、出力先の res/ ディレクトリにコピーする
+// 対応付けたエントリの件数を返す
+func attachAudioFiles(entries []DictionaryEntry, audioDir, outputDir string) (int, error) {
+	audioFiles, err := findAudioFiles(audioDir)
+	if err != nil {
+		return 0, errorf("音声ディレクトリの読み込みに失敗: %w", err)
+	}
+
+	resDir := filepath.Join(outputDir, resourceDirName)
+	linked := 0
+	for i := range entries {
+		fileName, ok := audioFiles[strings.ToLower(entries[i].Headword)]
+		if !ok {
+			continue
+		}
+		if linked == 0 {
+			if err := os.MkdirAll(resDir, 0755); err != nil {
+				return 0, err
+			}
+		}
+		if err := copyFile(filepath.Join(audioDir, fileName), filepath.Join(resDir, fileName)); err != nil {
+			return linked, errorf("'%s' のコピーに失敗: %w", fileName, err)
+		}
+		entries[i].Audio = fileName
+		linked++
+	}
+	return linked, nil
+}
+
+// copyFile はファイルをコピーする
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
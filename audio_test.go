@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAttachAudioFiles は音声ファイルが見出し語に対応付けられ、res/ にコピーされることを検証します。
+func TestAttachAudioFiles(t *testing.T) {
+	audioDir := t.TempDir()
+	outputDir := t.TempDir()
+	for _, name := range []string{"Door.mp3", "kick_the_bucket.ogg", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(audioDir, name), []byte("audio"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "kick the bucket", Definition: "死ぬ"},
+		{Headword: "notes", Definition: "{名} 覚え書き"},
+	}
+	linked, err := attachAudioFiles(entries, audioDir, outputDir)
+	if err != nil {
+		t.Fatalf("attachAudioFilesでエラーが発生しました: %v", err)
+	}
+	if linked != 2 || entries[0].Audio != "Door.mp3" || entries[1].Audio != "kick_the_bucket.ogg" || entries[2].Audio != "" {
+		t.Errorf("音声ファイルの対応付けが不正です: %d件, %+v", linked, entries)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, resourceDirName, "Door.mp3")); err != nil {
+		t.Errorf("音声ファイルが res/ にコピーされていません: %v", err)
+	}
+
+	html := htmlRenderer{}.Render(entries[1])
+	if !strings.Contains(html, `<audio controls src="kick_the_bucket.ogg"></audio><br>死ぬ`) {
+		t.Errorf("HTMLに音声タグが含まれていません: %s", html)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// profiler はCPUとメモリのプロファイルを書き出す
+// 正規表現を多用する処理の性能の変化を、Issueなどで具体的に示せるようにする
+type profiler struct {
+	CPUProfile string // CPUプロファイルの出力先 (空の場合は取得しない)
+	MemProfile string // 終了時のメモリプロファイルの出力先 (空の場合は取得しない)
+	cpuFile    *os.File
+}
+
+// registerProfileFlags はプロファイルのフラグを fs に登録し、解析後に profiler を組み立てる関数を返す
+func registerProfileFlags(fs *flag.FlagSet) func() *profiler {
+	cpuProfile := fs.String("cpuprofile", "", "CPUプロファイルを書き出すファイル名 (go tool pprof で解析できる)")
+	memProfile := fs.String("memprofile", "", "終了時のメモリプロファイルを書き出すファイル名 (go tool pprof で解析できる)")
+	return func() *profiler {
+		return &profiler{CPUProfile: *cpuProfile, MemProfile: *memProfile}
+	}
+}
+
+// start はCPUプロファイルの取得を開始する
+func (p *profiler) start() error {
+	if p.CPUProfile == "" {
+		return nil
+	}
+	file, err := os.Create(p.CPUProfile)
+	if err != nil {
+		return errorf("CPUプロファイルの作成に失敗しました: %w", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return errorf("CPUプロファイルの取得を開始できません: %w", err)
+	}
+	p.cpuFile = file
+	return nil
+}
+
+// stop はCPUプロファイルの取得を終え、メモリプロファイルを書き出す
+func (p *profiler) stop() error {
+	if p.cpuFile != nil {
+		pprof.StopCPUProfile()
+		if err := p.cpuFile.Close(); err != nil {
+			return errorf("CPUプロファイルの書き込みに失敗しました: %w", err)
+		}
+		p.cpuFile = nil
+	}
+	if p.MemProfile == "" {
+		return nil
+	}
+	file, err := os.Create(p.MemProfile)
+	if err != nil {
+		return errorf("メモリプロファイルの作成に失敗しました: %w", err)
+	}
+	defer file.Close()
+	// 直前までに解放されたメモリを反映させるため、先にGCを実行する
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return errorf("メモリプロファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// benchPhase はベンチマークの一段階の所要時間
+type benchPhase struct {
+	Phase   string        `json:"phase"`
+	Elapsed time.Duration `json:"elapsed_ns"`
+	Entries int           `json:"entries,omitempty"` // 段階を終えた時点のエントリ数
+}
+
+// runBenchmark は変換の各段階 (文字コード変換・パース・参照の解決とまとめ・描画・書き出し・圧縮) の所要時間を計測する
+// 書き出しと圧縮は dir に対して行う
+func runBenchmark(input string, opts ParseOptions, wopts WriteOptions, mergeStrategy, dir string) ([]benchPhase, error) {
+	var phases []benchPhase
+	measure := func(phase string, fn func() (int, error)) error {
+		start := time.Now()
+		entries, err := fn()
+		if err != nil {
+			return errorf("%s: %w", phase, err)
+		}
+		phases = append(phases, benchPhase{Phase: phase, Elapsed: time.Since(start), Entries: entries})
+		return nil
+	}
+
+	var entries, final []DictionaryEntry
+	var data starDictData
+	steps := []struct {
+		phase string
+		fn    func() (int, error)
+	}{
+		{"文字コード変換", func() (int, error) {
+			file, err := openInput(input)
+			if err != nil {
+				return 0, err
+			}
+			defer file.Close()
+			_, err = io.Copy(io.Discard, transform.NewReader(file, japanese.ShiftJIS.NewDecoder()))
+			return 0, err
+		}},
+		// パースは文字コードを変換しながら行うため、その時間も含む
+		{"パース", func() (int, error) {
+			var err error
+			entries, _, err = parseEijiroWithStats(input, opts)
+			return len(entries), err
+		}},
+		{"参照の解決と定義のまとめ", func() (int, error) {
+			final = applyMergeStrategy(resolveAndMergeEntries(entries), mergeStrategy)
+			return len(final), nil
+		}},
+		{"描画", func() (int, error) {
+			renderer, err := newRenderer(wopts)
+			if err != nil {
+				return 0, err
+			}
+			data, err = buildStarDictData(final, renderer)
+			return len(final), err
+		}},
+		// 圧縮の計測と区別するため、非圧縮の .dict は別の名前で書き出す
+		{"書き出し", func() (int, error) {
+			if err := os.WriteFile(filepath.Join(dir, "Bench.idx"), data.idx, 0644); err != nil {
+				return 0, err
+			}
+			return len(final), os.WriteFile(filepath.Join(dir, "Bench.dict.raw"), data.dict, 0644)
+		}},
+		{"圧縮", func() (int, error) {
+			return len(final), writeDictFile(filepath.Join(dir, "Bench.dict"), data.dict, false, reproducibleEpoch)
+		}},
+	}
+	for _, step := range steps {
+		if err := measure(step.phase, step.fn); err != nil {
+			return phases, err
+		}
+	}
+	return phases, nil
+}
+
+// fastestPhases は複数回の計測結果から、段階ごとに最も短い所要時間を選ぶ
+// 他のプロセスの影響などによるばらつきを除くため、平均ではなく最小値を使う
+func fastestPhases(runs [][]benchPhase) []benchPhase {
+	if len(runs) == 0 {
+		return nil
+	}
+	fastest := make([]benchPhase, len(runs[0]))
+	copy(fastest, runs[0])
+	for _, run := range runs[1:] {
+		for i, phase := range run {
+			if i < len(fastest) && phase.Elapsed < fastest[i].Elapsed {
+				fastest[i] = phase
+			}
+		}
+	}
+	return fastest
+}
+
+// writeBenchPhases は各段階の所要時間を format ("table" または "json") の形式で書き出す
+func writeBenchPhases(w io.Writer, phases []benchPhase, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(phases)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		var total time.Duration
+		for _, phase := range phases {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", tr(phase.Phase), phase.Elapsed.Round(time.Millisecond), sprintf("%d件", phase.Entries))
+			total += phase.Elapsed
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", tr("合計"), total.Round(time.Millisecond))
+		return tw.Flush()
+	default:
+		return errorf("未対応の出力形式です: %s (table または json を指定してください)", format)
+	}
+}
+
+// runBenchCommand は bench サブコマンドを実行する
+// 変換の各段階の所要時間を計測し、性能の変化を比較できるように出力する
+func runBenchCommand(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter bench [オプション]"))
+		fs.PrintDefaults()
+	}
+	inputFile := fs.String("i", "EIJIRO-1448.TXT", "計測に使う英辞郎ファイル名")
+	mergeStrategy := fs.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)")
+	count := fs.Int("count", 1, "計測の回数 (段階ごとに最も短い所要時間を出力する)")
+	format := fs.String("format", "table", "出力形式 (table: 表, json: JSON)")
+	parseOptions := registerParseFlags(fs)
+	writeOptions := registerWriteFlags(fs)
+	profileOptions := registerProfileFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if *format != "table" && *format != "json" {
+		return errorf("未対応の出力形式です: %s (table または json を指定してください)", *format)
+	}
+	if *count < 1 {
+		return errorf("-count には1以上を指定してください: %d", *count)
+	}
+	if err := validateMergeStrategy(*mergeStrategy); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "eijiro-bench-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	prof := profileOptions()
+	if err := prof.start(); err != nil {
+		return err
+	}
+	var runs [][]benchPhase
+	for i := 0; i < *count; i++ {
+		phases, err := runBenchmark(*inputFile, parseOptions(), writeOptions(), *mergeStrategy, dir)
+		if err != nil {
+			prof.stop()
+			return err
+		}
+		runs = append(runs, phases)
+	}
+	if err := prof.stop(); err != nil {
+		return err
+	}
+	return writeBenchPhases(os.Stdout, fastestPhases(runs), *format)
+}
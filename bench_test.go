@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRunBenchmark は変換の各段階の所要時間が順に計測されることを検証します。
+func TestRunBenchmark(t *testing.T) {
+	installFakeDictzip(t)
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n■know {動} : 知っている【変化】《動》knows | knew\n")
+
+	phases, err := runBenchmark(path, ParseOptions{}, WriteOptions{}, MergeConcat, t.TempDir())
+	if err != nil {
+		t.Fatalf("runBenchmarkでエラーが発生しました: %v", err)
+	}
+	expected := []string{"文字コード変換", "パース", "参照の解決と定義のまとめ", "描画", "書き出し", "圧縮"}
+	if len(phases) != len(expected) {
+		t.Fatalf("段階の数が不正です: %+v", phases)
+	}
+	for i, phase := range phases {
+		if phase.Phase != expected[i] {
+			t.Errorf("%d番目の段階 期待値: %s, 実際: %s", i+1, expected[i], phase.Phase)
+		}
+	}
+	if phases[1].Entries != 4 || phases[2].Entries != 4 {
+		t.Errorf("エントリ数が不正です: %+v", phases)
+	}
+}
+
+// TestFastestPhases は複数回の計測から段階ごとに最短の所要時間が選ばれることを検証します。
+func TestFastestPhases(t *testing.T) {
+	runs := [][]benchPhase{
+		{{Phase: "パース", Elapsed: 3 * time.Second}, {Phase: "圧縮", Elapsed: time.Second}},
+		{{Phase: "パース", Elapsed: 2 * time.Second}, {Phase: "圧縮", Elapsed: 4 * time.Second}},
+	}
+	fastest := fastestPhases(runs)
+	if fastest[0].Elapsed != 2*time.Second || fastest[1].Elapsed != time.Second {
+		t.Errorf("最短の所要時間が選ばれていません: %+v", fastest)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBenchPhases(&buf, fastest, "table"); err != nil || !strings.Contains(buf.String(), "合計") {
+		t.Errorf("表の出力が不正です: %v %q", err, buf.String())
+	}
+	buf.Reset()
+	var decoded []benchPhase
+	if err := writeBenchPhases(&buf, fastest, "json"); err != nil || json.Unmarshal(buf.Bytes(), &decoded) != nil || len(decoded) != 2 {
+		t.Errorf("JSONの出力が不正です: %v %q", err, buf.String())
+	}
+}
+
+// TestProfiler はCPUとメモリのプロファイルが書き出されることを検証します。
+func TestProfiler(t *testing.T) {
+	dir := t.TempDir()
+	prof := &profiler{CPUProfile: filepath.Join(dir, "cpu.pprof"), MemProfile: filepath.Join(dir, "mem.pprof")}
+	if err := prof.start(); err != nil {
+		t.Fatalf("startでエラーが発生しました: %v", err)
+	}
+	if err := prof.stop(); err != nil {
+		t.Fatalf("stopでエラーが発生しました: %v", err)
+	}
+	for _, path := range []string{prof.CPUProfile, prof.MemProfile} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s が書き出されていません: %v", path, err)
+		}
+	}
+}
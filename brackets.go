@@ -0,0 +1,70 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// reHeadwordBracket は見出し語中の括弧 (例: "at the end [close] of" の "[close]"、"(be) afraid of" の "(be)")
+// [] は直前の語の言い換え、〔〕と () は省略できる語句を表す
+var reHeadwordBracket = regexp.MustCompile(`\[([^\[\]]+)\]|〔([^〔〕]+)〕|\(([^()]+)\)`)
+
+// expandHeadwordBrackets は括弧を含む見出し語を、検索に使える見出し語の一覧に展開する
+// 先頭の要素は表示用の見出し語で、言い換え ([]) を取り除き、省略できる語句 (〔〕, ()) は括弧のみを外したもの
+// 続く要素は、言い換えごとに直前の語を置き換えたものと、省略できる語句ごとにそれを省いたもの
+// 例: "at the end [close] of" -> ["at the end of", "at the close of"]
+// 例: "(be) afraid of" -> ["be afraid of", "afraid of"]
+func expandHeadwordBrackets(headword string) []string {
+	matches := reHeadwordBracket.FindAllStringSubmatchIndex(headword, -1)
+	if matches == nil {
+		return []string{headword}
+	}
+
+	// 括弧を処理した表示用の見出し語と、その中での各括弧の位置
+	type segment struct {
+		start, end  int    // 表示用の見出し語における位置 (言い換えの場合は挿入位置)
+		alternative string // 言い換えの内容 (省略できる語句の場合は空)
+	}
+	var base strings.Builder
+	var segments []segment
+	last := 0
+	for _, m := range matches {
+		base.WriteString(headword[last:m[0]])
+		if m[2] >= 0 {
+			segments = append(segments, segment{start: base.Len(), end: base.Len(), alternative: headword[m[2]:m[3]]})
+		} else {
+			group := 2 // 〔〕
+			if m[4] < 0 {
+				group = 3 // ()
+			}
+			inner := headword[m[2*group]:m[2*group+1]]
+			start := base.Len()
+			base.WriteString(inner)
+			segments = append(segments, segment{start: start, end: base.Len()})
+		}
+		last = m[1]
+	}
+	base.WriteString(headword[last:])
+	display := base.String()
+
+	variants := []string{collapseSpaces(display)}
+	for _, seg := range segments {
+		var variant string
+		if seg.alternative == "" {
+			variant = display[:seg.start] + display[seg.end:]
+		} else {
+			before := strings.TrimRight(display[:seg.start], " ")
+			wordStart := strings.LastIndex(before, " ") + 1
+			variant = before[:wordStart] + seg.alternative + display[seg.start:]
+		}
+		if variant = collapseSpaces(variant); variant != "" {
+			variants = appendUnique(variants, variant)
+		}
+	}
+	return variants
+}
+
+// collapseSpaces は連続する空白を一つにまとめ、前後の空白を取り除く
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExpandHeadwordBrackets は見出し語の括弧が表示用の見出し語と検索用の形に展開されることを検証します。
+func TestExpandHeadwordBrackets(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"括弧なし", "door", []string{"door"}},
+		{"直前の語の言い換え", "at the end [close] of", []string{"at the end of", "at the close of"}},
+		{"省略できる語句", "(be) afraid of", []string{"be afraid of", "afraid of"}},
+		{"亀甲括弧", "take 〔someone's〕 side", []string{"take someone's side", "take side"}},
+		{"複数の括弧", "(be) at the end [close] of", []string{"be at the end of", "at the end of", "be at the close of"}},
+		{"先頭の言い換え", "[the] end", []string{"end", "the end"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := expandHeadwordBrackets(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithExpandBrackets は括弧を外した見出し語で登録され、他の形と元の表記が検索用キーワードになることを検証します。
+func TestParseWithExpandBrackets(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■at the end [close] of : ～の終わりに",
+		"■at the end [close] of : ～の最後に",
+	}, "\n"))
+
+	entries, err := parseEijiro(path, ParseOptions{ExpandBrackets: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("エントリ数が不正です: %+v", entries)
+	}
+	if entries[0].Headword != "at the end of" || !strings.Contains(entries[0].Definition, "最後に") {
+		t.Errorf("見出し語または定義が不正です: %+v", entries[0])
+	}
+	expected := []string{"at the close of", "at the end [close] of"}
+	if !reflect.DeepEqual(entries[0].Keywords, expected) {
+		t.Errorf("検索用キーワード 期待値: %q, 実際: %q", expected, entries[0].Keywords)
+	}
+
+	// オプションが無効な場合は従来どおり
+	entries, _ = parseEijiro(path, ParseOptions{})
+	if entries[0].Headword != "at the end [close] of" || len(entries[0].Keywords) != 0 {
+		t.Errorf("オプションが無効なのに見出し語が展開されています: %+v", entries[0])
+	}
+}
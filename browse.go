@@ -0,0 +1,346 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// browseResultRows は検索結果の一覧に表示する最大件数
+const browseResultRows = 10
+
+// 端末の制御シーケンス
+const (
+	ansiClear   = "\x1b[H\x1b[2J"
+	ansiReverse = "\x1b[7m"
+	ansiReset   = "\x1b[0m"
+)
+
+// browseToggle は閲覧中に切り替えられる削除オプション
+// 切り替えると、英辞郎ファイルを新しいオプションで読み込み直す
+type browseToggle struct {
+	key   byte   // 切り替えに使う制御キー (Ctrl+英字)
+	label string // 画面に表示する名前
+	field func(opts *ParseOptions) *bool
+}
+
+// browseToggles は閲覧中に切り替えられる削除オプションの一覧
+var browseToggles = []browseToggle{
+	{key: 'E' - '@', label: "用例", field: func(o *ParseOptions) *bool { return &o.StripExamples }},
+	{key: 'O' - '@', label: "補足", field: func(o *ParseOptions) *bool { return &o.StripSupplement }},
+	{key: 'R' - '@', label: "読み仮名", field: func(o *ParseOptions) *bool { return &o.StripRuby }},
+	{key: 'A' - '@', label: "発音", field: func(o *ParseOptions) *bool { return &o.StripPronunciation }},
+	{key: 'L' - '@', label: "レベル", field: func(o *ParseOptions) *bool { return &o.StripLevel }},
+}
+
+// keyKind は入力されたキーの種類
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyUp
+	keyDown
+	keyBackspace
+	keyQuit
+	keyControl
+)
+
+// keyEvent は入力されたキー一つ分
+type keyEvent struct {
+	kind keyKind
+	r    rune // keyRune の場合は入力された文字、keyControl の場合は制御文字
+}
+
+// parseKeys は端末から読み込んだバイト列をキー入力に分解する
+// 矢印キーはエスケープシーケンス (ESC [ A など) として届く
+func parseKeys(buf []byte) []keyEvent {
+	var events []keyEvent
+	for len(buf) > 0 {
+		switch {
+		case len(buf) >= 3 && buf[0] == 0x1b && buf[1] == '[':
+			switch buf[2] {
+			case 'A':
+				events = append(events, keyEvent{kind: keyUp})
+			case 'B':
+				events = append(events, keyEvent{kind: keyDown})
+			}
+			buf = buf[3:]
+			continue
+		case buf[0] == 0x1b || buf[0] == 0x03 || buf[0] == 0x04:
+			// ESC, Ctrl+C, Ctrl+D で終了する
+			events = append(events, keyEvent{kind: keyQuit})
+		case buf[0] == 0x7f || buf[0] == 0x08:
+			events = append(events, keyEvent{kind: keyBackspace})
+		case buf[0] == 'N'-'@':
+			events = append(events, keyEvent{kind: keyDown})
+		case buf[0] == 'P'-'@':
+			events = append(events, keyEvent{kind: keyUp})
+		case buf[0] < 0x20:
+			events = append(events, keyEvent{kind: keyControl, r: rune(buf[0])})
+		default:
+			r, size := utf8.DecodeRune(buf)
+			events = append(events, keyEvent{kind: keyRune, r: r})
+			buf = buf[size:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return events
+}
+
+// browser は対話的な閲覧画面の状態
+// 端末の入出力とは切り離してあり、キー入力を handleKey で受け取り、画面の内容を render で組み立てる
+type browser struct {
+	load     func(opts ParseOptions) (*Dictionary, error) // オプションを切り替えた際に辞書を読み込み直す
+	opts     ParseOptions
+	dict     *Dictionary
+	query    []rune
+	results  []DictionaryEntry
+	selected int
+	status   string
+}
+
+// newBrowser は辞書を読み込み、閲覧画面の初期状態を作る
+func newBrowser(opts ParseOptions, load func(opts ParseOptions) (*Dictionary, error)) (*browser, error) {
+	dict, err := load(opts)
+	if err != nil {
+		return nil, err
+	}
+	b := &browser{load: load, opts: opts, dict: dict}
+	b.search()
+	return b, nil
+}
+
+// search は入力中の文字列で前方一致検索を行い、結果の一覧を更新する
+func (b *browser) search() {
+	b.results = b.dict.Prefix(string(b.query), browseResultRows)
+	b.selected = min(b.selected, max(len(b.results)-1, 0))
+}
+
+// handleKey はキー入力を処理する。終了する場合は true を返す
+func (b *browser) handleKey(ev keyEvent) (quit bool, err error) {
+	switch ev.kind {
+	case keyQuit:
+		return true, nil
+	case keyUp:
+		b.selected = max(b.selected-1, 0)
+	case keyDown:
+		b.selected = min(b.selected+1, max(len(b.results)-1, 0))
+	case keyBackspace:
+		if len(b.query) > 0 {
+			b.query = b.query[:len(b.query)-1]
+			b.selected = 0
+			b.search()
+		}
+	case keyRune:
+		b.query = append(b.query, ev.r)
+		b.selected = 0
+		b.search()
+	case keyControl:
+		for _, toggle := range browseToggles {
+			if rune(toggle.key) != ev.r {
+				continue
+			}
+			opts := b.opts
+			*toggle.field(&opts) = !*toggle.field(&opts)
+			dict, err := b.load(opts)
+			if err != nil {
+				return false, err
+			}
+			b.opts, b.dict = opts, dict
+			b.status = toggle.label + "の表示を切り替えました"
+			b.search()
+		}
+	}
+	return false, nil
+}
+
+// render は幅 w・高さ h の端末に表示する画面の内容を組み立てる
+func (b *browser) render(w, h int) string {
+	var lines []string
+	lines = append(lines, "検索: "+string(b.query)+"_")
+
+	var toggles []string
+	for _, toggle := range browseToggles {
+		state := "表示"
+		if *toggle.field(&b.opts) {
+			state = "削除"
+		}
+		toggles = append(toggles, fmt.Sprintf("^%c %s:%s", toggle.key+'@', toggle.label, state))
+	}
+	lines = append(lines, strings.Join(toggles, "  "))
+	lines = append(lines, strings.Repeat("─", w))
+
+	for i := range browseResultRows {
+		switch {
+		case i >= len(b.results):
+			lines = append(lines, "")
+		case i == b.selected:
+			lines = append(lines, ansiReverse+"> "+truncateDisplay(b.results[i].Headword, w-2)+ansiReset)
+		default:
+			lines = append(lines, "  "+truncateDisplay(b.results[i].Headword, w-2))
+		}
+	}
+	lines = append(lines, strings.Repeat("─", w))
+
+	if len(b.results) > 0 {
+		for _, paragraph := range splitParagraphs(plainRenderer{}.Render(b.results[b.selected])) {
+			lines = append(lines, wrapDisplay(paragraph, w)...)
+		}
+	} else if len(b.query) > 0 {
+		lines = append(lines, "見つかりませんでした")
+	}
+
+	// 定義が長い場合は画面に収まる分だけ表示し、最終行は状態の表示に使う
+	if len(lines) > h-1 {
+		lines = lines[:max(h-1, 0)]
+	}
+	for len(lines) < h-1 {
+		lines = append(lines, "")
+	}
+	status := b.status
+	if status == "" {
+		status = fmt.Sprintf("%d語 ↑↓: 選択  Esc: 終了", b.dict.Len())
+	}
+	lines = append(lines, truncateDisplay(status, w))
+	return ansiClear + strings.Join(lines, "\r\n")
+}
+
+// runeWidth は端末上での文字の表示幅を返す (全角文字は2)
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// truncateDisplay は表示幅が w を超えないように文字列を切り詰める
+func truncateDisplay(s string, w int) string {
+	used := 0
+	for i, r := range s {
+		if used+runeWidth(r) > w {
+			return s[:i]
+		}
+		used += runeWidth(r)
+	}
+	return s
+}
+
+// wrapDisplay は表示幅が w を超えないように文字列を折り返す
+func wrapDisplay(s string, w int) []string {
+	if w <= 0 {
+		return nil
+	}
+	var lines []string
+	for s != "" {
+		line := truncateDisplay(s, w)
+		if line == "" {
+			// 幅が全角1文字に満たない場合も、無限に繰り返さないよう1文字は出力する
+			_, size := utf8.DecodeRuneInString(s)
+			line = s[:size]
+		}
+		lines = append(lines, line)
+		s = s[len(line):]
+	}
+	return lines
+}
+
+// terminalSize は端末の行数と桁数を返す (取得できない場合は 24行80桁とする)
+func terminalSize() (rows, cols int) {
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err == nil {
+		if _, err := fmt.Sscan(string(out), &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return rows, cols
+		}
+	}
+	return 24, 80
+}
+
+// enterRawMode は端末を1文字ずつ入力を受け取る状態にし、元に戻す関数を返す
+// 端末の設定には stty コマンドを利用する
+func enterRawMode() (restore func(), err error) {
+	save := exec.Command("stty", "-g")
+	save.Stdin = os.Stdin
+	state, err := save.Output()
+	if err != nil {
+		return nil, errorf("端末の設定を取得できません (stty が利用できる端末で実行してください): %w", err)
+	}
+	raw := exec.Command("stty", "raw", "-echo")
+	raw.Stdin = os.Stdin
+	if err := raw.Run(); err != nil {
+		return nil, errorf("端末の設定を変更できません: %w", err)
+	}
+	return func() {
+		restore := exec.Command("stty", strings.TrimSpace(string(state)))
+		restore.Stdin = os.Stdin
+		restore.Run()
+	}, nil
+}
+
+// runBrowser は端末の入出力で閲覧画面を操作する
+func runBrowser(b *browser, in io.Reader, out io.Writer) error {
+	buf := make([]byte, 64)
+	for {
+		rows, cols := terminalSize()
+		io.WriteString(out, b.render(cols, rows))
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, ev := range parseKeys(buf[:n]) {
+			b.status = ""
+			quit, err := b.handleKey(ev)
+			if err != nil || quit {
+				return err
+			}
+		}
+	}
+}
+
+// runBrowseCommand は browse サブコマンドを実行する
+// 英辞郎ファイルを読み込み、見出し語の前方一致検索と定義の表示を対話的に行う
+func runBrowseCommand(args []string) error {
+	fs := flag.NewFlagSet("browse", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter browse [オプション]"))
+		fs.PrintDefaults()
+	}
+	inputFile := fs.String("i", "EIJIRO-1448.TXT", "閲覧する英辞郎ファイル名")
+	mergeStrategy := fs.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)")
+	parseOptions := registerParseFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	load := func(opts ParseOptions) (*Dictionary, error) {
+		log.Print(sprintf("%s を読み込んでいます...", *inputFile))
+		return loadEijiroDictionary(*inputFile, opts, *mergeStrategy)
+	}
+	b, err := newBrowser(parseOptions(), load)
+	if err != nil {
+		return err
+	}
+
+	restore, err := enterRawMode()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		restore()
+		io.WriteString(os.Stdout, ansiClear)
+	}()
+	// 画面の表示中に読み込みのログが崩れて表示されないよう、ログは破棄する
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	return runBrowser(b, os.Stdin, os.Stdout)
+}
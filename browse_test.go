@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseKeys は端末から読み込んだバイト列がキー入力に分解されることを検証します。
+func TestParseKeys(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []keyEvent
+	}{
+		{name: "文字", input: "do", expected: []keyEvent{{kind: keyRune, r: 'd'}, {kind: keyRune, r: 'o'}}},
+		{name: "マルチバイト文字", input: "扉", expected: []keyEvent{{kind: keyRune, r: '扉'}}},
+		{name: "矢印キー", input: "\x1b[A\x1b[B", expected: []keyEvent{{kind: keyUp}, {kind: keyDown}}},
+		{name: "削除と終了", input: "\x7f\x1b", expected: []keyEvent{{kind: keyBackspace}, {kind: keyQuit}}},
+		{name: "制御キー", input: "\x05", expected: []keyEvent{{kind: keyControl, r: 0x05}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseKeys([]byte(tc.input)); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %+v, 実際: %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestBrowser は検索・選択・オプションの切り替えが画面に反映されることを検証します。
+func TestBrowser(t *testing.T) {
+	var loaded []ParseOptions
+	load := func(opts ParseOptions) (*Dictionary, error) {
+		loaded = append(loaded, opts)
+		def := "{名} 扉\n■Close the door. : 扉を閉めて。"
+		if opts.StripExamples {
+			def = "{名} 扉"
+		}
+		return NewDictionary([]DictionaryEntry{
+			{Headword: "door", Definition: def},
+			{Headword: "doorbell", Definition: "{名} 呼び鈴"},
+			{Headword: "know", Definition: "{動} 知っている"},
+		}), nil
+	}
+	b, err := newBrowser(ParseOptions{}, load)
+	if err != nil {
+		t.Fatalf("newBrowserでエラーが発生しました: %v", err)
+	}
+
+	var in bytes.Buffer
+	in.WriteString("doo\x1b[B")
+	var out bytes.Buffer
+	// 入力が尽きると読み込みエラーで終了する
+	runBrowser(b, &in, &out)
+	screen := b.render(40, 24)
+	if !strings.Contains(screen, "検索: doo_") || !strings.Contains(screen, ansiReverse+"> doorbell") || !strings.Contains(screen, "呼び鈴") {
+		t.Errorf("検索結果が画面に反映されていません:\n%s", screen)
+	}
+	if strings.Contains(screen, "know") {
+		t.Errorf("前方一致しない見出し語が表示されています:\n%s", screen)
+	}
+
+	b.handleKey(keyEvent{kind: keyUp})
+	if !strings.Contains(b.render(40, 24), "Close the door.") {
+		t.Errorf("選択した見出し語の用例が表示されていません")
+	}
+	b.handleKey(keyEvent{kind: keyControl, r: 'E' - '@'})
+	if len(loaded) != 2 || !loaded[1].StripExamples {
+		t.Fatalf("オプションを切り替えて読み込み直していません: %+v", loaded)
+	}
+	if screen := b.render(40, 24); strings.Contains(screen, "Close the door.") || !strings.Contains(screen, "^E 用例:削除") {
+		t.Errorf("切り替えたオプションが画面に反映されていません:\n%s", screen)
+	}
+
+	if quit, _ := b.handleKey(keyEvent{kind: keyQuit}); !quit {
+		t.Errorf("終了キーで終了していません")
+	}
+}
+
+// TestWrapDisplay は全角文字の表示幅を考慮して折り返されることを検証します。
+func TestWrapDisplay(t *testing.T) {
+	got := wrapDisplay("扉を閉めてdoor", 5)
+	expected := []string{"扉を", "閉め", "てdoo", "r"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"os"
+	"time"
+)
+
+// parseCacheVersion はパース結果のキャッシュの形式のバージョン
+// DictionaryEntry などの構造を変えた場合は値を増やし、古いキャッシュを読み込まないようにする
+const parseCacheVersion = 5
+
+// parseCache はパース結果のキャッシュ
+// 時間のかかるパースを省略し、出力のオプションだけを変えて変換をやり直すために使う
+type parseCache struct {
+	FormatVersion int
+	Input         string       // パースした英辞郎ファイル名 (辞書のバージョンの決定に使う)
+	Options       ParseOptions // パースに使ったオプション (Deadline は記録しない)
+	Stats         ParseStats
+	Entries       []DictionaryEntry
+}
+
+// writeParseCache はパース結果をgobで符号化し、gzipで圧縮して書き出す
+// 書き出しの途中で中断されても壊れたキャッシュが残らないよう、一時的な名前で書き出してから置き換える
+func writeParseCache(path string, cache parseCache) error {
+	cache.FormatVersion = parseCacheVersion
+	cache.Options.Deadline = time.Time{}
+
+	tmpPath := path + partialSuffix
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(file)
+	err = gob.NewEncoder(zw).Encode(cache)
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// readParseCache は writeParseCache で書き出したキャッシュを読み込む
+func readParseCache(path string) (parseCache, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return parseCache{}, err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return parseCache{}, errorf("キャッシュの形式が不正です: %w", err)
+	}
+	defer zr.Close()
+
+	var cache parseCache
+	if err := gob.NewDecoder(zr).Decode(&cache); err != nil {
+		return parseCache{}, errorf("キャッシュの形式が不正です: %w", err)
+	}
+	if cache.FormatVersion != parseCacheVersion {
+		return parseCache{}, errorf("キャッシュの形式のバージョン (%d) が現在のバージョン (%d) と異なります。キャッシュを作り直してください", cache.FormatVersion, parseCacheVersion)
+	}
+	return cache, nil
+}
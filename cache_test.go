@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestParseCacheRoundTrip はキャッシュに書き出したパース結果を、そのまま読み込めることを検証します。
+func TestParseCacheRoundTrip(t *testing.T) {
+	path := writeEijiroTestFile(t, "■door {名} : 扉■・Close the door. : 扉を閉めて。\n■know {動} : 知っている【変化】《動》knows | knew\n")
+	opts := ParseOptions{SplitExamples: true}
+	entries, stats, err := parseEijiroWithStats(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "parse.cache")
+	if err := writeParseCache(cachePath, parseCache{Input: path, Options: opts, Stats: stats, Entries: entries}); err != nil {
+		t.Fatalf("writeParseCacheでエラーが発生しました: %v", err)
+	}
+	cache, err := readParseCache(cachePath)
+	if err != nil {
+		t.Fatalf("readParseCacheでエラーが発生しました: %v", err)
+	}
+	if cache.Input != path || cache.Options != opts || cache.Stats != stats || !reflect.DeepEqual(cache.Entries, entries) {
+		t.Errorf("キャッシュから元のパース結果を復元できません: %+v", cache)
+	}
+
+	// 形式の異なるファイルはエラーになること
+	os.WriteFile(cachePath, []byte("not a cache"), 0644)
+	if _, err := readParseCache(cachePath); err == nil {
+		t.Error("不正なキャッシュでエラーが返されていません")
+	}
+}
+
+// TestRunConversionFromCache は -cache で書き出したキャッシュから、英辞郎ファイルなしで同じ辞書を作れることを検証します。
+func TestRunConversionFromCache(t *testing.T) {
+	installFakeDictzip(t)
+	dir := t.TempDir()
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n■know {動} : 知っている【変化】《動》knows | knew\n")
+	cachePath := filepath.Join(dir, "parse.cache")
+
+	first, err := runConversion(context.Background(), ConvertConfig{InputFile: path, OutputDir: filepath.Join(dir, "first"), BookName: "Test", MergeStrategy: MergeConcat, CacheFile: cachePath})
+	if err != nil {
+		t.Fatalf("runConversionでエラーが発生しました: %v", err)
+	}
+	os.Remove(path)
+
+	second, err := runConversion(context.Background(), ConvertConfig{OutputDir: filepath.Join(dir, "second"), BookName: "Test", MergeStrategy: MergeConcat, FromCache: cachePath})
+	if err != nil {
+		t.Fatalf("キャッシュからの変換でエラーが発生しました: %v", err)
+	}
+	if second.Input != path || second.FinalEntries != first.FinalEntries {
+		t.Errorf("キャッシュからの変換結果が異なります: %+v, %+v", first, second)
+	}
+	for _, read := range []func(base string) ([]byte, error){
+		func(base string) ([]byte, error) { return os.ReadFile(base + ".idx") },
+		readDictData,
+	} {
+		a, _ := read(filepath.Join(dir, "first", "Test"))
+		b, _ := read(filepath.Join(dir, "second", "Test"))
+		if len(a) == 0 || !reflect.DeepEqual(a, b) {
+			t.Errorf("辞書の内容がキャッシュを使わない場合と異なります: %q, %q", a, b)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "strings"
+
+// preserveHeadwordCase はマージで小文字に統一された見出し語を、元の表記に戻す
+// 同じ見出し語に小文字のみの表記がある場合はそれを優先し (例: "Doors" と "doors" -> "doors")、
+// ない場合は最初に現れた表記を使う (例: "NASA")
+// 表記を戻した見出し語には小文字のキーワードを追加し、大文字小文字を区別しない検索を .syn で保証する
+func preserveHeadwordCase(finalEntries, parsedEntries []DictionaryEntry) {
+	applyHeadwordCase(finalEntries, headwordDisplayForms(parsedEntries))
+}
+
+// headwordDisplayForms はパース結果から、小文字に統一した見出し語ごとに戻す表記を求める
+// 参照の解決でパース結果の領域を再利用する場合は、参照の解決の前に求めておく
+func headwordDisplayForms(parsedEntries []DictionaryEntry) map[string]string {
+	display := make(map[string]string)
+	for _, entry := range parsedEntries {
+		key := strings.ToLower(entry.Headword)
+		current, exists := display[key]
+		if !exists || (current != key && entry.Headword == key) {
+			display[key] = entry.Headword
+		}
+	}
+	return display
+}
+
+// applyHeadwordCase は headwordDisplayForms で求めた表記に、見出し語を戻す
+func applyHeadwordCase(finalEntries []DictionaryEntry, display map[string]string) {
+	for i := range finalEntries {
+		key := finalEntries[i].Headword
+		if original, ok := display[key]; ok && original != key {
+			finalEntries[i].Headword = original
+			finalEntries[i].Keywords = appendUnique(finalEntries[i].Keywords, key)
+		}
+	}
+}
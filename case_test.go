@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPreserveHeadwordCase は見出し語の元の表記が復元されることを検証します。
+func TestPreserveHeadwordCase(t *testing.T) {
+	parsed := []DictionaryEntry{
+		{Headword: "Doors", Definition: "{バンド名} ドアーズ"},
+		{Headword: "NASA", Definition: "{組織} 米航空宇宙局"},
+		{Headword: "doors", Definition: "@@@LINK=door"},
+		{Headword: "door", Definition: "{名} 扉"},
+	}
+	final := resolveAndMergeEntries(parsed)
+	preserveHeadwordCase(final, parsed)
+
+	got := make(map[string][]string)
+	for _, entry := range final {
+		got[entry.Headword] = entry.Keywords
+	}
+	expected := map[string][]string{
+		"doors": nil,
+		"NASA":  {"nasa"},
+		"door":  nil,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, got)
+	}
+}
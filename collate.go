@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// 一覧の並べ方 (ConvertConfig.Collation に指定する値)
+const (
+	CollationByte     = "byte"     // UTF-8のバイト列の順
+	CollationJapanese = "japanese" // 五十音順 (JIS X 4061 の考え方に沿った簡易的な照合)
+)
+
+// validateCollation は一覧の並べ方の指定が正しいかを確認する
+func validateCollation(collation string) error {
+	switch collation {
+	case "", CollationByte, CollationJapanese:
+		return nil
+	}
+	return errorf("未対応の並べ方です: %s (%s または %s を指定してください)", collation, CollationByte, CollationJapanese)
+}
+
+// collationLess は並べ方に応じた文字列の比較関数を返す
+// StarDict の .idx は辞書アプリが二分探索するため、この並べ方の指定に関わらず stardictStrcmp の順で書き出す
+// この比較関数は、見出し語一覧など人が読む一覧の並べ替えに使う
+func collationLess(collation string) func(a, b string) bool {
+	if collation != CollationJapanese {
+		return func(a, b string) bool { return a < b }
+	}
+	return func(a, b string) bool {
+		return compareJapanese(a, b) < 0
+	}
+}
+
+// compareJapanese は五十音順で文字列を比較する
+// 1. 清音・濁音・半濁音、大きい仮名と小さい仮名、ひらがなとカタカナ、全角と半角、英字の大文字と小文字を区別せずに比較する
+// 2. 等しい場合は、清音・濁音・半濁音の順 (例: はは < ばば < ぱぱ) で比較する
+// 3. それでも等しい場合は、元の文字列のバイト列で比較する
+// 漢字は読みが分からないため、仮名の後にコードポイント順で並ぶ
+func compareJapanese(a, b string) int {
+	ka, kb := japaneseCollationKey(a), japaneseCollationKey(b)
+	if c := strings.Compare(ka.primary, kb.primary); c != 0 {
+		return c
+	}
+	if c := strings.Compare(ka.secondary, kb.secondary); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// collationKey は五十音順の比較に使う、文字列を段階的に揃えた形
+type collationKey struct {
+	primary   string // 濁点・半濁点を除き、すべての揃え方を適用した形
+	secondary string // 濁点・半濁点を残した形
+}
+
+// smallKana は小さい仮名と、対応する大きい仮名
+var smallKana = strings.NewReplacer(
+	"ぁ", "あ", "ぃ", "い", "ぅ", "う", "ぇ", "え", "ぉ", "お",
+	"っ", "つ", "ゃ", "や", "ゅ", "ゆ", "ょ", "よ", "ゎ", "わ", "ゕ", "か", "ゖ", "け",
+)
+
+// kanaVowels は長音記号 (ー) を直前の仮名の母音に置き換えるための、母音ごとの仮名の一覧 (清音のみ)
+var kanaVowels = map[rune]string{
+	'あ': "あかさたなはまやらわ",
+	'い': "いきしちにひみり",
+	'う': "うくすつぬふむゆる",
+	'え': "えけせてねへめれ",
+	'お': "おこそとのほもよろを",
+}
+
+// japaneseCollationKey は文字列から五十音順の比較に使う形を作る
+func japaneseCollationKey(s string) collationKey {
+	// 全角英数字を半角に、半角カナを全角に揃え、濁点・半濁点を結合文字に分解する
+	s = norm.NFD.String(width.Fold.String(s))
+
+	var secondary, primary strings.Builder
+	var prev rune
+	for _, r := range strings.ToLower(s) {
+		// カタカナをひらがなに揃える
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+		}
+		secondary.WriteRune(r)
+		if r == '\u3099' || r == '\u309a' {
+			continue
+		}
+		if r == 'ー' {
+			r = vowelOf(prev)
+		}
+		primary.WriteRune(r)
+		prev = r
+	}
+	return collationKey{primary: smallKana.Replace(primary.String()), secondary: smallKana.Replace(secondary.String())}
+}
+
+// vowelOf は仮名の母音を返す (母音が分からない場合は長音記号のまま返す)
+func vowelOf(r rune) rune {
+	if small := []rune(smallKana.Replace(string(r))); len(small) == 1 {
+		r = small[0]
+	}
+	for vowel, kana := range kanaVowels {
+		if strings.ContainsRune(kana, r) {
+			return vowel
+		}
+	}
+	return 'ー'
+}
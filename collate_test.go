@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestCompareJapanese は五十音順の比較で、仮名の種類や清濁などの違いが段階的に扱われることを検証します。
+func TestCompareJapanese(t *testing.T) {
+	testCases := []struct {
+		name string
+		a, b string
+	}{
+		{"五十音順", "あさ", "いぬ"},
+		{"カタカナとひらがなを区別しない", "アサ", "いぬ"},
+		{"濁音は清音の後", "はは", "ばば"},
+		{"濁音の違いより後ろの文字を優先", "ばか", "はし"},
+		{"半濁音は濁音の後", "ばば", "ぱぱ"},
+		{"小さい仮名は大きい仮名と同じ位置", "きゃく", "きやま"},
+		{"長音記号は直前の母音", "カード", "かあとり"},
+		{"半角カナ", "ｱｻ", "いぬ"},
+		{"英字の大文字と小文字を区別しない", "Apple", "banana"},
+		{"仮名は漢字の前", "ん", "亜"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if compareJapanese(tc.a, tc.b) >= 0 || compareJapanese(tc.b, tc.a) <= 0 {
+				t.Errorf("%q が %q より前に並びません", tc.a, tc.b)
+			}
+		})
+	}
+	if compareJapanese("ドア", "ドア") != 0 {
+		t.Errorf("同じ文字列が等しくなりません")
+	}
+}
+
+// TestCollationLess は並べ方の指定に応じて一覧の順序が変わることを検証します。
+func TestCollationLess(t *testing.T) {
+	words := []string{"ドア", "とびら", "Zebra", "apple", "扉"}
+	testCases := []struct {
+		collation string
+		expected  []string
+	}{
+		{CollationByte, []string{"Zebra", "apple", "とびら", "ドア", "扉"}},
+		{CollationJapanese, []string{"apple", "Zebra", "ドア", "とびら", "扉"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.collation, func(t *testing.T) {
+			got := append([]string(nil), words...)
+			less := collationLess(tc.collation)
+			sort.Slice(got, func(i, j int) bool { return less(got[i], got[j]) })
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+	if validateCollation("unicode") == nil {
+		t.Errorf("未対応の並べ方でエラーになりませんでした")
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// writeDictFile は .dict の内容を書き出し、圧縮する
+// noCompress が true の場合は非圧縮の .dict のまま残す
+// dictzip が見つからない場合は、警告を出したうえで同じgzip互換の形式でプロセス内で圧縮する
+func writeDictFile(dictPath string, data []byte, noCompress bool, modTime time.Time) error {
+	dzPath := dictPath + ".dz"
+
+	if noCompress {
+		// 読み込み側は .dict.dz を優先するため、以前の変換で作られたものが残らないようにする
+		if err := removeStaleFiles(dzPath); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dictPath, data, 0644); err != nil {
+			return errorf(".dict ファイルの書き込みに失敗: %w", err)
+		}
+		return nil
+	}
+
+	if !dictzipAvailable() {
+		// 同じ名前の非圧縮の .dict が残っていると紛らわしいため削除しておく
+		if err := removeStaleFiles(dictPath); err != nil {
+			return err
+		}
+		if err := writeGzipFile(dzPath, data); err != nil {
+			return errorf(".dict.dz ファイルの書き込みに失敗: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(dictPath, data, 0644); err != nil {
+		return errorf(".dict ファイルの書き込みに失敗: %w", err)
+	}
+	return runDictzip(dictPath, modTime)
+}
+
+// dictzipAvailable は dictzip コマンドで .dict を圧縮できるかどうかを返す
+// 見つからない場合は、プロセス内でgzip互換の形式に圧縮することを警告する
+func dictzipAvailable() bool {
+	if _, err := exec.LookPath("dictzip"); err != nil {
+		logger.Warn(tr("dictzipが見つからないため、gzipで圧縮します。辞書アプリによっては定義の読み込みが遅くなる場合があります。"))
+		return false
+	}
+	return true
+}
+
+// runDictzip は非圧縮の .dict を dictzip コマンドで .dict.dz に圧縮する
+// dictzip は .dict の更新日時をヘッダーに記録するため、圧縮する前に更新日時を modTime にしておく
+// dictzipは成功すると元のファイルを削除する
+func runDictzip(dictPath string, modTime time.Time) error {
+	if err := os.Chtimes(dictPath, modTime, modTime); err != nil {
+		return err
+	}
+	cmd := exec.Command("dictzip", dictPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errorf("dictzipの実行に失敗: %w\n%s", err, string(output))
+	}
+	return nil
+}
+
+// removeStaleFiles は以前の変換で作られたファイルを削除する (存在しないファイルは無視する)
+func removeStaleFiles(paths ...string) error {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return errorf("以前の %s ファイルの削除に失敗: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// writeGzipFile は data をgzipで圧縮して path に書き出す
+// dictzip形式はgzipの拡張のため、辞書アプリはこのファイルも .dict.dz として読み込める
+func writeGzipFile(path string, data []byte) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeGzip(file, data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeGzip は data をgzipで圧縮して w に書き出す
+func writeGzip(w io.Writer, data []byte) error {
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
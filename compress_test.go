@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteDictFile は圧縮の有無と dictzip の有無に応じて、読み込める定義ファイルが書き出されることを検証します。
+func TestWriteDictFile(t *testing.T) {
+	content := []byte("{名} 扉")
+	testCases := []struct {
+		name       string
+		noCompress bool
+		dictzip    bool
+		expected   string // 書き出されるファイルの拡張子
+	}{
+		{"dictzipで圧縮", false, true, ".dict.dz"},
+		{"dictzipがない場合はgzipで圧縮", false, false, ".dict.dz"},
+		{"圧縮しない", true, true, ".dict"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.dictzip {
+				installFakeDictzip(t)
+			} else {
+				t.Setenv("PATH", t.TempDir())
+			}
+			base := filepath.Join(t.TempDir(), "Test")
+			// 以前の変換で作られたファイルが残っていても、読み込まれるのは新しい内容であること
+			os.WriteFile(base+".dict.dz", []byte("stale"), 0644)
+			os.WriteFile(base+".dict", []byte("stale"), 0644)
+
+			if err := writeDictFile(base+".dict", content, tc.noCompress, reproducibleEpoch); err != nil {
+				t.Fatalf("writeDictFileでエラーが発生しました: %v", err)
+			}
+			if _, err := os.Stat(base + tc.expected); err != nil {
+				t.Errorf("%s が書き出されていません: %v", tc.expected, err)
+			}
+			data, err := readDictData(base)
+			if err != nil {
+				t.Fatalf("readDictDataでエラーが発生しました: %v", err)
+			}
+			if string(data) != string(content) {
+				t.Errorf("期待値: %q, 実際: %q", content, data)
+			}
+		})
+	}
+}
+
+// TestWriteStarDictFilesIdx は .idx と .idx.gz のどちらで書き出しても同じ内容を読み込めることを検証します。
+func TestWriteStarDictFilesIdx(t *testing.T) {
+	entries := []DictionaryEntry{{Headword: "door", Definition: "扉"}}
+	testCases := []struct {
+		name     string
+		compress bool
+		expected string // 書き出されるファイルの拡張子
+	}{
+		{"圧縮しない", false, ".idx"},
+		{"gzipで圧縮", true, ".idx.gz"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			base := filepath.Join(dir, "Test")
+			if err := writeStarDictFiles(dir, "Test", "1.0", entries, WriteOptions{NoCompress: true, CompressIdx: tc.compress}); err != nil {
+				t.Fatalf("writeStarDictFilesでエラーが発生しました: %v", err)
+			}
+			if _, err := os.Stat(base + tc.expected); err != nil {
+				t.Errorf("%s が書き出されていません: %v", tc.expected, err)
+			}
+			data, err := readIdxData(base)
+			if err != nil {
+				t.Fatalf("readIdxDataでエラーが発生しました: %v", err)
+			}
+			if expected := "door\x00\x00\x00\x00\x00\x00\x00\x00\x03"; string(data) != expected {
+				t.Errorf("期待値: %q, 実際: %q", expected, data)
+			}
+		})
+	}
+}
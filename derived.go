@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 定義文を含まない派生データのファイル名
+const (
+	derivedHeadwordsFile   = "headwords.txt"
+	derivedInflectionsFile = "inflections.tsv"
+	derivedStatsFile       = "stats.json"
+)
+
+// inflectionEdge は変化形から原形への参照 (例: "knew" -> "know")
+type inflectionEdge struct {
+	Form string
+	Base string
+}
+
+// derivedStats は派生データとして出力する統計情報
+type derivedStats struct {
+	ParsedEntries   int `json:"parsed_entries"`
+	Headwords       int `json:"headwords"`
+	SingleWords     int `json:"single_words"`
+	MultiWords      int `json:"multi_words"`
+	InflectionLinks int `json:"inflection_links"`
+}
+
+// writeDerivedArtifacts は著作物である定義文を含まない派生データのみを書き出す
+// 見出し語の一覧・変化形の参照関係・統計情報は、ビルド手順や索引を公開で共有するために利用できる
+// entries はマージ前のパース結果を渡す (変化形のリンク情報を取り出すため)。collation は見出し語の一覧の並べ方
+func writeDerivedArtifacts(dir string, entries []DictionaryEntry, collation string) error {
+	headwordSet := make(map[string]bool)
+	edgeSet := make(map[inflectionEdge]bool)
+	for _, entry := range entries {
+		headword := strings.ToLower(entry.Headword)
+		headwordSet[headword] = true
+		for _, target := range splitLinks(entry.Definition).targets {
+			edgeSet[inflectionEdge{Form: headword, Base: target}] = true
+		}
+	}
+
+	headwords := make([]string, 0, len(headwordSet))
+	stats := derivedStats{ParsedEntries: len(entries), Headwords: len(headwordSet), InflectionLinks: len(edgeSet)}
+	for headword := range headwordSet {
+		headwords = append(headwords, headword)
+		if strings.Contains(headword, " ") {
+			stats.MultiWords++
+		} else {
+			stats.SingleWords++
+		}
+	}
+	less := collationLess(collation)
+	sort.Slice(headwords, func(i, j int) bool { return less(headwords[i], headwords[j]) })
+
+	edges := make([]inflectionEdge, 0, len(edgeSet))
+	for edge := range edgeSet {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Form != edges[j].Form {
+			return edges[i].Form < edges[j].Form
+		}
+		return edges[i].Base < edges[j].Base
+	})
+
+	if err := writeLines(filepath.Join(dir, derivedHeadwordsFile), headwords); err != nil {
+		return errorf("見出し語一覧の書き込みに失敗: %w", err)
+	}
+	edgeLines := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		edgeLines = append(edgeLines, edge.Form+"\t"+edge.Base)
+	}
+	if err := writeLines(filepath.Join(dir, derivedInflectionsFile), edgeLines); err != nil {
+		return errorf("変化形の参照関係の書き込みに失敗: %w", err)
+	}
+	statsJSON, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, derivedStatsFile), append(statsJSON, '\n'), 0644); err != nil {
+		return errorf("統計情報の書き込みに失敗: %w", err)
+	}
+	return nil
+}
+
+// writeLines は文字列を1行ずつファイルに書き出す
+func writeLines(path string, lines []string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		writer.WriteString(line)
+		writer.WriteByte('\n')
+	}
+	return writer.Flush()
+}
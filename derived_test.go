@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWriteDerivedArtifacts は派生データに定義文が含まれないことを検証します。
+func TestWriteDerivedArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	entries := []DictionaryEntry{
+		{Headword: "know", Definition: "{動} 知っている"},
+		{Headword: "knew", Definition: "{動} knowの過去形\n@@@LINK=know"},
+		{Headword: "Kick the bucket", Definition: "死ぬ"},
+	}
+	if err := writeDerivedArtifacts(dir, entries, CollationByte); err != nil {
+		t.Fatalf("writeDerivedArtifactsでエラーが発生しました: %v", err)
+	}
+
+	expected := map[string]string{
+		derivedHeadwordsFile:   "kick the bucket\nknew\nknow\n",
+		derivedInflectionsFile: "knew\tknow\n",
+	}
+	for name, content := range expected {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s の内容が不正です。期待値: %q, 実際: %q", name, content, got)
+		}
+	}
+
+	stats, err := os.ReadFile(filepath.Join(dir, derivedStatsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(stats), `"multi_words": 1`) {
+		t.Errorf("統計情報が不正です: %s", stats)
+	}
+
+	files, _ := os.ReadDir(dir)
+	for _, file := range files {
+		content, _ := os.ReadFile(filepath.Join(dir, file.Name()))
+		if strings.Contains(string(content), "知っている") {
+			t.Errorf("%s に定義文が含まれています", file.Name())
+		}
+	}
+}
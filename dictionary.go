@@ -0,0 +1,92 @@
+package main
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Dictionary は変換済みのエントリを検索できる形で保持する
+// 生成後は内部状態を一切変更しないため、一つのインスタンスを複数のgoroutineから同時に利用できる
+// (サーバーやバッチ処理で、読み込んだ辞書を共有することを想定している)
+type Dictionary struct {
+	entries []DictionaryEntry
+	index   map[string]int // 小文字に統一した見出し語・キーワードから entries の位置への対応
+	keys    []string       // 前方一致検索用の、小文字に統一した見出し語の昇順リスト
+}
+
+// NewDictionary はエントリから検索用の Dictionary を生成する
+// エントリは複製して保持するため、呼び出し後に引数のスライスを変更しても影響しない
+func NewDictionary(entries []DictionaryEntry) *Dictionary {
+	d := &Dictionary{
+		entries: make([]DictionaryEntry, len(entries)),
+		index:   make(map[string]int, len(entries)),
+		keys:    make([]string, 0, len(entries)),
+	}
+	for i, entry := range sortEntriesForStarDict(entries) {
+		d.entries[i] = cloneEntry(entry)
+		key := strings.ToLower(entry.Headword)
+		if _, exists := d.index[key]; !exists {
+			d.index[key] = i
+			d.keys = append(d.keys, key)
+		}
+	}
+	// 見出し語が優先されるよう、キーワードは後から登録する
+	for i, entry := range d.entries {
+		for _, keyword := range entry.Keywords {
+			if _, exists := d.index[strings.ToLower(keyword)]; !exists {
+				d.index[strings.ToLower(keyword)] = i
+			}
+		}
+	}
+	sort.Strings(d.keys)
+	return d
+}
+
+// Len は辞書に含まれるエントリ数を返す
+func (d *Dictionary) Len() int {
+	return len(d.entries)
+}
+
+// Lookup は見出し語（またはキーワード）に完全一致するエントリを返す
+// 大文字と小文字は区別しない。返されるエントリは複製なので、呼び出し側で変更してもよい
+func (d *Dictionary) Lookup(word string) (DictionaryEntry, bool) {
+	i, ok := d.index[strings.ToLower(word)]
+	if !ok {
+		return DictionaryEntry{}, false
+	}
+	return cloneEntry(d.entries[i]), true
+}
+
+// Prefix は見出し語が prefix で始まるエントリを、見出し語順に最大 limit 件返す
+// limit が0以下の場合は件数を制限しない
+func (d *Dictionary) Prefix(prefix string, limit int) []DictionaryEntry {
+	prefix = strings.ToLower(prefix)
+	var results []DictionaryEntry
+	for i := sort.SearchStrings(d.keys, prefix); i < len(d.keys) && strings.HasPrefix(d.keys[i], prefix); i++ {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		results = append(results, cloneEntry(d.entries[d.index[d.keys[i]]]))
+	}
+	return results
+}
+
+// Random は無作為に選んだエントリを返す (辞書が空の場合は false)
+func (d *Dictionary) Random() (DictionaryEntry, bool) {
+	if len(d.entries) == 0 {
+		return DictionaryEntry{}, false
+	}
+	return cloneEntry(d.entries[rand.IntN(len(d.entries))]), true
+}
+
+// cloneEntry はスライスを含めてエントリを複製する
+func cloneEntry(entry DictionaryEntry) DictionaryEntry {
+	entry.Examples = slices.Clone(entry.Examples)
+	entry.Keywords = slices.Clone(entry.Keywords)
+	entry.Senses = cloneSenses(entry.Senses)
+	entry.CrossRefs = slices.Clone(entry.CrossRefs)
+	entry.Hyphenation = slices.Clone(entry.Hyphenation)
+	return entry
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestDictionaryLookup は完全一致と前方一致の検索を検証します。
+func TestDictionaryLookup(t *testing.T) {
+	dict := NewDictionary([]DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "doorbell", Definition: "{名} 玄関の呼び鈴"},
+		{Headword: "tactical", Definition: "{形} 戦術的な", Keywords: []string{"タクティカル"}},
+	})
+
+	if entry, ok := dict.Lookup("Door"); !ok || entry.Definition != "{名} 扉" {
+		t.Errorf("大文字小文字を無視した完全一致検索に失敗しました: %+v", entry)
+	}
+	if entry, ok := dict.Lookup("タクティカル"); !ok || entry.Headword != "tactical" {
+		t.Errorf("キーワードによる検索に失敗しました: %+v", entry)
+	}
+	if _, ok := dict.Lookup("window"); ok {
+		t.Errorf("存在しない見出し語が見つかりました")
+	}
+
+	results := dict.Prefix("door", 0)
+	if len(results) != 2 || results[0].Headword != "door" || results[1].Headword != "doorbell" {
+		t.Errorf("前方一致検索の結果が不正です: %+v", results)
+	}
+	if results := dict.Prefix("door", 1); len(results) != 1 {
+		t.Errorf("件数の制限が効いていません: %+v", results)
+	}
+
+	if entry, ok := dict.Random(); !ok || entry.Headword == "" {
+		t.Errorf("無作為なエントリを取得できません: %+v", entry)
+	}
+	if _, ok := NewDictionary(nil).Random(); ok {
+		t.Errorf("空の辞書からエントリが返されました")
+	}
+}
+
+// TestDictionaryConcurrentUse は一つの辞書を複数のgoroutineから同時に利用できることを検証します。
+// go test -race で実行すると、データ競合も検出できます。
+func TestDictionaryConcurrentUse(t *testing.T) {
+	var entries []DictionaryEntry
+	for i := 0; i < 100; i++ {
+		entries = append(entries, DictionaryEntry{Headword: fmt.Sprintf("word%03d", i), Keywords: []string{fmt.Sprintf("key%03d", i)}})
+	}
+	dict := NewDictionary(entries)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				entry, ok := dict.Lookup(fmt.Sprintf("word%03d", i))
+				if !ok {
+					t.Errorf("word%03d が見つかりませんでした", i)
+					return
+				}
+				// 返されたエントリを変更しても辞書には影響しない
+				entry.Keywords[0] = "changed"
+				dict.Prefix("word0", 10)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entry, _ := dict.Lookup("word000"); entry.Keywords[0] != "key000" {
+		t.Errorf("返されたエントリの変更が辞書に影響しています: %+v", entry)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"unicode"
+)
+
+// DICTプロトコル (RFC 2229) のサーバーに関する定数
+const (
+	dictDefaultAddr   = ":2628" // DICTプロトコルの標準のポート
+	dictDatabaseName  = "eijiro"
+	dictDatabaseDesc  = "英辞郎 (eijiro-converter)"
+	dictMaxMatches    = 1000 // MATCH コマンドで返す最大件数
+	dictServerBanner  = "eijiro-converter DICT server"
+	dictMaxLineLength = 1024 // RFC 2229 で定められたコマンド行の最大長
+)
+
+// dictStrategies は MATCH コマンドで利用できる検索方法と、その説明
+var dictStrategies = []struct{ name, desc string }{
+	{"exact", "完全一致"},
+	{"prefix", "前方一致"},
+}
+
+// dictServer は変換済みのエントリをDICTプロトコルで提供するサーバー
+// Dictionary は生成後に変更されないため、複数の接続から同時に参照する
+type dictServer struct {
+	dict     *Dictionary
+	renderer Renderer
+}
+
+// serve は接続を受け付け、接続ごとに goroutine で処理する
+func (s *dictServer) serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := s.handle(conn); err != nil {
+				log.Print(sprintf("DICT: %s: %v", conn.RemoteAddr(), err))
+			}
+		}()
+	}
+}
+
+// handle は一つの接続でコマンドを読み込み、QUIT または切断まで応答する
+func (s *dictServer) handle(rw io.ReadWriter) error {
+	w := bufio.NewWriter(rw)
+	reader := bufio.NewReaderSize(rw, dictMaxLineLength)
+	fmt.Fprintf(w, "220 %s <> <eijiro-converter>\r\n", dictServerBanner)
+	for {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		line, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			// 長すぎる行は読み込む領域を増やさずに読み捨て、エラーを返して次の行から処理を続ける
+			if err := discardLine(reader); err != nil {
+				return ignoreEOF(err)
+			}
+			fmt.Fprint(w, "500 line too long\r\n")
+			continue
+		} else if err != nil {
+			return ignoreEOF(err)
+		}
+		if !s.command(w, strings.TrimRight(string(line), "\r\n")) {
+			return w.Flush()
+		}
+	}
+}
+
+// discardLine は行の残りを、改行まで読み捨てる
+func discardLine(reader *bufio.Reader) error {
+	for {
+		_, err := reader.ReadSlice('\n')
+		if err != bufio.ErrBufferFull {
+			return err
+		}
+	}
+}
+
+// ignoreEOF は接続が閉じられたことによる io.EOF を、正常な終了として nil にする
+func ignoreEOF(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// command は1行分のコマンドに応答する。接続を終了する場合は false を返す
+func (s *dictServer) command(w *bufio.Writer, line string) bool {
+	args, ok := splitDICTArgs(line)
+	if !ok {
+		fmt.Fprint(w, "501 syntax error, illegal parameters\r\n")
+		return true
+	}
+	if len(args) == 0 {
+		return true
+	}
+	switch cmd := strings.ToUpper(args[0]); {
+	case cmd == "QUIT":
+		fmt.Fprint(w, "221 bye\r\n")
+		return false
+	case cmd == "DEFINE" && len(args) == 3:
+		s.define(w, args[1], args[2])
+	case cmd == "MATCH" && len(args) == 4:
+		s.match(w, args[1], args[2], args[3])
+	case cmd == "SHOW" && len(args) >= 2:
+		s.show(w, args[1:])
+	case cmd == "CLIENT" || cmd == "OPTION":
+		fmt.Fprint(w, "250 ok\r\n")
+	case cmd == "STATUS":
+		fmt.Fprintf(w, "210 status: %d entries\r\n", s.dict.Len())
+	case cmd == "HELP":
+		fmt.Fprint(w, "113 help text follows\r\n")
+		writeDICTText(w, "DEFINE database word\nMATCH database strategy word\nSHOW DB\nSHOW STRAT\nSHOW INFO database\nSHOW SERVER\nSTATUS\nHELP\nQUIT")
+		fmt.Fprint(w, "250 ok\r\n")
+	case cmd == "DEFINE" || cmd == "MATCH" || cmd == "SHOW":
+		fmt.Fprint(w, "501 syntax error, illegal parameters\r\n")
+	default:
+		fmt.Fprint(w, "500 unknown command\r\n")
+	}
+	return true
+}
+
+// validDatabase はデータベース名がこのサーバーのものか ("*" と "!" はすべてのデータベースを表す) を返す
+func validDatabase(db string) bool {
+	return db == dictDatabaseName || db == "*" || db == "!"
+}
+
+// define は DEFINE コマンドに応答する
+func (s *dictServer) define(w *bufio.Writer, db, word string) {
+	if !validDatabase(db) {
+		fmt.Fprint(w, "550 invalid database, use \"SHOW DB\" for list of databases\r\n")
+		return
+	}
+	entry, found := s.dict.Lookup(word)
+	if !found {
+		fmt.Fprint(w, "552 no match\r\n")
+		return
+	}
+	fmt.Fprint(w, "150 1 definitions retrieved\r\n")
+	fmt.Fprintf(w, "151 %s %s %s\r\n", quoteDICT(entry.Headword), dictDatabaseName, quoteDICT(dictDatabaseDesc))
+	writeDICTText(w, entry.Headword+"\n"+s.renderer.Render(entry))
+	fmt.Fprint(w, "250 ok\r\n")
+}
+
+// match は MATCH コマンドに応答する ("." は既定の検索方法として前方一致を表す)
+func (s *dictServer) match(w *bufio.Writer, db, strategy, word string) {
+	if !validDatabase(db) {
+		fmt.Fprint(w, "550 invalid database, use \"SHOW DB\" for list of databases\r\n")
+		return
+	}
+	var headwords []string
+	switch strings.ToLower(strategy) {
+	case "exact":
+		if entry, found := s.dict.Lookup(word); found {
+			headwords = append(headwords, entry.Headword)
+		}
+	case "prefix", ".":
+		for _, entry := range s.dict.Prefix(word, dictMaxMatches) {
+			headwords = append(headwords, entry.Headword)
+		}
+	default:
+		fmt.Fprint(w, "551 invalid strategy, use \"SHOW STRAT\" for a list of strategies\r\n")
+		return
+	}
+	if len(headwords) == 0 {
+		fmt.Fprint(w, "552 no match\r\n")
+		return
+	}
+	fmt.Fprintf(w, "152 %d matches found\r\n", len(headwords))
+	lines := make([]string, len(headwords))
+	for i, headword := range headwords {
+		lines[i] = dictDatabaseName + " " + quoteDICT(headword)
+	}
+	writeDICTText(w, strings.Join(lines, "\n"))
+	fmt.Fprint(w, "250 ok\r\n")
+}
+
+// show は SHOW コマンドに応答する
+func (s *dictServer) show(w *bufio.Writer, args []string) {
+	switch strings.ToUpper(args[0]) {
+	case "DB", "DATABASES":
+		fmt.Fprint(w, "110 1 databases present\r\n")
+		writeDICTText(w, dictDatabaseName+" "+quoteDICT(dictDatabaseDesc))
+		fmt.Fprint(w, "250 ok\r\n")
+	case "STRAT", "STRATEGIES":
+		fmt.Fprintf(w, "111 %d strategies available\r\n", len(dictStrategies))
+		lines := make([]string, len(dictStrategies))
+		for i, strategy := range dictStrategies {
+			lines[i] = strategy.name + " " + quoteDICT(strategy.desc)
+		}
+		writeDICTText(w, strings.Join(lines, "\n"))
+		fmt.Fprint(w, "250 ok\r\n")
+	case "INFO":
+		if len(args) != 2 || !validDatabase(args[1]) {
+			fmt.Fprint(w, "550 invalid database, use \"SHOW DB\" for list of databases\r\n")
+			return
+		}
+		fmt.Fprintf(w, "112 database information follows\r\n")
+		writeDICTText(w, fmt.Sprintf("%s\n%d entries", dictDatabaseDesc, s.dict.Len()))
+		fmt.Fprint(w, "250 ok\r\n")
+	case "SERVER":
+		fmt.Fprint(w, "114 server information follows\r\n")
+		writeDICTText(w, dictServerBanner)
+		fmt.Fprint(w, "250 ok\r\n")
+	default:
+		fmt.Fprint(w, "501 syntax error, illegal parameters\r\n")
+	}
+}
+
+// writeDICTText は複数行の本文を、行頭の "." を重ねた上で "." だけの行で終端して書き出す
+func writeDICTText(w *bufio.Writer, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, ".") {
+			line = "." + line
+		}
+		w.WriteString(line + "\r\n")
+	}
+	w.WriteString(".\r\n")
+}
+
+// quoteDICT は文字列を二重引用符で囲む (引用符と "\" はエスケープする)
+func quoteDICT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// splitDICTArgs はコマンド行を引数に分解する
+// 引数は空白で区切られ、一重・二重引用符で囲んだ部分と "\" でエスケープした文字は一つの引数の中身として扱う
+// 引用符が閉じていない場合は ok が false になる
+func splitDICTArgs(line string) (args []string, ok bool) {
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, false
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, true
+}
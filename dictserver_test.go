@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDICTServer はDICTプロトコルの主なコマンドへの応答を検証します。
+func TestDICTServer(t *testing.T) {
+	server := &dictServer{
+		dict: NewDictionary([]DictionaryEntry{
+			{Headword: "door", Definition: "{名} 扉\n.で始まる行"},
+			{Headword: "doorbell", Definition: "{名} 呼び鈴"},
+			{Headword: "know", Definition: "{動} 知っている", Keywords: []string{"knew"}},
+		}),
+		renderer: plainRenderer{},
+	}
+
+	testCases := []struct {
+		name     string
+		command  string
+		expected string
+	}{
+		{
+			name:     "定義の取得",
+			command:  "DEFINE eijiro Door",
+			expected: "150 1 definitions retrieved\r\n151 \"door\" eijiro \"英辞郎 (eijiro-converter)\"\r\ndoor\r\n{名} 扉\r\n..で始まる行\r\n.\r\n250 ok\r\n",
+		},
+		{
+			name:     "キーワードからの定義の取得",
+			command:  "define * \"knew\"",
+			expected: "150 1 definitions retrieved\r\n151 \"know\" eijiro \"英辞郎 (eijiro-converter)\"\r\nknow\r\n{動} 知っている\r\n.\r\n250 ok\r\n",
+		},
+		{
+			name:     "前方一致",
+			command:  "MATCH eijiro prefix doo",
+			expected: "152 2 matches found\r\neijiro \"door\"\r\neijiro \"doorbell\"\r\n.\r\n250 ok\r\n",
+		},
+		{name: "見つからない見出し語", command: "DEFINE eijiro gate", expected: "552 no match\r\n"},
+		{name: "不正なデータベース", command: "DEFINE wordnet door", expected: "550 invalid database, use \"SHOW DB\" for list of databases\r\n"},
+		{name: "不正な検索方法", command: "MATCH eijiro soundex door", expected: "551 invalid strategy, use \"SHOW STRAT\" for a list of strategies\r\n"},
+		{name: "データベースの一覧", command: "SHOW DB", expected: "110 1 databases present\r\neijiro \"英辞郎 (eijiro-converter)\"\r\n.\r\n250 ok\r\n"},
+		{name: "引数の不足", command: "DEFINE eijiro", expected: "501 syntax error, illegal parameters\r\n"},
+		{name: "未知のコマンド", command: "LOOKUP door", expected: "500 unknown command\r\n"},
+		{name: "長すぎる行は読み捨てて次の行を処理する", command: "DEFINE eijiro " + strings.Repeat("a", dictMaxLineLength*3), expected: "500 line too long\r\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			conn := struct {
+				io.Reader
+				io.Writer
+			}{strings.NewReader(tc.command + "\r\nQUIT\r\n"), &out}
+			if err := server.handle(conn); err != nil {
+				t.Fatalf("handleでエラーが発生しました: %v", err)
+			}
+			got := out.String()
+			if !strings.HasPrefix(got, "220 ") || !strings.HasSuffix(got, "221 bye\r\n") {
+				t.Fatalf("接続時または終了時の応答が不正です: %q", got)
+			}
+			got = got[strings.Index(got, "\r\n")+2 : len(got)-len("221 bye\r\n")]
+			if got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestSplitDICTArgs はコマンド行の引数の分解を検証します。
+func TestSplitDICTArgs(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected []string
+		ok       bool
+	}{
+		{line: "DEFINE eijiro door", expected: []string{"DEFINE", "eijiro", "door"}, ok: true},
+		{line: `DEFINE eijiro "front door"`, expected: []string{"DEFINE", "eijiro", "front door"}, ok: true},
+		{line: `MATCH * . 'it\'s'`, expected: []string{"MATCH", "*", ".", "it's"}, ok: true},
+		{line: `DEFINE eijiro ""`, expected: []string{"DEFINE", "eijiro", ""}, ok: true},
+		{line: `DEFINE eijiro "door`, ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			got, ok := splitDICTArgs(tc.line)
+			if ok != tc.ok || (ok && !reflect.DeepEqual(got, tc.expected)) {
+				t.Errorf("期待値: %q (%v), 実際: %q (%v)", tc.expected, tc.ok, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// diffReport は2つの版の英辞郎ファイルを比べた結果
+// 見出し語は参照の解決と定義のまとめを終えた後の (小文字に統一した) ものを、それぞれ見出し語順に並べる
+type diffReport struct {
+	Old     string   `json:"old"`
+	New     string   `json:"new"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// diffEntries は旧版と新版のエントリを見出し語ごとに比べ、追加・削除・定義が変わった見出し語を求める
+// あわせて、新版のうち追加または変更されたエントリ (更新分のみの辞書の内容) を見出し語順に返す
+func diffEntries(oldEntries, newEntries []DictionaryEntry) (diffReport, []DictionaryEntry) {
+	oldDefs := make(map[string]string, len(oldEntries))
+	for _, entry := range oldEntries {
+		oldDefs[strings.ToLower(entry.Headword)] = entry.Definition
+	}
+
+	var report diffReport
+	var updates []DictionaryEntry
+	seen := make(map[string]bool, len(newEntries))
+	for _, entry := range newEntries {
+		key := strings.ToLower(entry.Headword)
+		seen[key] = true
+		oldDef, ok := oldDefs[key]
+		switch {
+		case !ok:
+			report.Added = append(report.Added, entry.Headword)
+		case oldDef != entry.Definition:
+			report.Changed = append(report.Changed, entry.Headword)
+		default:
+			continue
+		}
+		updates = append(updates, entry)
+	}
+	for _, entry := range oldEntries {
+		if !seen[strings.ToLower(entry.Headword)] {
+			report.Removed = append(report.Removed, entry.Headword)
+		}
+	}
+
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	sort.Strings(report.Changed)
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Headword < updates[j].Headword
+	})
+	return report, updates
+}
+
+// writeDiffReport は比較結果を format ("table" または "json") の形式で書き出す
+// table の場合は件数に続けて、追加を "+"、削除を "-"、変更を "~" を付けた行で1行1語ずつ書き出す
+func writeDiffReport(w io.Writer, report diffReport, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(report)
+	case "table":
+		fmt.Fprintln(w, sprintf("追加: %d件, 削除: %d件, 変更: %d件", len(report.Added), len(report.Removed), len(report.Changed)))
+		for _, group := range []struct {
+			mark      string
+			headwords []string
+		}{{"+", report.Added}, {"-", report.Removed}, {"~", report.Changed}} {
+			for _, headword := range group.headwords {
+				fmt.Fprintf(w, "%s %s\n", group.mark, headword)
+			}
+		}
+		return nil
+	default:
+		return errorf("未対応の出力形式です: %s (table または json を指定してください)", format)
+	}
+}
+
+// parseAndMerge は英辞郎ファイルをパースし、参照の解決と定義のまとめを終えたエントリを返す
+func parseAndMerge(path string, opts ParseOptions, mergeStrategy string) ([]DictionaryEntry, error) {
+	entries, err := parseEijiro(path, opts)
+	if err != nil {
+		return nil, errorf("%s のパースに失敗しました: %w", path, err)
+	}
+	return applyMergeStrategy(resolveAndMergeEntries(entries), mergeStrategy), nil
+}
+
+// runDiffCommand は diff サブコマンドを実行する
+// 2つの版の英辞郎ファイルを同じオプションで処理して見出し語の追加・削除・変更を出力し、
+// 指定した場合は追加・変更された見出し語のみからなる更新分の辞書を書き出す
+func runDiffCommand(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter diff [オプション] 旧版の英辞郎ファイル 新版の英辞郎ファイル"))
+		fs.PrintDefaults()
+	}
+	mergeStrategy := fs.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)")
+	format := fs.String("format", "table", "出力形式 (table: 表, json: JSON)")
+	updatesDir := fs.String("updates-dir", "", "追加・変更された見出し語のみからなる更新分の辞書を書き出すディレクトリ (空の場合は書き出さない)")
+	bookName := fs.String("b", "Eijiro-updates", "更新分の辞書の名前")
+	parseOptions := registerParseFlags(fs)
+	writeOptions := registerWriteFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errorf("比較する2つの英辞郎ファイルを指定してください")
+	}
+	if *format != "table" && *format != "json" {
+		return errorf("未対応の出力形式です: %s (table または json を指定してください)", *format)
+	}
+	if err := validateMergeStrategy(*mergeStrategy); err != nil {
+		return err
+	}
+	oldPath, newPath := fs.Arg(0), fs.Arg(1)
+	opts := parseOptions()
+	oldEntries, err := parseAndMerge(oldPath, opts, *mergeStrategy)
+	if err != nil {
+		return err
+	}
+	newEntries, err := parseAndMerge(newPath, opts, *mergeStrategy)
+	if err != nil {
+		return err
+	}
+
+	report, updates := diffEntries(oldEntries, newEntries)
+	report.Old, report.New = oldPath, newPath
+	if err := writeDiffReport(os.Stdout, report, *format); err != nil {
+		return err
+	}
+
+	if *updatesDir != "" {
+		if err := os.MkdirAll(*updatesDir, 0755); err != nil {
+			return errorf("出力ディレクトリの作成に失敗しました: %w", err)
+		}
+		// 変換と同じく一時ディレクトリに書き出してから置き換え、以前の更新分の辞書のファイルが残らないようにする
+		var outputs pendingOutputs
+		stageDir, err := outputs.stage(*updatesDir)
+		if err != nil {
+			return err
+		}
+		version := extractVersionFromFilename(inputName(newPath))
+		if err := writeStarDictFiles(stageDir, *bookName, version, updates, writeOptions()); err != nil {
+			outputs.abort()
+			return errorf("更新分の辞書の書き込みに失敗しました: %w", err)
+		}
+		if err := outputs.commit(); err != nil {
+			outputs.abort()
+			return err
+		}
+		logger.Info(sprintf("%d件の見出し語からなる更新分の辞書を書き出しました: %s", len(updates), *updatesDir))
+	}
+	return nil
+}
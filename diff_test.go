@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestDiffEntries は2つの版のエントリから、追加・削除・変更された見出し語と更新分のエントリが求められることを検証します。
+func TestDiffEntries(t *testing.T) {
+	oldEntries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "window", Definition: "{名} 窓"},
+		{Headword: "fax", Definition: "{名} ファクス"},
+	}
+	newEntries := []DictionaryEntry{
+		{Headword: "window", Definition: "{名} 窓、ウィンドウ"},
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "emoji", Definition: "{名} 絵文字"},
+	}
+	report, updates := diffEntries(oldEntries, newEntries)
+
+	expected := diffReport{Added: []string{"emoji"}, Removed: []string{"fax"}, Changed: []string{"window"}}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("期待値: %+v\n実際: %+v", expected, report)
+	}
+	expectedUpdates := []DictionaryEntry{newEntries[2], newEntries[0]}
+	if !reflect.DeepEqual(updates, expectedUpdates) {
+		t.Errorf("更新分のエントリが違います\n期待値: %+v\n実際: %+v", expectedUpdates, updates)
+	}
+}
+
+// TestWriteDiffReport は比較結果が表形式で出力され、未対応の形式がエラーになることを検証します。
+func TestWriteDiffReport(t *testing.T) {
+	report := diffReport{Added: []string{"emoji"}, Removed: []string{"fax"}, Changed: []string{"window"}}
+	var buf bytes.Buffer
+	if err := writeDiffReport(&buf, report, "table"); err != nil {
+		t.Fatalf("writeDiffReportでエラーが発生しました: %v", err)
+	}
+	expected := "追加: 1件, 削除: 1件, 変更: 1件\n+ emoji\n- fax\n~ window\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+	if err := writeDiffReport(&buf, report, "csv"); err == nil {
+		t.Error("未対応の形式でエラーになりませんでした")
+	}
+}
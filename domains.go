@@ -0,0 +1,98 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// reDomainLabel は専門分野や位相を表すラベル (例: 《医》《法》《俗》) に一致する
+var reDomainLabel = regexp.MustCompile(`《([^《》]+)》`)
+
+// maxDomainLength は分野・用法のラベルとみなす最大の文字数
+// 《the ～》や《～を》のような文法上の注記と区別するため、短いものだけを対象にする
+const maxDomainLength = 6
+
+// extractDomains は定義行から分野・用法のラベルを、出現順に重複なく返す
+// 【変化】の《複》などはラベル (【…】) の内容なので対象にしない
+// 例: "《医》《俗》腫瘍" -> ["医", "俗"]
+func extractDomains(text string) []string {
+	if i := strings.Index(text, "【"); i >= 0 {
+		text = text[:i]
+	}
+	var domains []string
+	for _, match := range reDomainLabel.FindAllStringSubmatch(text, -1) {
+		if name := strings.TrimSpace(match[1]); isDomainLabel(name) {
+			domains = appendUnique(domains, name)
+		}
+	}
+	return domains
+}
+
+// isDomainLabel は《》の内容が分野・用法のラベルかどうかを判断する
+// 記号 (~) や英字を含むもの、長いものは文法上の注記とみなす
+func isDomainLabel(name string) bool {
+	return name != "" && utf8.RuneCountInString(name) <= maxDomainLength &&
+		!strings.ContainsAny(name, headwordPlaceholders) &&
+		strings.IndexFunc(name, func(r rune) bool { return r < utf8.RuneSelf && unicode.IsLetter(r) }) < 0
+}
+
+// renderDomainLabels はHTMLエスケープ済みの文字列の分野・用法のラベルを、スタイルシートで装飾できる要素で囲む
+func renderDomainLabels(s string) string {
+	return reDomainLabel.ReplaceAllStringFunc(s, func(label string) string {
+		if !isDomainLabel(strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(label, "《"), "》"))) {
+			return label
+		}
+		return `<span class="domain">` + label + "</span>"
+	})
+}
+
+// domainFilter は分野・用法のラベルによって、定義行を残すかどうかを決める
+type domainFilter struct {
+	include []string // 空でない場合、いずれかのラベルを持つ行のみを残す
+	exclude []string // いずれかのラベルを持つ行を除外する
+}
+
+// newDomainFilter はカンマ区切りで指定したラベルから domainFilter を作る
+// ラベルは《》を付けても付けなくてもよい (例: "医,法" または "《医》,《法》")
+// どちらも指定しない場合は nil を返す
+func newDomainFilter(include, exclude string) *domainFilter {
+	trim := func(list []string) []string {
+		for i, s := range list {
+			list[i] = strings.TrimSuffix(strings.TrimPrefix(s, "《"), "》")
+		}
+		return list
+	}
+	f := &domainFilter{include: trim(splitList(include)), exclude: trim(splitList(exclude))}
+	if len(f.include) == 0 && len(f.exclude) == 0 {
+		return nil
+	}
+	return f
+}
+
+// keep は分野・用法のラベルが domains である定義行を残すかどうかを返す
+// 《米俗》のような複合したラベルは扱わず、ラベル全体、または "・" で区切った各部分が指定と一致する場合のみ該当とする
+func (f *domainFilter) keep(domains []string) bool {
+	if f == nil {
+		return true
+	}
+	matches := func(list []string) bool {
+		for _, domain := range domains {
+			if slices.Contains(list, domain) {
+				return true
+			}
+			for _, part := range strings.Split(domain, "・") {
+				if slices.Contains(list, part) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if matches(f.exclude) {
+		return false
+	}
+	return len(f.include) == 0 || matches(f.include)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExtractDomains は定義行から分野・用法のラベルが取り出され、文法上の注記は除かれることを検証します。
+func TestExtractDomains(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"ラベルなし", "腫瘍", nil},
+		{"複数のラベル", "《医》《俗》腫瘍", []string{"医", "俗"}},
+		{"文法上の注記", "《the ～》群衆、《複》人々", []string{"複"}},
+		{"英字を含む注記", "《be ～》好きである", nil},
+		{"【変化】の内容は除く", "扉、【変化】《複》doors", nil},
+		{"重複", "《法》訴訟、《法》訴え", []string{"法"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := extractDomains(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestDomainFilter は指定したラベルによって定義行を残すかどうかが決まることを検証します。
+func TestDomainFilter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		include  string
+		exclude  string
+		domains  []string
+		expected bool
+	}{
+		{"指定なし", "", "", []string{"俗"}, true},
+		{"除外に該当", "", "俗,卑", []string{"俗"}, false},
+		{"除外に該当しない", "", "俗,卑", []string{"医"}, true},
+		{"対象に該当", "《医》", "", []string{"医"}, true},
+		{"ラベルのない行は対象外", "医", "", nil, false},
+		{"・で区切った部分", "薬", "", []string{"医・薬"}, true},
+		{"除外が優先", "医", "俗", []string{"医", "俗"}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := newDomainFilter(tc.include, tc.exclude).keep(tc.domains); got != tc.expected {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithDomainFilter は除外した定義行が用例や補足説明とともに取り除かれ、同じ見出し語の残りの行は残ることを検証します。
+func TestParseWithDomainFilter(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■growth {名} : 成長",
+		"■growth {名} : 《医》腫瘍【変化】《複》growths■・a malignant growth : 悪性腫瘍",
+		"◆医学用語",
+		"■growth {名} : 増大",
+		"■stiff {名} : 《俗》死体",
+		"◆俗語",
+		"■door {名} : 扉",
+	}, "\n"))
+
+	entries, stats, err := parseEijiroWithStats(path, ParseOptions{ExcludeDomains: "医,俗"})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Headword != "growth" || entries[1].Headword != "door" {
+		t.Fatalf("エントリが不正です: %+v", entries)
+	}
+	if entries[0].Definition != "{名} 成長\n{名} 増大" {
+		t.Errorf("定義が不正です: %q", entries[0].Definition)
+	}
+	if stats.SkippedLines != 4 || stats.LinkEntries != 0 {
+		t.Errorf("内訳が不正です: %+v", stats)
+	}
+
+	entries, err = parseEijiro(path, ParseOptions{IncludeDomains: "医"})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Headword != "growth" || !reflect.DeepEqual(entries[0].Senses[0].Domains, []string{"医"}) {
+		t.Errorf("エントリが不正です: %+v", entries)
+	}
+	if !strings.Contains(entries[0].Definition, "悪性腫瘍") || strings.Contains(entries[0].Definition, "成長") {
+		t.Errorf("定義が不正です: %q", entries[0].Definition)
+	}
+}
+
+// TestRenderDomainLabels はHTML形式で分野・用法のラベルが装飾用の要素で囲まれることを検証します。
+func TestRenderDomainLabels(t *testing.T) {
+	got := htmlRenderer{}.Render(DictionaryEntry{Headword: "growth", Definition: "{名} 《医》腫瘍、《the ～》"})
+	expected := `{名} <span class="domain">《医》</span>腫瘍、《the ～》`
+	if got != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+// bookSizes は書き出した場合の辞書ファイルの大きさ (バイト数)
+type bookSizes struct {
+	BookName string
+	Idx      int64
+	Dict     int64 // 圧縮前の .dict
+	DictDz   int64 // .dict.dz の見積もり (gzipの最大圧縮で代用する)
+	Syn      int64
+}
+
+// estimateBookSizes はファイルを書き出さずに、辞書を書き出した場合の各ファイルの大きさを求める
+func estimateBookSizes(bookName string, entries []DictionaryEntry, wopts WriteOptions) (bookSizes, error) {
+	renderer, err := newRenderer(wopts)
+	if err != nil {
+		return bookSizes{}, err
+	}
+	data, err := buildStarDictData(entries, renderer)
+	if err != nil {
+		return bookSizes{}, err
+	}
+
+	sizes := bookSizes{BookName: bookName, Idx: int64(len(data.idx)), Dict: int64(len(data.dict))}
+	for _, syn := range data.synonyms {
+		sizes.Syn += int64(len(syn.Word)) + 1 + 4
+	}
+
+	// dictzipはgzip互換の形式のため、同じ内容をgzipで圧縮した大きさをおおよその値とする
+	var counter byteCounter
+	zw, err := gzip.NewWriterLevel(&counter, gzip.BestCompression)
+	if err != nil {
+		return bookSizes{}, err
+	}
+	if _, err := zw.Write(data.dict); err != nil {
+		return bookSizes{}, err
+	}
+	if err := zw.Close(); err != nil {
+		return bookSizes{}, err
+	}
+	sizes.DictDz = counter.n
+	return sizes, nil
+}
+
+// byteCounter は書き込まれたバイト数のみを数える io.Writer
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// reportDryRun は -dry-run 指定時に、変換を最後まで行った場合に書き出す辞書の大きさを求め、統計情報とともに出力する
+// 辞書ファイルに加え、JSONLや見出し語一覧などの追加の出力も書き出さない
+// report はパース結果から集計した統計情報 (parsedStatsReport) で、最終的なエントリの統計情報はここで加える
+func reportDryRun(cfg ConvertConfig, report statsReport, final, examples []DictionaryEntry) error {
+	var sizes []bookSizes
+	estimate := func(bookName string, entries []DictionaryEntry) error {
+		size, err := estimateBookSizes(bookName, entries, cfg.WriteOptions)
+		if err != nil {
+			return errorf("辞書 '%s' の大きさを求められませんでした: %w", bookName, err)
+		}
+		sizes = append(sizes, size)
+		return nil
+	}
+
+	mainEntries := final
+	if cfg.IdiomDict {
+		var idioms []DictionaryEntry
+		mainEntries, idioms = splitIdiomEntries(final)
+		if err := estimate(cfg.BookName+idiomBookSuffix, idioms); err != nil {
+			return err
+		}
+	}
+	if err := estimate(cfg.BookName, mainEntries); err != nil {
+		return err
+	}
+	if cfg.ParseOptions.SplitExamples {
+		if err := estimate(cfg.BookName+examplesBookSuffix, examples); err != nil {
+			return err
+		}
+	}
+	if cfg.ReverseIndex {
+		if err := estimate(cfg.BookName+reverseBookSuffix, buildReverseEntries(final, cfg.ParseOptions.ExpandAlternatives)); err != nil {
+			return err
+		}
+	}
+	if cfg.KatakanaDict {
+		if err := estimate(cfg.BookName+katakanaBookSuffix, buildKatakanaEntries(final)); err != nil {
+			return err
+		}
+	}
+
+	w := cfg.DryRunOutput
+	if w == nil {
+		w = os.Stdout
+	}
+	report.addFinal(final)
+	if err := writeDryRunReport(w, report, sizes); err != nil {
+		return err
+	}
+	logger.Info(tr("試行のため、ファイルは書き出していません。"))
+	return nil
+}
+
+// writeDryRunReport は統計情報と、書き出した場合の辞書ファイルの大きさを表形式で出力する
+func writeDryRunReport(w io.Writer, report statsReport, books []bookSizes) error {
+	if err := writeStatsReport(w, report, "table"); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, tr("辞書\t.idx\t.dict\t.dict.dz (見積もり)\t.syn\t"))
+	for _, book := range books {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t\n", book.BookName, formatBytes(book.Idx), formatBytes(book.Dict), formatBytes(book.DictDz), formatBytes(book.Syn))
+	}
+	return tw.Flush()
+}
+
+// formatBytes はバイト数を読みやすい単位で表す (例: 1536 -> "1.5 KiB")
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", value)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDryRun は試行では何も書き出さず、統計情報と書き出した場合のファイルの大きさが出力されることを検証します。
+func TestDryRun(t *testing.T) {
+	path := writeEijiroTestFile(t, "■door {名} : 扉■・Close the door. : 扉を閉めて。\n■know {動} : 知っている【変化】《動》knows | knew\n")
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	var buf bytes.Buffer
+	_, err := runConversion(context.Background(), ConvertConfig{
+		InputFile:     path,
+		OutputDir:     outputDir,
+		BookName:      "Test",
+		ParseOptions:  ParseOptions{SplitExamples: true},
+		MergeStrategy: MergeConcat,
+		ReverseIndex:  true,
+		DryRun:        true,
+		DryRunOutput:  &buf,
+	})
+	if err != nil {
+		t.Fatalf("runConversionでエラーが発生しました: %v", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("試行なのに出力ディレクトリが作成されています: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"エントリ数 (最終)", "Test ", "Test-examples", "Test" + reverseBookSuffix} {
+		if !strings.Contains(output, want) {
+			t.Errorf("出力に '%s' が含まれていません:\n%s", want, output)
+		}
+	}
+
+	// 書き出した場合と同じ内容から大きさを求めていることを確認する
+	renderer, _ := newRenderer(WriteOptions{})
+	entries, _ := parseEijiro(path, ParseOptions{SplitExamples: true})
+	data, err := buildStarDictData(resolveAndMergeEntries(entries), renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes, err := estimateBookSizes("Test", resolveAndMergeEntries(entries), WriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes.Idx != int64(len(data.idx)) || sizes.Dict != int64(len(data.dict)) || sizes.DictDz == 0 {
+		t.Errorf("ファイルの大きさが不正です: %+v", sizes)
+	}
+}
+
+// TestDryRunWithDerivedOnly は -dry-run と -derived-only を同時に指定するとエラーになることを検証します。
+func TestDryRunWithDerivedOnly(t *testing.T) {
+	_, err := runConversion(context.Background(), ConvertConfig{InputFile: "unused.txt", MergeStrategy: MergeConcat, DryRun: true, DerivedOnly: true})
+	if err == nil {
+		t.Error("エラーになるべきところで、エラーになりませんでした")
+	}
+}
+
+// TestFormatBytes はバイト数が読みやすい単位で表されることを検証します。
+func TestFormatBytes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    int64
+		expected string
+	}{
+		{"1KiB未満", 512, "512 B"},
+		{"KiB", 1536, "1.5 KiB"},
+		{"MiB", 3 * 1024 * 1024, "3.0 MiB"},
+		{"GiB", 5 * 1024 * 1024 * 1024, "5.0 GiB"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatBytes(tc.input); got != tc.expected {
+				t.Errorf("期待値: %s, 実際: %s", tc.expected, got)
+			}
+		})
+	}
+}
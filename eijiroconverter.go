@@ -3,15 +3,22 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/binary"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	// 文字コード変換のためにパッケージを追加
@@ -20,27 +27,50 @@ import (
 )
 
 // DictionaryEntry は一つの辞書エントリを保持する構造体
+// Definition は従来どおりの加工済みの定義文字列で、Senses 以降はパース時に構造化した情報
+// 出力形式ごとの描画では、必要に応じて構造化した情報を利用する
 type DictionaryEntry struct {
 	Headword   string
 	Definition string
+	Examples   []string // 用例 (SplitExamplesが有効な場合のみ、定義とは別に保持する)
+	Keywords   []string // 検索用キーワード (削除したラベルの内容など。.synやJSONLに出力する)
+	Audio      string   // 発音音声のリソースファイル名 (res/ 以下に配置される)
+
+	Senses          []Sense  // 語義の一覧 (■ の1行につき一つ。SplitSenses が有効な場合は番号付きの語義ごとに一つ)
+	Pronunciation   string   // 発音記号 (【発音】)
+	Katakana        string   // カタカナ発音 (【＠】)
+	Level           string   // 単語レベル (【レベル】)
+	Syllabification string   // 分節 (【分節】)
+	Hyphenation     []string // 分節を音節に分けたもの (例: ["tac", "ti", "cal"])
+	CrossRefs       []string // PDICリンク (<→…>) の参照先
+
+	SourceLine    int // 英辞郎ファイル内でエントリが最初に現れた行番号 (1始まり。ファイル以外から作ったエントリは0)
+	FrequencyRank int // 頻度リストでの順位 (1始まり。頻度リストを指定しない場合やリストにない場合は0)
 }
 
 // StarDictInfo は .ifo ファイルに書き込む情報を保持する構造体
 type StarDictInfo struct {
-	BookName    string
-	WordCount   uint32
-	IdxFileSize uint32
-	Author      string
-	Description string
-	Date        string
-	SameTypeSeq string
-	Version     string
+	BookName     string
+	WordCount    uint32
+	IdxFileSize  uint64
+	SynWordCount uint32
+	// IdxOffsetBits は .idx に記録する .dict 内の位置のビット数 (32 または 64)
+	// 64 の場合のみ .ifo に idxoffsetbits として書き出す
+	IdxOffsetBits int
+	Author        string
+	Email         string
+	Website       string
+	Description   string
+	Date          string
+	SameTypeSeq   string
+	Version       string
 }
 
 // 正規表現をコンパイル（一度だけ行い、効率化）
 var entryRegex = regexp.MustCompile(`^■([^:]*?)\s*:(.*)`)
 
 // processDefinitionで利用する正規表現を事前にコンパイル
+// これらは初期化後に変更しないこと (*regexp.Regexp は複数のgoroutineから同時に利用しても安全)
 var (
 	reRuby            = regexp.MustCompile(`｛.*?｝`)
 	rePDICLink        = regexp.MustCompile(`<→.*?>`)
@@ -60,88 +90,902 @@ var (
 
 // ParseOptions はパース時のオプションを保持する構造体
 type ParseOptions struct {
-	StripExamples        bool // 用例 (■・)
-	StripSupplement      bool // 補足説明 (◆)
-	StripRuby            bool // 読み仮名 ({})
-	StripPDICLink        bool // PDICリンク (<→...>)
-	StripPronunciation   bool // 発音記号 (【発音】)
-	StripKatakana        bool // カタカナ発音 (【＠】)
-	StripForms           bool // 変化形 (【変化】)
-	StripLevel           bool // 単語レベル (【レベル】)
-	StripSyllabification bool // 分節 (【分節】)
-	StripOtherLabels     bool // その他のラベル ({名}, 【大学入試】など)を削除
-	SingleWordOnly       bool // 見出語が単一の単語のみ
+	StripExamples        bool   // 用例 (■・)
+	SplitExamples        bool   // 用例を定義から分離し、別の辞書として出力する
+	StripSupplement      bool   // 補足説明 (◆)
+	StripRuby            bool   // 読み仮名 ({})
+	StripPDICLink        bool   // PDICリンク (<→...>)
+	StripPronunciation   bool   // 発音記号 (【発音】)
+	PronunciationIPA     bool   // 発音記号をIPAに変換する (StripPronunciationが無効な場合のみ)
+	StripKatakana        bool   // カタカナ発音 (【＠】)
+	StripForms           bool   // 変化形 (【変化】)
+	StripLevel           bool   // 単語レベル (【レベル】)
+	StripSyllabification bool   // 分節 (【分節】)
+	StripOtherLabels     bool   // その他のラベル ({名}, 【大学入試】など)を削除
+	SingleWordOnly       bool   // 見出語が単一の単語のみ
+	KeepStrippedKeywords bool   // 削除したラベル(【＠】, 【分節】)の内容を検索用キーワードとして残す
+	ExpandAlternatives   bool   // 訳語中の言い換え ([…]) を展開した訳語の一覧を構造化データに加える
+	ExpandBrackets       bool   // 見出し語の括弧 ([…], 〔…〕, (…)) を展開し、表示用の見出し語と検索用キーワードにする
+	PlaceholderKeys      bool   // 成句の見出し語から目的語などの位置を表す記号 (~) を除いた形を検索用キーワードにする
+	GenerateInflections  bool   // 【変化】のない名詞・動詞・形容詞について、規則変化の変化形から原形へのリンクを生成する
+	AbbreviationLinks    bool   // 定義中の【略】に続く略語から、元の見出し語へのリンクを生成する
+	SplitSenses          bool   // 一行に番号付きで並べた語義 (1. …、2. …) を、構造化データの別々の語義に分ける
+	ResolveAliases       bool   // 定義全体が別の見出し語の参照 (＝<→…>) である見出し語に、参照先へのリンクを加える
+	IncludeDomains       string // カンマ区切りの分野・用法のラベル (医,法 など)。いずれかを持つ定義行のみを対象とする
+	ExcludeDomains       string // カンマ区切りの分野・用法のラベル (俗,卑 など)。いずれかを持つ定義行を除外する
+	IncludePOS           string // カンマ区切りの品詞 (noun,verb または 名,他動 など)。いずれかの品詞の定義行のみを対象とする
+	ExcludePOS           string // カンマ区切りの品詞。いずれかの品詞の定義行を除外する
+	Normalize            string // 文字列のUnicode正規化の形式 (NormalizeNFC, NormalizeNFKC。空の場合は正規化しない)
+	HalfwidthASCII       bool   // 全角英数字・記号を半角に変換する
+	HeadwordRange        string // 対象にする見出し語の先頭の文字の範囲 ("a-c" など。空の場合は絞り込まない)
+	Offset               int    // 読み飛ばす先頭の見出し語の数
+	Limit                int    // 対象にする見出し語の最大数 (0の場合は無制限)
+
+	Deadline time.Time // この時刻を過ぎたら、次の見出し語の手前で読み込みを打ち切る (ゼロ値の場合は打ち切らない)
+}
+
+// WriteOptions はStarDictファイル出力時のオプションを保持する構造体
+type WriteOptions struct {
+	HTML                    bool   // 定義をHTML形式(sametypesequence=h)で書き出す
+	ParagraphStyle          string // HTML形式での段落の区切り方 ("br" または "p")
+	Theme                   string // HTML形式で添えるスタイルシートのテーマ ("light" または "dark")
+	AccentColor             string // テーマのアクセントカラー (空の場合はテーマの既定値)
+	AccessiblePronunciation bool   // HTML形式で、発音情報を読み上げ用の要素として定義の前に置く
+	NoCompress              bool   // .dict を圧縮せずに書き出す
+	PhoneticField           bool   // 発音記号を定義本体とは別の 't' の項目として書き出す (sametypesequence=tg など)
+	Hyphenation             bool   // HTML形式で、見出し語の分節 (tac·ti·cal) を定義の前に置く
+	CompressIdx             bool   // .idx をgzipで圧縮した .idx.gz として書き出す
+
+	// .ifo に書き出す辞書の情報 (空の場合は Author と Description のみ既定値を使い、Email と Website は書き出さない)
+	Author      string
+	Email       string
+	Website     string
+	Description string
+
+	// 辞書に記録する作成日時 (.ifo の date、EPUB やアーカイブの日時)
+	// ゼロ値の場合、.ifo の date は変換した日とし、EPUB やアーカイブの日時は固定の日時 (reproducibleEpoch) とする
+	BuildTime time.Time
 }
 
 func main() {
+	// フラグの説明を翻訳するため、フラグの解析より前に表示する言語を決める
+	lang, err := detectLanguage(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	language = lang
+
+	// サブコマンドが指定された場合は、そちらを実行する
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
 	// --- コマンドライン引数の設定 ---
-	inputFile := flag.String("i", "EIJIRO-1448.TXT", "入力する英辞郎ファイル名 (例: EIJIRO-1448.TXT)")
+	inputFile := flag.String("i", "EIJIRO-1448.TXT", "入力する英辞郎ファイル名 (例: EIJIRO-1448.TXT。.zip や .gz の圧縮ファイルも可)")
 	outputDir := flag.String("o", "output_stardict", "出力先ディレクトリ")
 	bookName := flag.String("b", "Eijiro", "辞書の名前")
 
-	// --- パースオプションのフラグ定義 ---
-	stripExamples := flag.Bool("strip-examples", false, "用例(■・)を除外する")
-	stripSupplement := flag.Bool("strip-supplement", false, "補足説明(◆)を除外する")
-	stripRuby := flag.Bool("strip-ruby", false, "読み仮名({…})を削除する")
-	stripPDICLink := flag.Bool("strip-pdic-link", false, "PDICリンク(<→…>)を削除する")
-	stripPronunciation := flag.Bool("strip-pronunciation", false, "発音記号(【発音】…)を削除する")
-	stripKatakana := flag.Bool("strip-katakana", false, "カタカナ発音(【＠】…)を削除する")
-	stripForms := flag.Bool("strip-forms", false, "変化形(【変化】…)を削除する")
-	stripLevel := flag.Bool("strip-level", false, "単語レベル(【レベル】…)を削除する")
-	stripSyllabification := flag.Bool("strip-syllabification", false, "分節(【分節】…)を削除する")
-	stripOtherLabels := flag.Bool("strip-other-labels", false, "品詞({名})やその他のラベル({大学入試})を削除する")
-	singleWordOnly := flag.Bool("single-word-only", false, "見出語が単一の単語からなるもののみを対象とする")
-	minimal := flag.Bool("minimal", false, "すべての追加情報を除外し、最小限の定義のみを対象とする")
-
+	// --- パースオプション・出力オプションのフラグ定義 (lookup サブコマンドと共通) ---
+	parseOptions := registerParseFlags(flag.CommandLine)
+	writeOptions := registerWriteFlags(flag.CommandLine)
+
+	jsonlPath := flag.String("jsonl", "", "エントリをJSONL形式で書き出すファイル名 (空の場合は出力しない)")
+	validateSchema := flag.Bool("validate-schema", false, "JSONL出力の各レコードをスキーマ(schema/entry.schema.json)で検証する")
+	audioDir := flag.String("audio-dir", "", "発音音声ファイル(見出し語.mp3など)を格納したディレクトリ (-html 指定時に定義へ埋め込む)")
+	resourceDir := flag.String("resources", "", "画像や音声などのファイルを格納したディレクトリ。ディレクトリの構成を保ったまま res/ にコピーし、-html の定義から相対パス(例: <img src=\"cat.png\">)で参照できるようにする")
+	resourceDB := flag.Bool("resource-db", false, "res/ のファイル(-audio-dir や -resources で配置したもの)を、StarDict 3.0 のリソースデータベース(res.rifo, res.ridx, res.rdic.dz)にまとめる")
+	exportTransliteration := flag.String("export-transliteration", "", "見出し語・カタカナ発音・IPAの対応表(TSV)を書き出すファイル名 (読み上げソフト向け)")
+	exportKeys := flag.String("export-keys", "", "見出し語と別名の一覧を書き出すファイル名 (入力メソッドや補完エンジン向け)")
+	keysFormat := flag.String("keys-format", "plain", "見出し語一覧の形式 (plain: 1行1語, mozc: Mozcのユーザー辞書形式)")
+	collation := flag.String("collation", CollationByte, "見出し語一覧(-export-keys, -derived-only)の並べ方 (byte: バイト列の順, japanese: 仮名の種類や大文字・小文字を区別しない五十音順)")
+	reverseIndex := flag.Bool("reverse-index", false, "日本語の訳語から英語の見出し語を引く逆引き(和英)辞書を追加で生成する")
+	idiomDict := flag.Bool("idiom-dict", false, "複数の単語からなる見出し語(句動詞、慣用句、連語など)を成句辞書(<辞書名>-idioms)に分け、構成する語(kick the bucket の bucket など)からも引けるようにする")
+	katakanaDict := flag.Bool("katakana-dict", false, "訳語が主にカタカナ語である見出し語を集め、カタカナの訳語から英語の見出し語を引くカタカナ語辞書(<辞書名>-katakana)を追加で生成する")
+	mergeStrategy := flag.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat: 改行でつなぐ, numbered: 語義に番号を付ける, pos: 品詞ごとにまとめる, separate: 語義ごとに別エントリにする, split-pos: 品詞ごとに別エントリにする)")
+	rankSensesFlag := flag.Bool("rank-senses", false, "短く一般的な訳語を前に、専門分野(《医》など)の語義を後ろに並べ替える")
+	fuzzyKeys := flag.Bool("fuzzy-keys", false, "英単語の見出し語に、よくある綴り誤りの形(recieve, accomodateなど)を検索キーとして加える (あいまい検索のない辞書アプリ向け)")
+	relatedWords := flag.Bool("related", false, "語幹を共有する見出し語(happy, happiness, unhappilyなど)を関連語として各エントリに追記する")
+	preserveCase := flag.Bool("preserve-case", false, "見出し語の大文字・小文字を元の表記のまま残す (小文字での検索は.synで引き続き可能)")
+	filterCmd := flag.String("filter-cmd", "", "書き出す前にエントリ(JSONL形式)を標準入力に渡して加工させる外部コマンド (標準出力の内容で置き換える)")
+	maxDuration := flag.Duration("max-duration", 0, "変換の制限時間 (例: 30s)。時間内に読み込めた分だけで辞書を書き出して正常終了する (0の場合は無制限)")
+	strictCounts := flag.Bool("strict-counts", false, "変換の各段階でエントリ数が想定外に増減した場合に、警告ではなくエラーにする")
+	cacheFile := flag.String("cache", "", "パース結果のキャッシュを書き出すファイル名 (-from-cache で再利用できる)")
+	fromCache := flag.String("from-cache", "", "英辞郎ファイルをパースせず、-cache で書き出したキャッシュから読み込む (パースオプションはキャッシュ作成時のものが使われる)")
+	patch := flag.String("patch", "", "-from-cache のキャッシュを作成した版から -i の版への英辞郎ファイルの差分(diff -u の形式)。変更された見出し語のみをパースし直す")
+	watch := flag.Bool("watch", false, "変換の後も入力ファイル(英辞郎ファイル、頻度リスト、JMdictなど)を監視し、変更されるたびに変換し直す (英辞郎ファイルが変わっていない場合はパース結果のキャッシュを使う)")
+	watchInterval := flag.Duration("watch-interval", defaultWatchInterval, "-watch で入力ファイルの変更を確認する間隔")
+	dryRun := flag.Bool("dry-run", false, "パースと定義のまとめまでを行い、統計情報と書き出した場合のファイルの大きさを出力する (ファイルは一切書き出さない)")
+	author := flag.String("author", "", "辞書の作者 (.ifo の author。空の場合は Converted with Go)")
+	email := flag.String("email", "", "辞書の作者の連絡先のメールアドレス (.ifo の email)")
+	website := flag.String("website", "", "辞書のWebサイトのURL (.ifo の website)")
+	description := flag.String("description", "", "辞書の説明 (.ifo の description。改行は<br>として書き出す。空の場合は既定の英語の説明)")
+	compressIdx := flag.Bool("compress-idx", false, "索引ファイルをgzipで圧縮し、.idx.gz として書き出す (大きな辞書で容量を節約できる)")
+	noCompress := flag.Bool("no-compress", false, "定義ファイルを圧縮せず、非圧縮の.dictとして書き出す (dictzipは不要になる)")
+	frequencyListPath := flag.String("frequency-list", "", "単語の頻度リスト(単語<TAB>順位)のファイル名。各エントリに順位を記録し、JSONLに出力する")
+	showFrequency := flag.Bool("show-frequency", false, "頻度リストの順位を定義の末尾に「【頻度】123位」として追記する (-frequency-list が必要)")
+	topN := flag.Int("top-n", 0, "頻度リストの上位N位までの見出し語とその変化形のみを出力する (-frequency-list が必要。0の場合は絞り込まない)")
+	tatoebaPath := flag.String("tatoeba", "", "Tatoebaの英日の対訳文(TSV)のファイル名。用例のないエントリに、見出し語を含む対訳文を「■〔Tatoeba〕」を付けて用例として加える")
+	tatoebaMax := flag.Int("tatoeba-max", 3, "一つのエントリに加えるTatoebaの対訳文の最大数")
+	jmdictPath := flag.String("jmdict", "", "JMdictのXMLファイル(JMdict_e、.gzも可)。-reverse-index の逆引き辞書で、一致する訳語にJMdictの通し番号・読み・英訳を添える (-furigana の読み仮名にも使う)")
+	pitchAccentPath := flag.String("pitch-accent", "", "アクセントのデータ(表記<TAB>読み<TAB>アクセント核の位置のTSV。kanjiumのaccents.txtなど)。逆引き辞書とカタカナ語辞書の見出し語に「【アクセント】にほん［2］中高型」の行を添える")
+	furigana := flag.Bool("furigana", false, "定義の日本語の漢字に、JMdictで読みが一つに定まる語の読み仮名をルビとして振る (-html と -jmdict が必要)")
+	packageFormat := flag.String("package", "", "書き出した辞書を変換の設定を記したREADME.txtとともに一つのアーカイブ(stardict-<辞書名>-<バージョン>.zip など)にまとめる (zip または tar.bz2。空の場合はまとめない)")
+	install := flag.String("install", "", "書き出した辞書を辞書ディレクトリの<辞書名>/にインストールする (user: ~/.stardict/dic など, system: /usr/share/stardict/dic など。以前の版はバックアップする)")
+	headwordRange := flag.String("headword-range", "", "先頭の文字が範囲内にある見出し語のみを変換する (例: a-c, x)。テンプレートの調整や不具合の調査向け")
+	offset := flag.Int("offset", 0, "先頭から指定した数の見出し語を読み飛ばす (-headword-range を指定した場合は範囲内で数える)")
+	limit := flag.Int("limit", 0, "指定した数の見出し語のみを変換し、残りは読み込まない (0の場合は無制限)")
+	format := flag.String("format", FormatStarDict, "辞書の出力形式 (stardict: StarDict形式, epub: 辞書の読み込みに対応していない電子書籍リーダー向けの、目次と相互参照のリンクを備えたEPUB)")
+	buildTime := flag.String("build-time", "", "辞書に記録する作成日時 (RFC 3339 の日時、2006-01-02 の形式の日付、または now)。空の場合は環境変数 SOURCE_DATE_EPOCH を使い、それもない場合は .ifo に変換した日を記録する。指定すると同じ入力から常に同じ内容の辞書を書き出す")
+	manifest := flag.Bool("manifest", false, "書き出したファイルのSHA-256のチェックサムと、入力ファイルのチェックサム、変換の設定を manifest.json に記録する (同じ辞書が得られたかの確認向け)")
+	derivedOnly := flag.Bool("derived-only", false, "定義文を含まない派生データ(見出し語一覧・変化形の参照関係・統計情報)のみを出力する")
+
+	// --- プロファイルのフラグ定義 (bench サブコマンドと共通) ---
+	profileOptions := registerProfileFlags(flag.CommandLine)
+
+	// --- ログのフラグ定義 ---
+	verbose := flag.Bool("v", false, "詳しいログ(見つからないリンク先の一覧など)を表示する")
+	veryVerbose := flag.Bool("vv", false, "-v に加えて、除外・無視した行を一行ずつ表示する")
+	quiet := flag.Bool("quiet", false, "警告とエラーのみを表示する")
+	logJSON := flag.Bool("log-json", false, "ログを1行1件のJSON(event, count などの属性付き)で出力する (ビルドの自動化向け)")
+
+	// --- 完了通知のフラグ定義 ---
+	notifyWebhook := flag.String("notify-webhook", "", "変換の終了時に実行結果(JSON)をPOSTするWebhookのURL")
+	notifySMTP := flag.String("notify-smtp", "", "変換の終了時に実行結果をメールで送るSMTPサーバー (host:port)")
+	notifySMTPUser := flag.String("notify-smtp-user", "", "SMTP認証のユーザー名 (パスワードは環境変数 EIJIRO_SMTP_PASSWORD で指定)")
+	notifyFrom := flag.String("notify-from", "", "通知メールの送信元アドレス")
+	notifyTo := flag.String("notify-to", "", "通知メールの宛先アドレス (カンマ区切りで複数指定可)")
+
+	localizeFlags(flag.CommandLine)
 	flag.Parse()
 
-	isMinimal := *minimal
+	verbosity := 0
+	if *veryVerbose {
+		verbosity = 2
+	} else if *verbose {
+		verbosity = 1
+	}
+	if err := configureLogging(verbosity, *quiet, *logJSON); err != nil {
+		log.Fatal(err)
+	}
 
-	// --- パースオプションの設定 ---
-	opts := ParseOptions{
-		// isMinimalがtrueの場合、個別の指定に関わらず除外/削除する
-		StripExamples:        *stripExamples || isMinimal,
-		StripSupplement:      *stripSupplement || isMinimal,
-		StripRuby:            *stripRuby || isMinimal,
-		StripPDICLink:        *stripPDICLink, // minimalオプションの影響を受けないように変更
-		StripPronunciation:   *stripPronunciation || isMinimal,
-		StripKatakana:        *stripKatakana || isMinimal,
-		StripForms:           *stripForms || isMinimal,
-		StripLevel:           *stripLevel || isMinimal,
-		StripSyllabification: *stripSyllabification || isMinimal,
-		StripOtherLabels:     *stripOtherLabels || isMinimal,
-		// singleWordOnlyは情報の「内容」ではなく「対象」のフィルタリングなので、minimalの対象外とする
-		SingleWordOnly: *singleWordOnly,
+	opts := parseOptions()
+	opts.HeadwordRange = *headwordRange
+	opts.Offset = *offset
+	opts.Limit = *limit
+	wopts := writeOptions()
+	wopts.NoCompress = *noCompress
+	wopts.CompressIdx = *compressIdx
+	wopts.Author = *author
+	wopts.Email = *email
+	wopts.Website = *website
+	wopts.Description = *description
+	if wopts.BuildTime, err = resolveBuildTime(*buildTime, os.Getenv); err != nil {
+		log.Fatal(err)
 	}
 
-	log.Println("変換処理を開始します...")
+	cfg := ConvertConfig{
+		InputFile:             *inputFile,
+		OutputDir:             *outputDir,
+		BookName:              *bookName,
+		Format:                *format,
+		ParseOptions:          opts,
+		WriteOptions:          wopts,
+		AudioDir:              *audioDir,
+		ResourceDir:           *resourceDir,
+		ResourceDB:            *resourceDB,
+		JSONLPath:             *jsonlPath,
+		ValidateSchema:        *validateSchema,
+		ExportKeys:            *exportKeys,
+		ExportTransliteration: *exportTransliteration,
+		KeysFormat:            *keysFormat,
+		ReverseIndex:          *reverseIndex,
+		KatakanaDict:          *katakanaDict,
+		IdiomDict:             *idiomDict,
+		DerivedOnly:           *derivedOnly,
+		PreserveCase:          *preserveCase,
+		MergeStrategy:         *mergeStrategy,
+		RelatedWords:          *relatedWords,
+		FuzzyKeys:             *fuzzyKeys,
+		Furigana:              *furigana,
+		RankSenses:            *rankSensesFlag,
+		MaxDuration:           *maxDuration,
+		StrictCounts:          *strictCounts,
+		CacheFile:             *cacheFile,
+		FromCache:             *fromCache,
+		Patch:                 *patch,
+		DryRun:                *dryRun,
+		FrequencyList:         *frequencyListPath,
+		ShowFrequency:         *showFrequency,
+		TopN:                  *topN,
+		TatoebaFile:           *tatoebaPath,
+		TatoebaMax:            *tatoebaMax,
+		JMdictFile:            *jmdictPath,
+		PitchAccentFile:       *pitchAccentPath,
+		Collation:             *collation,
+		Package:               *packageFormat,
+		BuildFlags:            buildFlags(flag.CommandLine),
+		Install:               *install,
+		Manifest:              *manifest,
+	}
+	if *filterCmd != "" {
+		cfg.Transformers = append(cfg.Transformers, CommandTransformer{Command: *filterCmd})
+	}
 
-	// 出力ディレクトリを作成
-	if err := os.MkdirAll(*outputDir, 0755); err != nil {
-		log.Fatalf("出力ディレクトリの作成に失敗しました: %v", err)
+	notifier := Notifier{
+		WebhookURL: *notifyWebhook,
+		SMTPAddr:   *notifySMTP,
+		SMTPUser:   *notifySMTPUser,
+		From:       *notifyFrom,
+		To:         splitList(*notifyTo),
 	}
 
-	// 1. 英辞郎ファイルをパース（文字コード変換もここで行う）
-	entries, err := parseEijiro(*inputFile, opts)
+	prof := profileOptions()
+	if err := prof.start(); err != nil {
+		exitWithError(err)
+	}
+	// Ctrl-C (SIGINT) や SIGTERM を受け取ったら変換を中断し、書き出し中のファイルを削除する
+	// 2回目のシグナルでは、後片付けを待たずに終了する
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if *watch {
+		if err := watchConversion(ctx, cfg, *watchInterval, runConversion); err != nil {
+			exitWithError(err)
+		}
+		return
+	}
+	summary, err := runConversion(ctx, cfg)
+	if profErr := prof.stop(); profErr != nil {
+		logger.Warn(profErr.Error())
+	}
+	if notifier.Enabled() {
+		if notifyErr := notifier.Notify(summary); notifyErr != nil {
+			logger.Warn(sprintf("完了通知の送信に失敗しました: %v", notifyErr), "event", "notify_failed")
+		}
+	}
 	if err != nil {
-		log.Fatalf("英辞郎ファイルのパースに失敗しました: %v", err)
+		exitWithError(err)
+	}
+}
+
+// registerParseFlags はパースオプションのフラグを fs に登録し、解析後にオプションを組み立てる関数を返す
+// 変換と lookup サブコマンドで同じ指定ができるよう、フラグの定義を共有する
+func registerParseFlags(fs *flag.FlagSet) func() ParseOptions {
+	stripExamples := fs.Bool("strip-examples", false, "用例(■・)を除外する")
+	splitExamples := fs.Bool("split-examples", false, "用例(■・)を本体から分離し、別の辞書(<辞書名>-examples)として出力する")
+	stripSupplement := fs.Bool("strip-supplement", false, "補足説明(◆)を除外する")
+	stripRuby := fs.Bool("strip-ruby", false, "読み仮名({…})を削除する")
+	stripPDICLink := fs.Bool("strip-pdic-link", false, "PDICリンク(<→…>)を削除する")
+	stripPronunciation := fs.Bool("strip-pronunciation", false, "発音記号(【発音】…)を削除する")
+	pronunciationIPA := fs.Bool("ipa", false, "発音記号(【発音】…)を英辞郎の表記からIPAに変換する")
+	stripKatakana := fs.Bool("strip-katakana", false, "カタカナ発音(【＠】…)を削除する")
+	stripForms := fs.Bool("strip-forms", false, "変化形(【変化】…)を削除する")
+	stripLevel := fs.Bool("strip-level", false, "単語レベル(【レベル】…)を削除する")
+	stripSyllabification := fs.Bool("strip-syllabification", false, "分節(【分節】…)を削除する")
+	stripOtherLabels := fs.Bool("strip-other-labels", false, "品詞({名})やその他のラベル({大学入試})を削除する")
+	singleWordOnly := fs.Bool("single-word-only", false, "見出語が単一の単語からなるもののみを対象とする")
+	keepStrippedKeywords := fs.Bool("keep-stripped-keywords", false, "削除したカタカナ発音(【＠】)や分節(【分節】)の内容を検索用キーワードとして残す")
+	expandAlternativesFlag := fs.Bool("expand-alternatives", false, "訳語中の言い換え(追い払う[追い出す]など)を展開し、語義の訳語一覧や逆引きの索引に加える")
+	expandBrackets := fs.Bool("expand-brackets", false, "見出し語の括弧([…]: 直前の語の言い換え, 〔…〕・(…): 省略できる語句)を展開し、括弧を外した表示用の見出し語と、言い換えや省略をした検索用キーワードにする")
+	placeholderKeys := fs.Bool("placeholder-keys", false, "成句の見出し語(account for ~ など)から~を除いた形(account for)を検索用キーワードに加える")
+	generateInflectionsFlag := fs.Bool("generate-inflections", false, "【変化】のない名詞・動詞・形容詞について、規則変化(-s/-es, -ed, -ing, -er/-est)の変化形から原形へのリンクを生成する")
+	abbreviationLinks := fs.Bool("abbreviation-links", false, "定義中の【略】に続く略語(AIDSなど)から、元の見出し語へのリンクを生成する")
+	splitSenses := fs.Bool("split-senses", false, "一行に番号付きで並べた語義(1. 取る、2. 持って行く)を、構造化データ(JSONLの senses など)の別々の語義に分ける")
+	resolveAliases := fs.Bool("resolve-aliases", false, "定義全体が別の見出し語の参照(＝<→color>など)である見出し語に、参照先の定義を加える")
+	includeDomains := fs.String("include-domain", "", "指定した分野・用法のラベル(《医》《法》など)のいずれかを持つ定義行のみを対象とする (カンマ区切り。例: 医,薬)")
+	excludeDomains := fs.String("exclude-domain", "", "指定した分野・用法のラベル(《俗》《卑》など)のいずれかを持つ定義行を除外する (カンマ区切り。例: 俗,卑)")
+	includePOS := fs.String("pos", "", "指定した品詞のいずれかの定義行のみを対象とする (カンマ区切り。noun, verb, adj, adv などの英語の名前、または 名, 他動 などの英辞郎の品詞情報の表記。例: verb)")
+	excludePOS := fs.String("exclude-pos", "", "指定した品詞のいずれかの定義行を除外する (指定の形式は -pos と同じ)")
+	normalize := fs.String("normalize", "", "見出し語・変化形・リンク先・定義の文字列をUnicode正規化する (nfc または nfkc。空の場合は正規化しない)")
+	halfwidthASCII := fs.Bool("halfwidth-ascii", false, "見出し語・変化形・リンク先・定義の全角英数字・記号を半角に変換する")
+	minimal := fs.Bool("minimal", false, "すべての追加情報を除外し、最小限の定義のみを対象とする")
+
+	return func() ParseOptions {
+		isMinimal := *minimal
+
+		return ParseOptions{
+			// isMinimalがtrueの場合、個別の指定に関わらず除外/削除する
+			StripExamples:        *stripExamples || isMinimal,
+			SplitExamples:        *splitExamples,
+			StripSupplement:      *stripSupplement || isMinimal,
+			StripRuby:            *stripRuby || isMinimal,
+			StripPDICLink:        *stripPDICLink, // minimalオプションの影響を受けないように変更
+			StripPronunciation:   *stripPronunciation || isMinimal,
+			PronunciationIPA:     *pronunciationIPA,
+			StripKatakana:        *stripKatakana || isMinimal,
+			StripForms:           *stripForms || isMinimal,
+			StripLevel:           *stripLevel || isMinimal,
+			StripSyllabification: *stripSyllabification || isMinimal,
+			StripOtherLabels:     *stripOtherLabels || isMinimal,
+			// singleWordOnlyは情報の「内容」ではなく「対象」のフィルタリングなので、minimalの対象外とする
+			SingleWordOnly:       *singleWordOnly,
+			KeepStrippedKeywords: *keepStrippedKeywords,
+			ExpandAlternatives:   *expandAlternativesFlag,
+			ExpandBrackets:       *expandBrackets,
+			PlaceholderKeys:      *placeholderKeys,
+			GenerateInflections:  *generateInflectionsFlag,
+			AbbreviationLinks:    *abbreviationLinks,
+			SplitSenses:          *splitSenses,
+			ResolveAliases:       *resolveAliases,
+			IncludeDomains:       *includeDomains,
+			ExcludeDomains:       *excludeDomains,
+			IncludePOS:           *includePOS,
+			ExcludePOS:           *excludePOS,
+			Normalize:            *normalize,
+			HalfwidthASCII:       *halfwidthASCII,
+		}
 	}
-	log.Printf("%d件のエントリを読み込みました。", len(entries))
+}
+
+// registerWriteFlags は出力オプションのフラグを fs に登録し、解析後にオプションを組み立てる関数を返す
+func registerWriteFlags(fs *flag.FlagSet) func() WriteOptions {
+	htmlMode := fs.Bool("html", false, "定義をHTML形式で書き出す")
+	theme := fs.String("theme", "light", "HTML形式で添えるスタイルシートのテーマ (light または dark)")
+	accentColor := fs.String("accent-color", "", "スタイルシートのアクセントカラー (#rrggbb の形式。空の場合はテーマの既定値)")
+	paragraphStyle := fs.String("paragraph", "br", "HTML形式での段落の区切り方 (br: <br>で区切る, p: <p>で囲む)")
+	accessiblePronunciation := fs.Bool("accessible-pronunciation", false, "HTML形式で、発音情報をスクリーンリーダーが読み上げられる要素として定義の前に置く")
+	hyphenation := fs.Bool("hyphenation", false, "HTML形式で、見出し語の分節(【分節】)を tac·ti·cal の形で定義の前に表示する")
+	phoneticField := fs.Bool("phonetic-field", false, "発音記号を定義本体とは別の項目(sametypesequenceの't')として書き出し、辞書アプリが発音記号を別に装飾できるようにする")
+
+	return func() WriteOptions {
+		return WriteOptions{
+			HTML:                    *htmlMode,
+			ParagraphStyle:          *paragraphStyle,
+			Theme:                   *theme,
+			AccentColor:             *accentColor,
+			AccessiblePronunciation: *accessiblePronunciation,
+			PhoneticField:           *phoneticField,
+			Hyphenation:             *hyphenation,
+		}
+	}
+}
 
-	// ファイル名からバージョンを抽出
-	version := extractVersionFromFilename(*inputFile)
-	log.Printf("辞書バージョンを '%s' に設定します。", version)
+// ConvertConfig は変換処理全体の設定を保持する構造体
+type ConvertConfig struct {
+	InputFile             string
+	OutputDir             string
+	BookName              string
+	Format                string // 辞書の出力形式 (FormatStarDict など。空の場合は StarDict形式)
+	ParseOptions          ParseOptions
+	WriteOptions          WriteOptions
+	AudioDir              string // 発音音声ファイルのディレクトリ (空の場合は対応付けない)
+	ResourceDir           string // res/ にコピーする画像や音声などのファイルのディレクトリ (空の場合はコピーしない)
+	ResourceDB            bool   // res/ のファイルをリソースデータベース (res.rifo など) にまとめる
+	JSONLPath             string // JSONLの出力先 (空の場合は出力しない)
+	ValidateSchema        bool
+	ExportKeys            string // 見出し語一覧の出力先 (空の場合は出力しない)
+	KeysFormat            string
+	ExportTransliteration string // 発音の対応表の出力先 (空の場合は出力しない)
+	ReverseIndex          bool
+	KatakanaDict          bool               // カタカナ語辞書 (<辞書名>-katakana) を追加で生成する
+	IdiomDict             bool               // 成句の見出し語を成句辞書 (<辞書名>-idioms) に分ける
+	DerivedOnly           bool               // 定義文を含まない派生データのみを出力する
+	PreserveCase          bool               // 見出し語の元の表記を残す
+	MergeStrategy         string             // 同じ見出し語の定義のまとめ方 (MergeConcat など)
+	RelatedWords          bool               // 語幹を共有する見出し語を関連語として追記する
+	FuzzyKeys             bool               // 見出し語の綴り誤りの形を検索用キーワードとして加える
+	Furigana              bool               // 定義の漢字に JMdict の読みを読み仮名として加える (HTML形式のみ)
+	RankSenses            bool               // 語義を有用と思われる順に並べ替える
+	StrictCounts          bool               // 各段階のエントリ数が想定と異なる場合にエラーにする (無効な場合は警告のみ)
+	MaxDuration           time.Duration      // 変換全体の制限時間 (0の場合は無制限)
+	Transformers          []EntryTransformer // 書き出す前にエントリを加工する処理 (登録順に適用する)
+	CacheFile             string             // パース結果のキャッシュの出力先 (空の場合は出力しない)
+	FromCache             string             // パースを省略して読み込むキャッシュ (空の場合は英辞郎ファイルをパースする)
+	Patch                 string             // キャッシュに適用する英辞郎ファイルの差分 (diff -u の形式。-i に新版を指定する)
+	DryRun                bool               // 何も書き出さず、統計情報と書き出した場合のファイルの大きさのみを出力する
+	DryRunOutput          io.Writer          // DryRun の結果の出力先 (nilの場合は標準出力)
+	FrequencyList         string             // 単語の頻度リストのファイル (空の場合は順位を記録しない)
+	ShowFrequency         bool               // 頻度リストの順位を定義に追記する
+	TopN                  int                // 頻度リストの上位N位までの見出し語に絞り込む (0の場合は絞り込まない)
+	TatoebaFile           string             // 用例のないエントリに加える Tatoeba の対訳文のファイル (空の場合は加えない)
+	TatoebaMax            int                // 一つのエントリに加える Tatoeba の対訳文の最大数
+	JMdictFile            string             // 逆引き辞書の訳語に添える JMdict のファイル (空の場合は添えない)
+	PitchAccentFile       string             // 逆引き辞書とカタカナ語辞書の見出し語に添えるアクセントのデータ (空の場合は添えない)
+	Collation             string             // 見出し語一覧などの並べ方 (CollationByte など。.idx の並び順には影響しない)
+	Package               string             // 書き出した辞書をまとめるアーカイブの形式 (PackageZip など。空の場合はまとめない)
+	BuildFlags            []string           // アーカイブの README.txt に記録する、コマンドラインで指定したフラグ
+	Install               string             // 書き出した辞書をインストールする範囲 (InstallUser など。空の場合はインストールしない)
+	Manifest              bool               // 書き出したファイルのチェックサムと変換の設定を manifest.json に記録する
+}
+
+// runConversion は設定に従って英辞郎ファイルを変換し、実行結果の概要を返す
+// 失敗した場合も、それまでの経過を記録した概要を返す
+// ctx が取り消された場合は各段階の区切りで変換を中断する
+// 出力するファイルは一時的な名前で書き出し、成功した場合にのみ本来の名前に置き換える (中断や失敗の場合は削除する)
+func runConversion(ctx context.Context, cfg ConvertConfig) (summary RunSummary, err error) {
+	opts := cfg.ParseOptions
+	wopts := cfg.WriteOptions
+
+	summary = RunSummary{
+		Input:     cfg.InputFile,
+		OutputDir: cfg.OutputDir,
+		BookName:  cfg.BookName,
+		StartedAt: time.Now(),
+	}
+	defer func() {
+		summary.finish(err)
+	}()
+
+	// 制限時間がある場合は、その半分を読み込みに使い、残りを書き出しに充てる
+	// 読み込みを打ち切っても、それまでのエントリで完全な辞書を書き出す
+	// 書き出しは残りの時間を過ぎても中断しない (途中で止めると辞書が残らないため)
+	if cfg.MaxDuration > 0 {
+		opts.Deadline = summary.StartedAt.Add(cfg.MaxDuration / 2)
+	}
+
+	// 時間のかかるパースの前に、指定の誤りを検出しておく
+	if err := validateMergeStrategy(cfg.MergeStrategy); err != nil {
+		return summary, err
+	}
+	if err := validateCollation(cfg.Collation); err != nil {
+		return summary, err
+	}
+	if cfg.Patch != "" && cfg.FromCache == "" {
+		return summary, errorf("-patch は -from-cache と同時に指定してください")
+	}
+	if cfg.DryRun && cfg.DerivedOnly {
+		return summary, errorf("-dry-run と -derived-only は同時に指定できません")
+	}
+	if err := validatePackageFormat(cfg.Package); err != nil {
+		return summary, err
+	}
+	if cfg.Package != "" && (cfg.DryRun || cfg.DerivedOnly) {
+		return summary, errorf("-package は -dry-run や -derived-only と同時に指定できません")
+	}
+	if err := validateInstallScope(cfg.Install); err != nil {
+		return summary, err
+	}
+	if err := validateFormat(cfg.Format); err != nil {
+		return summary, err
+	}
+	if cfg.Format == FormatEPUB && (cfg.DryRun || cfg.Package != "" || cfg.Install != "") {
+		return summary, errorf("-format epub は -dry-run、-package、-install と同時に指定できません (これらはStarDict形式の辞書が対象です)")
+	}
+	if cfg.Install != "" && (cfg.DryRun || cfg.DerivedOnly) {
+		return summary, errorf("-install は -dry-run や -derived-only と同時に指定できません")
+	}
+	var freqList frequencyList
+	if cfg.FrequencyList != "" {
+		if freqList, err = loadFrequencyList(cfg.FrequencyList); err != nil {
+			return summary, errorf("頻度リストの読み込みに失敗しました: %w", err)
+		}
+	} else if cfg.TopN > 0 || cfg.ShowFrequency {
+		return summary, errorf("-top-n と -show-frequency には -frequency-list の指定が必要です")
+	}
+	var tatoeba *tatoebaIndex
+	if cfg.TatoebaFile != "" {
+		if cfg.TatoebaMax < 1 {
+			return summary, errorf("-tatoeba-max には1以上の値を指定してください: %d", cfg.TatoebaMax)
+		}
+		pairs, err := loadTatoebaPairs(cfg.TatoebaFile)
+		if err != nil {
+			return summary, errorf("Tatoebaの対訳文の読み込みに失敗しました: %w", err)
+		}
+		tatoeba = newTatoebaIndex(pairs)
+		logger.Info(sprintf("Tatoebaの対訳文を%d件読み込みました。", len(pairs)))
+	}
+	if cfg.IdiomDict && cfg.ParseOptions.SingleWordOnly {
+		return summary, errorf("-idiom-dict は -single-word-only と同時に指定できません")
+	}
+	var jmdict jmdictIndex
+	if cfg.Furigana && cfg.JMdictFile == "" {
+		return summary, errorf("-furigana には -jmdict の指定が必要です")
+	}
+	if cfg.JMdictFile != "" {
+		if !cfg.ReverseIndex && !cfg.Furigana {
+			return summary, errorf("-jmdict は -reverse-index または -furigana と同時に指定してください")
+		}
+		if jmdict, err = loadJMdict(cfg.JMdictFile); err != nil {
+			return summary, errorf("JMdictの読み込みに失敗しました: %w", err)
+		}
+		logger.Info(sprintf("JMdictから%d件の表記・読みを読み込みました。", len(jmdict)))
+	}
+
+	var pitchAccents pitchAccentIndex
+	if cfg.PitchAccentFile != "" {
+		if !cfg.ReverseIndex && !cfg.KatakanaDict {
+			return summary, errorf("-pitch-accent は -reverse-index または -katakana-dict と同時に指定してください")
+		}
+		if pitchAccents, err = loadPitchAccents(cfg.PitchAccentFile); err != nil {
+			return summary, errorf("アクセントのデータの読み込みに失敗しました: %w", err)
+		}
+		logger.Info(sprintf("%d件の表記・読みのアクセントを読み込みました。", len(pitchAccents)))
+	}
+
+	logger.Info(tr("変換処理を開始します..."), "event", "start", "input", cfg.InputFile)
+
+	// 出力ディレクトリを作成し、書き出し中のファイルを置く一時ディレクトリを用意する
+	// 以降、出力先ディレクトリに置くファイルは outputDir に書き出す
+	outputs := &pendingOutputs{}
+	defer func() {
+		if err != nil {
+			outputs.abort()
+		}
+	}()
+	outputDir := cfg.OutputDir
+	if !cfg.DryRun {
+		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+			return summary, errorf("出力ディレクトリの作成に失敗しました: %w", err)
+		}
+		if outputDir, err = outputs.stage(cfg.OutputDir); err != nil {
+			return summary, err
+		}
+	}
+
+	// 各段階のエントリ数を記録し、想定外の増減を検出する
+	ledger := &entryLedger{strict: cfg.StrictCounts}
+	defer func() {
+		summary.Phases = ledger.phases
+	}()
+
+	// 1. 英辞郎ファイルをパース（文字コード変換もここで行う）
+	// キャッシュを指定した場合は、パースを省略してキャッシュから読み込む
+	var entries []DictionaryEntry
+	var stats ParseStats
+	if cfg.FromCache != "" {
+		cache, err := readParseCache(cfg.FromCache)
+		if err != nil {
+			return summary, errorf("パース結果のキャッシュの読み込みに失敗しました: %w", err)
+		}
+		opts.Deadline = time.Time{}
+		// 英辞郎ファイルの差分を指定した場合は、変更された見出し語のみを新版からパースし直す
+		if cfg.Patch != "" {
+			var changed int
+			if cache, changed, err = applySourcePatch(ctx, cache, cfg.Patch, cfg.InputFile); err != nil {
+				return summary, errorf("差分の適用に失敗しました: %w", err)
+			}
+			logger.Info(sprintf("差分で変更された%d件の見出し語を %s からパースし直しました。", changed, cfg.InputFile))
+			if cfg.CacheFile != "" {
+				if err := writeParseCache(cfg.CacheFile, cache); err != nil {
+					return summary, errorf("パース結果のキャッシュの書き込みに失敗しました: %w", err)
+				}
+				logger.Info(sprintf("パース結果のキャッシュを書き出しました: %s", cfg.CacheFile))
+			}
+		}
+		if cache.Options != opts {
+			logger.Warn(tr("パースオプションの指定はキャッシュの作成時と異なりますが、キャッシュ作成時のオプションでパースした結果を使います。"))
+		}
+		entries, stats, opts = cache.Entries, cache.Stats, cache.Options
+		cfg.InputFile = cache.Input
+		summary.Input = cache.Input
+		logger.Info(sprintf("パース結果をキャッシュから読み込みました: %s (入力: %s)", cfg.FromCache, cache.Input))
+	} else {
+		entries, stats, err = parseEijiroContext(ctx, cfg.InputFile, opts)
+		if err != nil {
+			return summary, errorf("英辞郎ファイルのパースに失敗しました: %w", err)
+		}
+		if cfg.CacheFile != "" {
+			if stats.Truncated {
+				logger.Warn(tr("読み込みを途中で打ち切ったため、パース結果のキャッシュは書き出しません。"))
+			} else if err := writeParseCache(cfg.CacheFile, parseCache{Input: cfg.InputFile, Options: opts, Stats: stats, Entries: entries}); err != nil {
+				return summary, errorf("パース結果のキャッシュの書き込みに失敗しました: %w", err)
+			} else {
+				logger.Info(sprintf("パース結果のキャッシュを書き出しました: %s", cfg.CacheFile))
+			}
+		}
+	}
+	if err := checkCanceled(ctx); err != nil {
+		return summary, err
+	}
+	summary.ParsedEntries = len(entries)
+	logger.Info(sprintf("%d件のエントリを読み込みました。", len(entries)), "event", "parsed", "entries", len(entries))
+	if err := ledger.checkParseStats(stats, entries); err != nil {
+		return summary, err
+	}
+
+	// 頻度リストの上位の見出し語のみに絞り込む（オプションが有効な場合）
+	if cfg.TopN > 0 {
+		var dropped int
+		entries, dropped = trimToTopN(entries, freqList, cfg.TopN)
+		logger.Info(sprintf("頻度リストの上位%d位までの見出し語に絞り込み、%d件のエントリが残りました。", cfg.TopN, len(entries)))
+		ledger.drop(dropped)
+		if err := ledger.check("頻度による絞り込み", len(entries)); err != nil {
+			return summary, err
+		}
+	}
+
+	// ファイル名からバージョンを抽出 (圧縮ファイルの場合は、展開して読み込んだファイルの名前から)
+	version := extractVersionFromFilename(inputName(cfg.InputFile))
+	summary.Version = version
+	logger.Info(sprintf("辞書バージョンを '%s' に設定します。", version))
+
+	// 派生データのみを出力する場合は、定義文を含むファイルを一切書き出さずに終了する
+	if cfg.DerivedOnly {
+		if err := writeDerivedArtifacts(outputDir, entries, cfg.Collation); err != nil {
+			return summary, errorf("派生データの書き込みに失敗しました: %w", err)
+		}
+		if err := outputs.commit(); err != nil {
+			return summary, err
+		}
+		logger.Info(sprintf("派生データのみを書き出しました。出力先: %s", cfg.OutputDir), "event", "done", "output", cfg.OutputDir)
+		return summary, nil
+	}
+
+	// 用例を分離する場合は、マージで失われる前に用例辞書のエントリを作成しておく
+	var exampleEntries []DictionaryEntry
+	if opts.SplitExamples {
+		exampleEntries = buildExampleEntries(entries)
+		logger.Info(sprintf("%d件の見出し語から用例を分離しました。", len(exampleEntries)))
+	}
 
 	// 2. 変化形の参照を解決し、定義をマージする
-	finalEntries := resolveAndMergeEntries(entries)
+	// 以降はパース結果を使わないため、パース結果と同じ領域で参照を解決し、エントリを二重に保持しないようにする
+	// パース結果から求める情報 (重複の数、見出し語の元の表記、試行の統計情報) は先に集めておく
+	var displayForms map[string]string
+	if cfg.PreserveCase {
+		displayForms = headwordDisplayForms(entries)
+	}
+	var dryRunReport statsReport
+	if cfg.DryRun {
+		dryRunReport = parsedStatsReport(cfg.InputFile, entries, stats)
+	}
+	finalEntries, merged := mergeEntriesInPlace(entries)
+	entries = nil
+	if err := checkCanceled(ctx); err != nil {
+		return summary, err
+	}
+	summary.FinalEntries = len(finalEntries)
+	if merged.Discarded > 0 {
+		logger.Warn(sprintf("既出の見出し語と重複する(大文字・小文字のみ異なるものを含む)%d件の定義は、最初の見出し語の定義のみが使われます。", merged.Discarded), "event", "duplicate_headwords", "count", merged.Discarded)
+	}
+	ledger.drop(merged.Links + merged.Discarded)
+	if err := ledger.check("参照の解決", len(finalEntries)); err != nil {
+		return summary, err
+	}
+	if cfg.PreserveCase {
+		applyHeadwordCase(finalEntries, displayForms)
+	}
+	if tatoeba != nil {
+		attached := attachTatoebaExamples(finalEntries, tatoeba, cfg.TatoebaMax)
+		logger.Info(sprintf("用例のない%d件の見出し語に、Tatoebaの対訳文を用例として加えました。", attached))
+	}
+	if freqList != nil {
+		annotated := annotateFrequency(finalEntries, freqList, cfg.ShowFrequency)
+		logger.Info(sprintf("%d件の見出し語に頻度リストの順位を記録しました。", annotated))
+	}
+	if cfg.RankSenses {
+		rankSenses(finalEntries)
+	}
+	if cfg.RelatedWords {
+		addRelatedWords(finalEntries)
+	}
+	if cfg.FuzzyKeys {
+		added := addFuzzyKeys(finalEntries)
+		logger.Info(sprintf("綴り誤りの検索キーを%d件加えました。", added))
+	}
+	if cfg.Furigana {
+		if !wopts.HTML {
+			logger.Warn(tr("読み仮名は -html 指定時のみルビとして表示されるため、-furigana を無視します。"))
+		} else {
+			annotated := addFuriganaToEntries(finalEntries, newFuriganaDict(jmdict))
+			logger.Info(sprintf("%d件の見出し語の定義に読み仮名を加えました。", annotated))
+		}
+	}
+	finalEntries = applyMergeStrategy(finalEntries, cfg.MergeStrategy)
+	// 語義ごと・品詞ごとに別エントリにする場合は、同じ見出し語の2件目以降を加えたエントリとして数える
+	// 元のエントリは少なくとも1件ずつ残るため、見出し語の種類数は変わらない
+	if mergeChangesEntryCount(cfg.MergeStrategy) {
+		ledger.add(len(finalEntries) - countDistinctHeadwords(finalEntries))
+	}
+	if err := ledger.check("定義のまとめ", len(finalEntries)); err != nil {
+		return summary, err
+	}
+
+	// 利用者が登録した加工処理を適用する
+	// 加工処理はエントリを自由に加えたり除いたりできるため、前後の件数の差をそのまま増減として届け出る
+	if len(cfg.Transformers) > 0 {
+		before := len(finalEntries)
+		finalEntries, err = applyTransformers(finalEntries, cfg.Transformers)
+		if err != nil {
+			return summary, errorf("エントリの加工に失敗しました: %w", err)
+		}
+		summary.FinalEntries = len(finalEntries)
+		logger.Info(sprintf("加工後のエントリは%d件です。", len(finalEntries)))
+		if n := len(finalEntries) - before; n > 0 {
+			ledger.add(n)
+		} else {
+			ledger.drop(-n)
+		}
+		if err := ledger.check("加工", len(finalEntries)); err != nil {
+			return summary, err
+		}
+	}
+
+	if err := checkCanceled(ctx); err != nil {
+		return summary, err
+	}
+
+	// 試行の場合は、書き出すはずだった辞書の大きさを求めて終了する
+	if cfg.DryRun {
+		return summary, reportDryRun(cfg, dryRunReport, finalEntries, exampleEntries)
+	}
+
+	// 発音音声を見出し語に対応付け、res/ ディレクトリに配置する（オプションが有効な場合）
+	if cfg.AudioDir != "" {
+		if !wopts.HTML || cfg.Format == FormatEPUB {
+			logger.Warn(tr("発音音声へのリンクはStarDict形式で -html 指定時のみ定義に埋め込まれるため、-audio-dir を無視します。"))
+		} else {
+			linked, err := attachAudioFiles(finalEntries, cfg.AudioDir, outputDir)
+			if err != nil {
+				return summary, errorf("発音音声ファイルの配置に失敗しました: %w", err)
+			}
+			logger.Info(sprintf("%d件の見出し語に発音音声を対応付けました。", linked))
+		}
+	}
+
+	// 画像などのリソースファイルを res/ ディレクトリに配置する（オプションが有効な場合）
+	if cfg.ResourceDir != "" {
+		if !wopts.HTML || cfg.Format == FormatEPUB {
+			logger.Warn(tr("リソースファイルはStarDict形式で -html 指定時のみ定義から参照できるため、-resources を無視します。"))
+		} else {
+			copied, err := copyResources(cfg.ResourceDir, outputDir)
+			if err != nil {
+				return summary, errorf("リソースファイルの配置に失敗しました: %w", err)
+			}
+			logger.Info(sprintf("%d件のリソースファイルを配置しました。", copied))
+		}
+	}
+
+	// res/ ディレクトリのファイルをリソースデータベースにまとめる（オプションが有効な場合）
+	if cfg.ResourceDB {
+		packed, err := writeResourceDatabase(outputDir, wopts.NoCompress, fileTimestamp(wopts.BuildTime))
+		if err != nil {
+			return summary, errorf("リソースデータベースの書き込みに失敗しました: %w", err)
+		}
+		if packed == 0 {
+			logger.Warn(tr("res/ に配置したファイルがないため、リソースデータベースを書き出しませんでした。"))
+		} else {
+			logger.Info(sprintf("%d件のリソースファイルをリソースデータベース(%s)にまとめました。", packed, resourceInfoName))
+		}
+	}
 
-	// 3. StarDict ファイルを生成
-	err = writeStarDictFiles(*outputDir, *bookName, version, finalEntries)
+	// 成句を成句辞書に分ける（オプションが有効な場合）
+	// JSONLや逆引き辞書などの追加の出力は、分ける前のすべてのエントリから作る
+	// エントリ数の確認には、書き出したエントリ数ではなく、書き出した辞書の見出し語の数を使う
+	mainEntries := finalEntries
+	written := 0
+	if cfg.IdiomDict {
+		var idiomEntries []DictionaryEntry
+		mainEntries, idiomEntries = splitIdiomEntries(finalEntries)
+		logger.Info(sprintf("%d件の成句の見出し語を成句辞書に分けました。", len(idiomEntries)))
+		if err := writeDictionary(cfg.Format, outputDir, cfg.BookName+idiomBookSuffix, version, idiomEntries, wopts); err != nil {
+			return summary, errorf("成句辞書の書き込みに失敗しました: %w", err)
+		}
+		n, err := writtenEntryCount(cfg.Format, outputDir, cfg.BookName+idiomBookSuffix, idiomEntries)
+		if err != nil {
+			return summary, err
+		}
+		written += n
+	}
+
+	// 3. StarDict ファイル (-format epub の場合は EPUB) を生成
+	if err := writeDictionary(cfg.Format, outputDir, cfg.BookName, version, mainEntries, wopts); err != nil {
+		return summary, errorf("StarDictファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := checkCanceled(ctx); err != nil {
+		return summary, err
+	}
+	n, err := writtenEntryCount(cfg.Format, outputDir, cfg.BookName, mainEntries)
 	if err != nil {
-		log.Fatalf("StarDictファイルの書き込みに失敗しました: %v", err)
+		return summary, err
+	}
+	written += n
+	if err := ledger.check("書き出し", written); err != nil {
+		return summary, err
+	}
+	logger.Info(sprintf("エントリ数の推移: %s", ledger), "event", "entry_counts", "phases", ledger.phases)
+
+	// 用例辞書を書き出す（オプションが有効な場合）
+	if opts.SplitExamples {
+		if err := writeDictionary(cfg.Format, outputDir, cfg.BookName+examplesBookSuffix, version, exampleEntries, wopts); err != nil {
+			return summary, errorf("用例辞書の書き込みに失敗しました: %w", err)
+		}
+	}
+
+	// 4. JSONL形式で書き出す（オプションが有効な場合）
+	if cfg.JSONLPath != "" {
+		if err := writeJSONLFile(outputs.file(cfg.JSONLPath), finalEntries, cfg.ValidateSchema); err != nil {
+			return summary, errorf("JSONLファイルの書き込みに失敗しました: %w", err)
+		}
+		logger.Info(sprintf("JSONLファイルを書き出しました: %s", cfg.JSONLPath))
+	}
+
+	// 見出し語と別名の一覧を書き出す（オプションが有効な場合）
+	if cfg.ExportKeys != "" {
+		if err := writeKeysFile(outputs.file(cfg.ExportKeys), cfg.KeysFormat, cfg.Collation, finalEntries); err != nil {
+			return summary, errorf("見出し語一覧の書き込みに失敗しました: %w", err)
+		}
+		logger.Info(sprintf("見出し語一覧を書き出しました: %s", cfg.ExportKeys))
+	}
+
+	// 発音の対応表を書き出す（オプションが有効な場合）
+	if cfg.ExportTransliteration != "" {
+		if err := writeTransliterationFile(outputs.file(cfg.ExportTransliteration), finalEntries); err != nil {
+			return summary, errorf("発音の対応表の書き込みに失敗しました: %w", err)
+		}
+		logger.Info(sprintf("発音の対応表を書き出しました: %s", cfg.ExportTransliteration))
 	}
 
-	log.Printf("処理が完了しました。出力先: %s", *outputDir)
+	// 5. 逆引き(和英)辞書を生成（オプションが有効な場合）
+	if cfg.ReverseIndex {
+		reverseEntries := buildReverseEntries(finalEntries, opts.ExpandAlternatives)
+		logger.Info(sprintf("逆引き辞書のエントリを%d件生成しました。", len(reverseEntries)))
+		if jmdict != nil {
+			matched := attachJMdict(reverseEntries, jmdict)
+			logger.Info(sprintf("逆引き辞書の%d件の見出し語にJMdictの情報を添えました。", matched))
+		}
+		if pitchAccents != nil {
+			matched := attachPitchAccents(reverseEntries, pitchAccents)
+			logger.Info(sprintf("逆引き辞書の%d件の見出し語にアクセントを添えました。", matched))
+		}
+		reverseBook := cfg.BookName + reverseBookSuffix
+		if err := writeDictionary(cfg.Format, outputDir, reverseBook, version, reverseEntries, wopts); err != nil {
+			return summary, errorf("逆引き辞書の書き込みに失敗しました: %w", err)
+		}
+	}
+
+	// カタカナ語辞書を生成（オプションが有効な場合）
+	if cfg.KatakanaDict {
+		katakanaEntries := buildKatakanaEntries(finalEntries)
+		logger.Info(sprintf("カタカナ語辞書のエントリを%d件生成しました。", len(katakanaEntries)))
+		if pitchAccents != nil {
+			matched := attachPitchAccents(katakanaEntries, pitchAccents)
+			logger.Info(sprintf("カタカナ語辞書の%d件の見出し語にアクセントを添えました。", matched))
+		}
+		if err := writeDictionary(cfg.Format, outputDir, cfg.BookName+katakanaBookSuffix, version, katakanaEntries, wopts); err != nil {
+			return summary, errorf("カタカナ語辞書の書き込みに失敗しました: %w", err)
+		}
+	}
+
+	// 書き出したファイルのチェックサムを記録する（オプションが有効な場合）
+	// アーカイブやインストール先にも含めるため、それらより前に書き出す
+	if cfg.Manifest {
+		manifest, err := newBuildManifest(outputDir, cfg, version)
+		if err != nil {
+			return summary, err
+		}
+		if err := writeManifestFile(filepath.Join(outputDir, manifestFileName), manifest); err != nil {
+			return summary, errorf("%s の書き込みに失敗: %w", manifestFileName, err)
+		}
+		logger.Info(sprintf("%d件のファイルのチェックサムを %s に記録しました。", len(manifest.Files), manifestFileName))
+	}
+
+	// 書き出した辞書を一つのアーカイブにまとめる（オプションが有効な場合）
+	if cfg.Package != "" {
+		name := packageName(cfg.BookName, version)
+		archivePath := filepath.Join(cfg.OutputDir, name+"."+cfg.Package)
+		readme := func(files []packageFile) []byte { return packageReadme(cfg, version, files) }
+		if err := writePackage(outputDir, outputs.file(archivePath), cfg.Package, name, fileTimestamp(wopts.BuildTime), readme); err != nil {
+			return summary, errorf("アーカイブの書き込みに失敗しました: %w", err)
+		}
+		logger.Info(sprintf("辞書をアーカイブにまとめました: %s", archivePath), "event", "packaged", "path", archivePath)
+	}
+
+	// すべて書き出せた場合にのみ、本来の名前に置き換える
+	if err := checkCanceled(ctx); err != nil {
+		return summary, err
+	}
+	// 辞書ディレクトリにインストールする（オプションが有効な場合）
+	if cfg.Install != "" {
+		dest, err := installDictionaries(outputDir, cfg.Install, cfg.BookName)
+		if err != nil {
+			return summary, err
+		}
+		logger.Info(sprintf("辞書を %s にインストールしました。", dest), "event", "installed", "path", dest)
+	}
+	if err := outputs.commit(); err != nil {
+		return summary, err
+	}
+	logger.Info(sprintf("処理が完了しました。出力先: %s", cfg.OutputDir), "event", "done", "output", cfg.OutputDir)
+	return summary, nil
 }
 
 // extractVersionFromFilename はファイル名からバージョン情報を抽出する
@@ -165,74 +1009,180 @@ func extractVersionFromFilename(filename string) string {
 }
 
 // resolveAndMergeEntries はパースされたエントリを受け取り、変化形のリンクを解決して定義をマージする
+// 引数のエントリは変更せず、新しいスライスを返すため、同じ入力を複数のgoroutineから利用できる
+// 返すエントリは語義などのスライスの要素を引数のエントリと共有する (容量は切り詰めるため、追記は引数のエントリに影響しない)
+// この後の加工のうち、スライスの要素を書き換えるもの (rankSenses の並べ替え) は、書き換える前にスライスを複製する
 func resolveAndMergeEntries(entries []DictionaryEntry) []DictionaryEntry {
-	log.Println("変化形の参照を解決しています...")
+	// 全エントリのスライスを複製するとメモリの使用量が倍になるため、容量のみを切り詰め、
+	// 後続の追記で呼び出し元のエントリを書き換えないようにする
+	clipped := make([]DictionaryEntry, len(entries))
+	for i, entry := range entries {
+		clipped[i] = clipEntry(entry)
+	}
+	finalEntries, _ := mergeEntriesInPlace(clipped)
+	return finalEntries
+}
+
+// mergeCounts は参照の解決で、既にある見出し語のエントリにまとめたため残らなかったエントリの数
+type mergeCounts struct {
+	Links     int // リンクのエントリ (リンク情報を既にあるエントリの定義に追記したもの、または既にリンクがあるため使わなかったもの)
+	Discarded int // 見出し語が既出のもの (大文字・小文字のみ異なるものを含む) と重複するため、定義が使われないエントリ
+}
 
-	// 1. 全ての定義をマップに集約する（キーは小文字に統一）
-	mergedEntries := make(map[string]string)
+// mergeEntriesInPlace は resolveAndMergeEntries と同じく参照を解決するが、引数のスライスの領域を再利用して結果を書き込む
+// 新しいスライスを確保しないため、パース結果を以降で使わない変換ではエントリを二重に保持せずに済む
+// 呼び出した後は、引数のスライスを使ってはならない
+// 既にあるエントリにまとめたエントリの数を、エントリ数の確認のために返す
+func mergeEntriesInPlace(entries []DictionaryEntry) ([]DictionaryEntry, mergeCounts) {
+	logger.Info(tr("変化形の参照を解決しています..."))
+
+	// 1. 全ての定義を見出し語ごとに集約する（キーは小文字に統一）
+	// エントリは最初に現れた順に前から詰めて並べ、マップには位置のみを記録して、エントリの複製を作らないようにする
+	// 書き込む位置は読み込む位置より後ろにならないため、未処理のエントリを上書きすることはない
+	finalEntries := entries[:0]
+	var merged mergeCounts
+	positions := make(map[string]int, len(entries))
 	for _, entry := range entries {
 		key := strings.ToLower(entry.Headword)
 		isLinkEntry := strings.Contains(entry.Definition, "@@@LINK=")
 
-		if existingDef, exists := mergedEntries[key]; exists {
+		if i, exists := positions[key]; exists {
 			// 既にエントリが存在する場合
-			if isLinkEntry && !strings.Contains(existingDef, "@@@LINK=") {
+			existing := &finalEntries[i]
+			if isLinkEntry {
+				merged.Links++
+			} else {
+				merged.Discarded++
+			}
+			if isLinkEntry && !strings.Contains(existing.Definition, "@@@LINK=") {
 				// 既存の定義に、新しいリンク情報を追記する
-				mergedEntries[key] = existingDef + "\n" + entry.Definition
+				existing.Definition += "\n" + entry.Definition
 			}
+			existing.Keywords = appendUnique(existing.Keywords, entry.Keywords...)
 		} else {
 			// 新しいエントリとして追加
-			mergedEntries[key] = entry.Definition
+			entry.Headword = key
+			positions[key] = len(finalEntries)
+			finalEntries = append(finalEntries, entry)
 		}
 	}
-
-	// 2. リンクを解決し、定義をマージする
-	for key, def := range mergedEntries {
-		if strings.Contains(def, "@@@LINK=") {
-			// リンク情報（例: "@@@LINK=drive"）を抽出し、元の定義から削除する
-			reLink := regexp.MustCompile(`\n?@@@LINK=(.+)`)
-			linkMatch := reLink.FindStringSubmatch(def)
-			originalDef := reLink.ReplaceAllString(def, "")
-			linkTarget := linkMatch[1]
-
-			if baseDef, ok := mergedEntries[linkTarget]; ok {
-				mergedEntries[key] = originalDef + "\n" + "---" + "\n" + baseDef
-			}
+	// 詰めた後に残った領域のエントリが文字列などを参照し続けないよう、空にしておく
+	clear(entries[len(finalEntries):])
+	finalEntries = slices.Clip(finalEntries)
+
+	// 2. リンクを解決し、定義をマージする（リンク先がさらにリンクを持つ場合もたどる）
+	// finalEntries はこれ以上伸ばさないため、要素へのポインタは有効なまま使える
+	mergedEntries := make(map[string]*DictionaryEntry, len(positions))
+	for key, i := range positions {
+		mergedEntries[key] = &finalEntries[i]
+	}
+	if unresolved := resolveLinks(mergedEntries); len(unresolved) > 0 {
+		logger.Warn(sprintf("リンク先が見つからない参照が%d件ありました。(例: %s)", len(unresolved), formatUnresolvedLinks(unresolved, 5)), "event", "unresolved_links", "count", len(unresolved))
+		for _, link := range unresolved {
+			logger.Debug(sprintf("リンク先が見つかりません: %s → %s", link.From, link.To), "event", "unresolved_link", "from", link.From, "to", link.To)
 		}
 	}
+	return finalEntries, merged
+}
 
-	// 3. マップから最終的なエントリリストを再生成
-	finalEntries := make([]DictionaryEntry, 0, len(mergedEntries))
-	for headword, definition := range mergedEntries {
-		finalEntries = append(finalEntries, DictionaryEntry{Headword: headword, Definition: definition})
+// appendUnique はスライスに含まれていない値のみを追加する
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
 	}
-	return finalEntries
+	return list
 }
 
 // parseEijiro は英辞郎形式のテキストファイルを解析する
 // Shift_JISからUTF-8への変換機能を含む
 func parseEijiro(filePath string, opts ParseOptions) ([]DictionaryEntry, error) {
+	entries, _, err := parseEijiroWithStats(filePath, opts)
+	return entries, err
+}
+
+// parseEijiroWithStats は parseEijiro と同じ解析を行い、読み込んだ行の内訳もあわせて返す
+func parseEijiroWithStats(filePath string, opts ParseOptions) ([]DictionaryEntry, ParseStats, error) {
+	return parseEijiroContext(context.Background(), filePath, opts)
+}
+
+// cancelCheckInterval は読み込み中に中断の指示を確認する間隔 (行数)
+const cancelCheckInterval = 1 << 14
+
+// parseEijiroContext は parseEijiroWithStats と同じ解析を行う
+// ctx が取り消された場合は、読み込みを中止してエラーを返す
+func parseEijiroContext(ctx context.Context, filePath string, opts ParseOptions) ([]DictionaryEntry, ParseStats, error) {
+	file, err := openInput(filePath)
+	if err != nil {
+		return nil, ParseStats{}, err
+	}
+	defer file.Close()
+	return parseEijiroReader(ctx, file, opts)
+}
+
+// ParseFrom は r から英辞郎の形式 (Shift_JIS) のデータを読み込み、parseEijiro と同じ解析を行う
+// ファイルを介さずに、サーバーやテストでメモリ上のデータやオブジェクトストレージから読み込んだデータを変換するために使う
+func ParseFrom(r io.Reader, opts ParseOptions) ([]DictionaryEntry, error) {
+	entries, _, err := parseEijiroReader(context.Background(), r, opts)
+	return entries, err
+}
+
+// parseEijiroReader は r から英辞郎の形式のデータを読み込み、エントリと読み込んだ行の内訳を返す
+// ctx が取り消された場合は、読み込みを中止してエラーを返す
+func parseEijiroReader(ctx context.Context, r io.Reader, opts ParseOptions) ([]DictionaryEntry, ParseStats, error) {
 	// ループの外で正規表現をコンパイルする
 	posRegex := regexp.MustCompile(`^(.*?)\s*(\{.*?\})$`)
 
-	file, err := os.Open(filePath)
+	var stats ParseStats
+	normalizer, err := newTextNormalizer(opts)
 	if err != nil {
-		return nil, err
+		return nil, stats, err
 	}
-	defer file.Close()
 
 	// Shift_JISからUTF-8へのデコーダーを作成
 	decoder := japanese.ShiftJIS.NewDecoder()
-	// ファイルリーダーをデコーダーでラップ
-	reader := transform.NewReader(file, decoder)
+	// リーダーをデコーダーでラップ
+	reader := transform.NewReader(r, decoder)
 
 	var entries []DictionaryEntry
 	var synonymEntries []DictionaryEntry // 変化形から原形へのリンクを保持
+	var abbreviations abbreviationLinker // 【略】の略語から元の見出し語へのリンクを保持
 	scanner := bufio.NewScanner(reader)  // デコードされたリーダーをスキャンする
 	var currentEntry *DictionaryEntry
+	skipping := false // 除外した見出し語や定義行の用例などの行を読み飛ばしている間は true
+	domains := newDomainFilter(opts.IncludeDomains, opts.ExcludeDomains)
+	partsOfSpeech, err := newPOSFilter(opts.IncludePOS, opts.ExcludePOS)
+	if err != nil {
+		return nil, stats, err
+	}
+	selector, err := newHeadwordSelector(opts.HeadwordRange, opts.Offset, opts.Limit)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	// エントリの文字列は読み込んだ行の一部を指しているため、確定した時点で少ないメモリで保持できる形に置き換える
+	compactor := newEntryCompactor()
+	// 現在のエントリの定義は行ごとに追記するため、文字列を連結し直さずに使い回すバッファに書き込み、確定した時点で文字列にする
+	var defBuf bytes.Buffer
+	flush := func() {
+		compactor.compact(currentEntry)
+		currentEntry.Definition = compactor.storeBytes(defBuf.Bytes())
+		if normalizer != nil {
+			normalizer.normalizeEntry(currentEntry)
+		}
+		entries = append(entries, *currentEntry)
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text() // ここで得られるlineはUTF-8に変換済み
+		synonymCount := len(synonymEntries)
+		stats.Lines++
+		if stats.Lines%cancelCheckInterval == 0 {
+			if err := checkCanceled(ctx); err != nil {
+				return nil, stats, err
+			}
+		}
 
 		matches := entryRegex.FindStringSubmatch(line)
 		if matches != nil {
@@ -240,6 +1190,23 @@ func parseEijiro(filePath string, opts ParseOptions) ([]DictionaryEntry, error)
 			rawHeadword := strings.TrimSpace(matches[1])
 			rawDefinition := strings.TrimSpace(matches[2])
 
+			// 見出し語の範囲や件数で絞り込む場合は、対象外の見出し語の行を加工せずに読み飛ばす
+			// 指定の件数を読み込み終えたら、以降の行は読み込まない
+			if selector != nil && !selector.accept(rawHeadword) {
+				if selector.done {
+					// 打ち切った行も、除外した行として数える
+					stats.SkippedLines++
+					break
+				}
+				if currentEntry != nil {
+					flush()
+					currentEntry = nil
+				}
+				stats.SkippedLines++
+				skipping = true
+				continue
+			}
+
 			// 【変化】タグから同義語（変化形）を抽出する
 			if formsMatch := reFormsExtract.FindStringSubmatch(rawDefinition); len(formsMatch) > 1 {
 				formsStr := formsMatch[1]
@@ -252,6 +1219,10 @@ func parseEijiro(filePath string, opts ParseOptions) ([]DictionaryEntry, error)
 						if posMatches := posRegex.FindStringSubmatch(rawHeadword); posMatches != nil {
 							linkTarget = posMatches[1]
 						}
+						// 見出し語の括弧を展開する場合は、表示用の見出し語にリンクする
+						if opts.ExpandBrackets {
+							linkTarget = expandHeadwordBrackets(linkTarget)[0]
+						}
 						// `|` で区切られた複数の変化形に対応する (例: expects | expecting | expected)
 						formWordsStr := strings.TrimSpace(part[1])
 						formWords := strings.Split(formWordsStr, "|")
@@ -260,8 +1231,9 @@ func parseEijiro(filePath string, opts ParseOptions) ([]DictionaryEntry, error)
 							trimmedFormWord := strings.TrimSpace(formWord)
 							if trimmedFormWord != "" {
 								synonymEntries = append(synonymEntries, DictionaryEntry{
-									Headword:   trimmedFormWord,
-									Definition: "@@@LINK=" + linkTarget, // StarDictのリンク形式
+									Headword:   compactor.store(trimmedFormWord),
+									Definition: compactor.store("@@@LINK=" + linkTarget), // StarDictのリンク形式
+									SourceLine: stats.Lines,
 								})
 							}
 						}
@@ -287,6 +1259,9 @@ func parseEijiro(filePath string, opts ParseOptions) ([]DictionaryEntry, error)
 				pos = posMatches[2]
 			}
 
+			// 構造化データ用に、品詞情報やリンクを付ける前の定義行を残しておく
+			senseText := definition
+
 			// 動詞の活用形から原形へのリンクを生成する (例: "knowの過去形" -> "@@@LINK=know")
 			// この処理は品詞情報が追加された後に行う
 			tempDefWithPos := pos + " " + definition
@@ -297,78 +1272,208 @@ func parseEijiro(filePath string, opts ParseOptions) ([]DictionaryEntry, error)
 				// リンクに変換しない場合は、品詞情報を先頭につける
 				definition = tempDefWithPos
 			}
+			// 定義全体が別の見出し語の参照 (＝<→…>) の場合は、参照先の定義を引けるようリンクを加える
+			if opts.ResolveAliases {
+				if target := aliasTarget(senseText); target != "" {
+					definition += "\n@@@LINK=" + target
+					stats.AliasLinks++
+				}
+			}
 
 			if headword == "" {
 				headword = rawHeadword
 			}
 
+			// 見出し語の括弧を展開し、表示用以外の形と元の表記は検索用キーワードにする
+			var headwordVariants []string
+			if opts.ExpandBrackets {
+				if variants := expandHeadwordBrackets(headword); variants[0] != headword {
+					headwordVariants = append(variants[1:], headword)
+					headword = variants[0]
+				}
+			}
+
+			// 削除されるラベルの内容を、検索用キーワードとして先に取り出しておく
+			var keywords []string
+			if opts.KeepStrippedKeywords {
+				keywords = extractStrippedKeywords(definition, opts)
+			}
+			keywords = appendUnique(keywords, headwordVariants...)
+			// 成句の見出し語は、目的語などの位置を表す記号 (~) を除いた形でも検索できるようにする
+			if opts.PlaceholderKeys {
+				for _, form := range append([]string{headword}, headwordVariants...) {
+					keywords = appendUnique(keywords, placeholderVariants(form)...)
+				}
+			}
+
+			// 分野・用法のラベルや品詞で除外する定義行は、この行から取り出した変化形や、続く用例・補足説明とともに読み飛ばす
+			// 同じ見出し語の残りの定義行は対象にするため、直前のエントリは見出し語が変わる場合のみ確定する
+			if lineDomains := extractDomains(senseText); !domains.keep(lineDomains) || !partsOfSpeech.keep(pos) {
+				synonymEntries = synonymEntries[:synonymCount]
+				stats.SkippedLines++
+				if logger.Enabled(ctx, levelTrace) {
+					logger.Log(ctx, levelTrace, sprintf("%d行目: 分野・用法のラベル (%s) または品詞 (%s) により '%s' の定義行を除外しました。", stats.Lines, strings.Join(lineDomains, ","), pos, headword), "event", "skipped_line", "line", stats.Lines, "headword", headword)
+				}
+				if currentEntry != nil && currentEntry.Headword != headword {
+					flush()
+					currentEntry = nil
+				}
+				skipping = true
+				continue
+			}
+			skipping = false
+
 			// 直前のエントリと同じ見出し語の場合、定義を追記する
 			if currentEntry != nil && currentEntry.Headword == headword {
-				processedDef := processDefinition(definition, opts)
-				if !opts.StripExamples && example != "" {
-					// "■・" を取り除いてから追加
-					processedDef += "\n" + "■" + strings.TrimPrefix(example, "■・")
+				stats.SenseLines++
+				currentEntry.Keywords = appendUnique(currentEntry.Keywords, keywords...)
+				applySense(currentEntry, pos, senseText, opts)
+				if opts.AbbreviationLinks {
+					abbreviations.add(senseText, currentEntry.Headword, stats.Lines)
 				}
+				processedDef := processDefinition(definition, opts)
 				if processedDef != "" {
-					currentEntry.Definition += "\n" + processedDef
+					defBuf.WriteByte('\n')
+					defBuf.WriteString(processedDef)
+				}
+				if example != "" {
+					// "■・" を取り除いてから追加
+					appendExample(currentEntry, &defBuf, strings.TrimPrefix(example, "■・"), opts)
 				}
 				continue // 次の行へ
 			}
 
+			// 時間制限を過ぎた場合は、見出し語の区切りで読み込みを打ち切る (空の辞書にならないよう、最初の見出し語は必ず読み込む)
+			// この行から取り出した変化形は、打ち切る見出し語へのリンクなので取り除く
+			if currentEntry != nil && !opts.Deadline.IsZero() && time.Now().After(opts.Deadline) {
+				synonymEntries = synonymEntries[:synonymCount]
+				stats.Truncated = true
+				stats.SkippedLines++
+				logger.Warn(sprintf("時間制限に達したため、'%s' の手前で読み込みを打ち切りました。", headword), "event", "truncated", "line", stats.Lines, "headword", headword)
+				break
+			}
+
 			// 新しい見出し語に移るので、その前に直前のエントリをリストに追加
 			if currentEntry != nil {
-				entries = append(entries, *currentEntry)
+				flush()
 			}
 
 			// --single-word-only オプションが有効な場合、スペースを含む見出語をスキップ
 			if opts.SingleWordOnly && strings.Contains(headword, " ") {
 				currentEntry = nil // 現在のエントリをリセットして、後続行が処理されないようにする
+				stats.SkippedLines++
+				if logger.Enabled(ctx, levelTrace) {
+					logger.Log(ctx, levelTrace, sprintf("%d行目: 複数の単語からなる見出し語 '%s' を除外しました。", stats.Lines, headword), "event", "skipped_line", "line", stats.Lines, "headword", headword)
+				}
+				skipping = true
 				continue
 			}
 
 			// オプションに基づいて定義を加工
 			definition = processDefinition(definition, opts)
+			skipping = false
 
-			// 用例を追加する（オプションが有効な場合）
-			if !opts.StripExamples && example != "" {
-				definition += "\n" + "■" + strings.TrimPrefix(example, "■・")
-			}
-
+			stats.Headwords++
 			currentEntry = &DictionaryEntry{
 				Headword:   headword,
-				Definition: definition,
+				Keywords:   appendUnique(nil, keywords...),
+				SourceLine: stats.Lines,
+			}
+			defBuf.Reset()
+			defBuf.WriteString(definition)
+			applySense(currentEntry, pos, senseText, opts)
+			if opts.AbbreviationLinks {
+				abbreviations.add(senseText, headword, stats.Lines)
+			}
+
+			// 用例を追加する（オプションが有効な場合）
+			if example != "" {
+				appendExample(currentEntry, &defBuf, strings.TrimPrefix(example, "■・"), opts)
 			}
-		} else if currentEntry != nil {
+		} else if currentEntry == nil || skipping || !(strings.HasPrefix(line, "■・") || strings.HasPrefix(line, "◆")) {
+			// 見出しにぶら下がらない行は無視するが、空行以外は件数を記録しておく
+			switch {
+			case strings.TrimSpace(line) == "":
+				stats.BlankLines++
+			case skipping:
+				stats.SkippedLines++
+			default:
+				stats.IgnoredLines++
+				if logger.Enabled(ctx, levelTrace) {
+					logger.Log(ctx, levelTrace, sprintf("%d行目: どの見出し語にも属さない行を無視しました: %s", stats.Lines, line), "event", "ignored_line", "line", stats.Lines, "text", line)
+				}
+			}
+		} else {
+			stats.AttachedLines++
 			// 用例 (■・)
 			if strings.HasPrefix(line, "■・") {
-				if !opts.StripExamples {
-					// "■・" を取り除いて追加
-					exampleLine := strings.TrimPrefix(line, "■・")
-					currentEntry.Definition += "\n" + "■" + exampleLine
-				}
+				// "■・" を取り除いて追加
+				appendExample(currentEntry, &defBuf, strings.TrimPrefix(line, "■・"), opts)
 			} else if strings.HasPrefix(line, "◆") {
 				// 補足説明 (◆)
+				// 略語は補足説明に書かれることが多いため、補足説明を削除する場合も取り出しておく
+				if opts.AbbreviationLinks {
+					abbreviations.add(line, currentEntry.Headword, stats.Lines)
+				}
 				if !opts.StripSupplement {
-					currentEntry.Definition += "\n" + line
+					defBuf.WriteByte('\n')
+					defBuf.WriteString(line)
+					if sense := currentEntry.lastSense(); sense != nil {
+						sense.Supplements = append(sense.Supplements, line)
+					}
 				}
 			}
 		}
-		// 上記以外の行（見出しにぶら下がらない行）は無視する
 	}
 
 	// 最後の見出しを追加
 	if currentEntry != nil {
-		entries = append(entries, *currentEntry)
+		flush()
 	}
 
 	// 最後に同義語エントリを追加
+	// 【略】の略語へのリンクも、変化形のリンクと同様に扱う
+	stats.AbbreviationLinks = len(abbreviations.entries)
+	synonymEntries = append(synonymEntries, abbreviations.entries...)
+	// 変化形とリンク先は、見出し語と同じ規則で正規化する
+	if normalizer != nil {
+		for i := range synonymEntries {
+			normalizer.normalizeEntry(&synonymEntries[i])
+		}
+	}
+	// 【変化】のない見出し語は、規則変化の変化形を生成してリンクを補う
+	if opts.GenerateInflections {
+		generated := generateInflections(entries, synonymEntries)
+		stats.GeneratedLinks = len(generated)
+		synonymEntries = append(synonymEntries, generated...)
+	}
+	stats.LinkEntries = len(synonymEntries)
+	// append で伸ばすと配列に余りができ、変換の終わりまで残るため、ちょうどの大きさで確保し直す
+	entries = append(make([]DictionaryEntry, 0, len(entries)+len(synonymEntries)), entries...)
 	entries = append(entries, synonymEntries...)
 
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return nil, stats, err
 	}
 
-	return entries, nil
+	return entries, stats, nil
+}
+
+// appendExample はオプションに応じて用例をエントリに追加する
+// SplitExamplesが有効な場合は定義とは別に保持し、StripExamplesが有効な場合は破棄する
+// 定義に含める場合は、エントリの定義を書き込んでいる def に追記する
+func appendExample(entry *DictionaryEntry, def *bytes.Buffer, example string, opts ParseOptions) {
+	// 構造化データでは、用例は直前の語義に属する
+	if sense := entry.lastSense(); sense != nil && (opts.SplitExamples || !opts.StripExamples) {
+		sense.Examples = append(sense.Examples, example)
+	}
+	switch {
+	case opts.SplitExamples:
+		entry.Examples = append(entry.Examples, example)
+	case !opts.StripExamples:
+		def.WriteString("\n■")
+		def.WriteString(example)
+	}
 }
 
 // processDefinition はオプションに基づいて定義文字列を加工する
@@ -382,6 +1487,8 @@ func processDefinition(def string, opts ParseOptions) string {
 	}
 	if opts.StripPronunciation {
 		def = rePronunciation.ReplaceAllString(def, "")
+	} else if opts.PronunciationIPA {
+		def = convertPronunciationsToIPA(def)
 	}
 	if opts.StripKatakana {
 		def = reKatakana.ReplaceAllString(def, "")
@@ -411,88 +1518,236 @@ func processDefinition(def string, opts ParseOptions) string {
 	return def
 }
 
-// writeStarDictFiles はパースしたエントリからStarDictファイルを書き出す
-func writeStarDictFiles(dir, bookName, version string, entries []DictionaryEntry) error {
-	// ファイルパスを定義
-	ifoPath := filepath.Join(dir, bookName+".ifo")
-	idxPath := filepath.Join(dir, bookName+".idx")
-	// 一時的に非圧縮の.dictファイルを作成する
-	dictPath := filepath.Join(dir, bookName+".dict")
+// writeStarDictFiles はパースしたエントリからStarDictファイルをディレクトリ dir に書き出す
+// 書き出す内容は WriteStarDict と同じで、dictzip があればそれで圧縮する
+// dir には一時ディレクトリを渡し、以前の変換で作られたファイルの置き換えは pendingOutputs.commit に任せる
+func writeStarDictFiles(dir, bookName, version string, entries []DictionaryEntry, wopts WriteOptions) error {
+	// dictzip で圧縮する場合は、非圧縮の .dict を書き出してから圧縮する
+	dictzip := !wopts.NoCompress && dictzipAvailable()
+	if err := writeStarDict(DirFS(dir), bookName, version, entries, wopts, dictzip); err != nil {
+		return err
+	}
+	if dictzip {
+		return runDictzip(filepath.Join(dir, bookName+".dict"), fileTimestamp(wopts.BuildTime))
+	}
+	return nil
+}
 
-	var idxBuf bytes.Buffer
-	var dictBuf bytes.Buffer
+// newStarDictInfo は組み立てた辞書の内容と出力オプションから、.ifo に書き込む情報を作る
+func newStarDictInfo(bookName, version string, wordCount int, data starDictData, renderer Renderer, wopts WriteOptions) StarDictInfo {
+	return StarDictInfo{
+		Version:       version,
+		BookName:      bookName,
+		WordCount:     uint32(wordCount),
+		IdxFileSize:   uint64(len(data.idx)),
+		IdxOffsetBits: data.offsetBits,
+		SynWordCount:  uint32(len(data.synonyms)),
+		SameTypeSeq:   renderer.TypeSequence(),
+		Author:        cmp.Or(wopts.Author, defaultIfoAuthor),
+		Email:         wopts.Email,
+		Website:       wopts.Website,
+		Description:   cmp.Or(wopts.Description, defaultIfoDescription),
+		Date:          ifoDate(wopts.BuildTime),
+	}
+}
+
+// ifoDate は .ifo の date に書き出す作成日を返す
+// 作成日時の指定がない場合は、辞書管理ソフトで変換した日が分かるよう、変換した日の日付を書き出す
+func ifoDate(buildTime time.Time) string {
+	if buildTime.IsZero() {
+		return time.Now().Format(ifoDateFormat)
+	}
+	return buildTime.Format(ifoDateFormat)
+}
+
+// starDictData はメモリ上に組み立てた .idx と .dict の内容、および .syn に書き出す同義語
+type starDictData struct {
+	idx        []byte
+	dict       []byte
+	synonyms   []synonymEntry
+	offsetBits int // .idx に記録した .dict 内の位置のビット数 (32 または 64)
+}
+
+// idxOffsetBits は .dict の大きさに応じて、.idx に記録する位置のビット数を返す
+// 32ビットで表せない位置がある場合のみ64ビットを使い、従来の辞書アプリとの互換性を保つ
+func idxOffsetBits(dictSize int64) int {
+	if dictSize > math.MaxUint32 {
+		return 64
+	}
+	return 32
+}
 
+// buildStarDictData はエントリを索引順に並べ、.idx と .dict の内容を組み立てる
+func buildStarDictData(entries []DictionaryEntry, renderer Renderer) (starDictData, error) {
+	// 見出し語が空のエントリは索引を壊すため、書き出す前に検出する
 	for _, entry := range entries {
-		definitionBytes := []byte(entry.Definition)
+		if entry.Headword == "" {
+			return starDictData{}, errorf("見出し語が空のエントリがあります (定義: %.40q)", entry.Definition)
+		}
+	}
 
-		// --- .idx ファイルのデータを準備 ---
-		idxBuf.WriteString(entry.Headword)
-		idxBuf.WriteByte(0)
+	// StarDictの読み込み側は二分探索を行うため、見出し語を規定の順序で並べておく
+	// エントリ全体を複製せず、並べた順序のみを求める
+	order := starDictOrder(entries)
 
-		// .dictファイル内でのオフセットを記録
-		offset := uint32(dictBuf.Len())
-		binary.Write(&idxBuf, binary.BigEndian, offset)
+	var dictBuf bytes.Buffer
+	var synonyms []synonymEntry
+	sizes := make([]uint32, len(entries))
 
-		// 定義データのサイズを記録
-		binary.Write(&idxBuf, binary.BigEndian, uint32(len(definitionBytes)))
+	// 書き込み中の再確保で一時的に倍の領域を使わないよう、描画前の定義の大きさから容量を見積もっておく
+	estimated := 0
+	for _, entry := range entries {
+		estimated += len(entry.Definition)
+	}
+	dictBuf.Grow(estimated + estimated/8)
+
+	// 位置のビット数は .dict 全体の大きさで決まるため、先に定義をすべて書き出す
+	for i, n := range order {
+		entry := &entries[n]
+		// 検索用キーワードは .syn ファイルで索引上の位置に対応付ける
+		for _, keyword := range entry.Keywords {
+			if keyword != entry.Headword {
+				synonyms = append(synonyms, synonymEntry{Word: keyword, Index: uint32(i)})
+			}
+		}
 
-		// .dictファイルの内容をバッファに書き込む
-		dictBuf.Write(definitionBytes)
+		definition := renderer.Render(*entry)
+		// 定義データの大きさは64ビットの索引でも32ビットで記録する
+		if int64(len(definition)) > math.MaxUint32 {
+			return starDictData{}, errorf("'%s' の定義が大きすぎます (%d バイト)", entry.Headword, len(definition))
+		}
+		sizes[i] = uint32(len(definition))
+		dictBuf.WriteString(definition)
 	}
 
-	// --- ファイル書き出し ---
+	offsetBits := idxOffsetBits(int64(dictBuf.Len()))
+	var idxBuf bytes.Buffer
+	var offset uint64
+	for i, n := range order {
+		appendIdxEntry(&idxBuf, entries[n].Headword, offset, sizes[i], offsetBits)
+		offset += uint64(sizes[i])
+	}
+	return starDictData{idx: idxBuf.Bytes(), dict: dictBuf.Bytes(), synonyms: synonyms, offsetBits: offsetBits}, nil
+}
 
-	// 1. 非圧縮の.dictファイルを書き出す
-	if err := os.WriteFile(dictPath, dictBuf.Bytes(), 0644); err != nil {
-		return fmt.Errorf(".dict ファイルの書き込みに失敗: %w", err)
+// appendIdxEntry は .idx の1エントリ (見出し語、.dict 内の位置、定義データの大きさ) を書き込む
+func appendIdxEntry(buf *bytes.Buffer, word string, offset uint64, size uint32, offsetBits int) {
+	buf.WriteString(word)
+	buf.WriteByte(0)
+	if offsetBits == 64 {
+		binary.Write(buf, binary.BigEndian, offset)
+	} else {
+		binary.Write(buf, binary.BigEndian, uint32(offset))
 	}
+	binary.Write(buf, binary.BigEndian, size)
+}
 
-	// 2. dictzipコマンドを実行して.dictを.dict.dzに圧縮する
-	// dictzipは成功すると元のファイルを削除する
-	cmd := exec.Command("dictzip", dictPath)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		// dictzipコマンドのパスが見つからない、などのエラーメッセージを出力する
-		return fmt.Errorf("dictzipの実行に失敗: %w\n%s", err, string(output))
+// sortEntriesForStarDict はエントリをStarDictの索引順に並べ替えたコピーを返す
+func sortEntriesForStarDict(entries []DictionaryEntry) []DictionaryEntry {
+	sorted := make([]DictionaryEntry, len(entries))
+	for i, n := range starDictOrder(entries) {
+		sorted[i] = entries[n]
 	}
+	return sorted
+}
 
-	// .idx ファイルを書き込み
-	if err := os.WriteFile(idxPath, idxBuf.Bytes(), 0644); err != nil {
-		return fmt.Errorf(".idx ファイルの書き込みに失敗: %w", err)
+// starDictOrder はエントリをStarDictの索引順に並べた場合の、元のスライスでの位置の並びを返す
+func starDictOrder(entries []DictionaryEntry) []int {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
 	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return stardictStrcmp(entries[order[i]].Headword, entries[order[j]].Headword) < 0
+	})
+	return order
+}
 
-	// .ifo ファイルを書き込み
-	ifo := StarDictInfo{
-		Version:     version,
-		BookName:    bookName,
-		WordCount:   uint32(len(entries)),
-		IdxFileSize: uint32(idxBuf.Len()),
-		SameTypeSeq: "g", // 'g' はdictzip圧縮されたUTF-8テキストを意味する
-		Author:      "Converted with Go",
-		Description: "A comprehensive Japanese-English dictionary based on Eijiro data, converted with eijiro-converter.",
-		Date:        time.Now().Format("2006-01-02"),
+// stardictStrcmp はStarDictの stardict_strcmp と同じ規則で文字列を比較する
+// ASCIIの大文字小文字を無視して比較し、等しい場合はバイト列で比較する
+func stardictStrcmp(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := asciiLower(a[i]), asciiLower(b[i])
+		if ca != cb {
+			return int(ca) - int(cb)
+		}
+	}
+	if len(a) != len(b) {
+		return len(a) - len(b)
 	}
-	return writeIfoFile(ifoPath, ifo)
+	return strings.Compare(a, b)
 }
 
-// writeIfoFile は .ifo ファイルを生成する
-func writeIfoFile(path string, info StarDictInfo) error {
-	file, err := os.Create(path)
-	if err != nil {
-		return err
+// asciiLower はASCIIの大文字のみを小文字に変換する
+func asciiLower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
 	}
-	defer file.Close()
+	return c
+}
+
+// synonymEntry は .syn ファイルの1エントリ（同義語と、.idx 内での見出し語の位置）
+type synonymEntry struct {
+	Word  string
+	Index uint32
+}
 
-	writer := bufio.NewWriter(file)
+// encodeSyn は .syn ファイルの内容を組み立てる
+// 同義語は .idx と同じ規則で並べる必要がある
+func encodeSyn(synonyms []synonymEntry) []byte {
+	sort.SliceStable(synonyms, func(i, j int) bool {
+		return stardictStrcmp(synonyms[i].Word, synonyms[j].Word) < 0
+	})
+
+	var synBuf bytes.Buffer
+	for _, syn := range synonyms {
+		synBuf.WriteString(syn.Word)
+		synBuf.WriteByte(0)
+		binary.Write(&synBuf, binary.BigEndian, syn.Index)
+	}
+	return synBuf.Bytes()
+}
+
+// .ifo に書き出す辞書の情報の既定値と、日付の形式 (StarDict の仕様の例にならい "2006.01.02" の形式)
+const (
+	defaultIfoAuthor      = "Converted with Go"
+	defaultIfoDescription = "A comprehensive Japanese-English dictionary based on Eijiro data, converted with eijiro-converter."
+	ifoDateFormat         = "2006.01.02"
+)
+
+// ifoValue は .ifo の1行に収まるよう値を整える
+// .ifo は1行に1項目の形式のため、改行は StarDict の仕様に従って <br> に置き換える
+func ifoValue(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+// writeIfo は .ifo の内容を w に書き出す
+func writeIfo(w io.Writer, info StarDictInfo) error {
+	writer := bufio.NewWriter(w)
 	fmt.Fprintln(writer, "StarDict's dict ifo file")
 	fmt.Fprintf(writer, "version=%s\n", info.Version)
 	fmt.Fprintf(writer, "bookname=%s\n", info.BookName)
 	fmt.Fprintf(writer, "wordcount=%d\n", info.WordCount)
 	fmt.Fprintf(writer, "idxfilesize=%d\n", info.IdxFileSize)
+	if info.IdxOffsetBits == 64 {
+		fmt.Fprintln(writer, "idxoffsetbits=64")
+	}
+	if info.SynWordCount > 0 {
+		fmt.Fprintf(writer, "synwordcount=%d\n", info.SynWordCount)
+	}
 	if info.Author != "" {
-		fmt.Fprintf(writer, "author=%s\n", info.Author)
+		fmt.Fprintf(writer, "author=%s\n", ifoValue(info.Author))
+	}
+	if info.Email != "" {
+		fmt.Fprintf(writer, "email=%s\n", ifoValue(info.Email))
+	}
+	if info.Website != "" {
+		fmt.Fprintf(writer, "website=%s\n", ifoValue(info.Website))
 	}
 	if info.Description != "" {
-		fmt.Fprintf(writer, "description=%s\n", info.Description)
+		fmt.Fprintf(writer, "description=%s\n", ifoValue(info.Description))
 	}
 	if info.Date != "" {
 		fmt.Fprintf(writer, "date=%s\n", info.Date)
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"archive/zip"
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 辞書の出力形式 (ConvertConfig.Format に指定する値)
+const (
+	FormatStarDict = "stardict" // StarDict形式 (.ifo, .idx, .dict.dz)
+	FormatEPUB     = "epub"     // 電子書籍リーダーで読み進められる EPUB の用語集 (<辞書名>.epub)
+)
+
+// epubOtherChapter は英字以外で始まる見出し語 (記号や日本語など) をまとめる章のファイル名の一部
+const epubOtherChapter = "other"
+
+// validateFormat は出力形式の指定が正しいかを確認する
+func validateFormat(format string) error {
+	switch format {
+	case "", FormatStarDict, FormatEPUB:
+		return nil
+	}
+	return errorf("未対応の出力形式です: %s (%s または %s を指定してください)", format, FormatStarDict, FormatEPUB)
+}
+
+// writeDictionary は出力形式に応じて、辞書を dir に書き出す
+func writeDictionary(format, dir, bookName, version string, entries []DictionaryEntry, wopts WriteOptions) error {
+	if format == FormatEPUB {
+		return writeEPUBFile(filepath.Join(dir, bookName+".epub"), bookName, version, entries, wopts)
+	}
+	return writeStarDictFiles(dir, bookName, version, entries, wopts)
+}
+
+// epubChapter は同じ文字で始まる見出し語をまとめた、EPUB の一つの章
+type epubChapter struct {
+	key     string // ファイル名の一部 ("a" から "z" または epubOtherChapter)
+	title   string // 目次に表示する題名 ("A" など)
+	first   int    // 章の最初の項目の通し番号 (項目のIDに使う)
+	entries []DictionaryEntry
+}
+
+// fileName は章の XHTML ファイルの、EPUB 内のパスを返す
+func (c epubChapter) fileName() string {
+	return "chapter-" + c.key + ".xhtml"
+}
+
+// epubChapterKey は見出し語を収める章を返す (英字で始まる場合はその小文字、それ以外は epubOtherChapter)
+func epubChapterKey(headword string) string {
+	if headword != "" {
+		if c := asciiLower(headword[0]); c >= 'a' && c <= 'z' {
+			return string(c)
+		}
+	}
+	return epubOtherChapter
+}
+
+// groupEPUBChapters はエントリを見出し語の順 (.idx と同じ stardictStrcmp の順) に並べ、先頭の文字ごとの章に分ける
+// 英字の章を A から Z の順に並べ、英字以外で始まる見出し語の章を最後に置く
+func groupEPUBChapters(entries []DictionaryEntry) []epubChapter {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b DictionaryEntry) int {
+		return stardictStrcmp(a.Headword, b.Headword)
+	})
+	byKey := make(map[string]*epubChapter)
+	for _, entry := range sorted {
+		key := epubChapterKey(entry.Headword)
+		chapter, ok := byKey[key]
+		if !ok {
+			chapter = &epubChapter{key: key, title: strings.ToUpper(key)}
+			if key == epubOtherChapter {
+				chapter.title = "その他"
+			}
+			byKey[key] = chapter
+		}
+		chapter.entries = append(chapter.entries, entry)
+	}
+	var chapters []epubChapter
+	n := 0
+	for _, key := range append(strings.Split("abcdefghijklmnopqrstuvwxyz", ""), epubOtherChapter) {
+		if chapter, ok := byKey[key]; ok {
+			chapter.first = n
+			n += len(chapter.entries)
+			chapters = append(chapters, *chapter)
+		}
+	}
+	return chapters
+}
+
+// epubAnchors は見出し語から、その項目へのEPUB内のリンク先 ("chapter-a.xhtml#e12" など) を引く
+type epubAnchors struct {
+	exact  map[string]string
+	folded map[string]string // 大文字・小文字だけが異なる参照 (<→United Nations> など) を解決するための、小文字にした見出し語からの索引
+}
+
+// epubEntryID は通し番号 n の項目の ID を返す
+func epubEntryID(n int) string {
+	return fmt.Sprintf("e%d", n)
+}
+
+// newEPUBAnchors は見出し語からリンク先を引けるようにする
+// 同じ見出し語の項目が複数ある場合 (-merge separate など) は、最初の項目をリンク先とする
+func newEPUBAnchors(chapters []epubChapter) epubAnchors {
+	anchors := epubAnchors{exact: make(map[string]string), folded: make(map[string]string)}
+	for _, chapter := range chapters {
+		for i, entry := range chapter.entries {
+			href := chapter.fileName() + "#" + epubEntryID(chapter.first+i)
+			if _, ok := anchors.exact[entry.Headword]; !ok {
+				anchors.exact[entry.Headword] = href
+			}
+			if lower := strings.ToLower(entry.Headword); anchors.folded[lower] == "" {
+				anchors.folded[lower] = href
+			}
+		}
+	}
+	return anchors
+}
+
+// href は見出し語へのリンク先を返す (見つからない場合は空文字列)
+func (a epubAnchors) href(headword string) string {
+	if href, ok := a.exact[headword]; ok {
+		return href
+	}
+	return a.folded[strings.ToLower(headword)]
+}
+
+// link は参照先の見出し語 (エスケープ前) を、EPUB内のリンクにした XHTML を返す
+// 参照先がこの辞書にない場合は、リンクにせず文字列のみを残す
+func (a epubAnchors) link(target string) string {
+	text := "→" + html.EscapeString(target)
+	if href := a.href(target); href != "" {
+		return `<a href="` + html.EscapeString(href) + `">` + text + "</a>"
+	}
+	return text
+}
+
+// renderEPUBDefinition は定義を XHTML の段落の並びに変換する
+// 読み仮名やラベルは HTML 形式の辞書と同じく装飾し、PDICリンク (<→…>) とリンク情報 (@@@LINK=) は、参照先の項目へのEPUB内のリンクにする
+func renderEPUBDefinition(def string, anchors epubAnchors) string {
+	var b strings.Builder
+	for _, paragraph := range splitParagraphs(def) {
+		if paragraph == mergeSeparator {
+			b.WriteString("<hr/>")
+			continue
+		}
+		if target, ok := strings.CutPrefix(paragraph, "@@@LINK="); ok {
+			b.WriteString(`<p class="link">` + anchors.link(strings.TrimSpace(target)) + "</p>")
+			continue
+		}
+		text := renderDomainLabels(renderRuby(html.EscapeString(paragraph)))
+		text = reEscapedPDICLink.ReplaceAllStringFunc(text, func(s string) string {
+			return anchors.link(html.UnescapeString(reEscapedPDICLink.FindStringSubmatch(s)[1]))
+		})
+		if class := paragraphClass(paragraph); class != "" {
+			b.WriteString(`<p class="` + class + `">` + text + "</p>")
+		} else {
+			b.WriteString("<p>" + text + "</p>")
+		}
+	}
+	return b.String()
+}
+
+// writeEPUBFile はエントリを EPUB 3 の用語集として書き出す
+// 辞書の読み込みに対応していない電子書籍リーダーでも、目次から先頭の文字の章へ移動し、参照をたどって読めるようにする
+// 古いリーダー向けに、EPUB 2 の目次 (toc.ncx) も添える
+func writeEPUBFile(path, bookName, version string, entries []DictionaryEntry, wopts WriteOptions) error {
+	// 書き込みを始める前に、テーマの指定に誤りがないことを確認しておく
+	css, err := loadThemeCSS(wopts.Theme, wopts.AccentColor)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeEPUB(file, bookName, version, cmp.Or(wopts.Author, defaultIfoAuthor), fileTimestamp(wopts.BuildTime), entries, css); err != nil {
+		file.Close()
+		return errorf("EPUBファイルの書き込みに失敗: %w", err)
+	}
+	return file.Close()
+}
+
+// epubFile は EPUB に収めるファイル
+type epubFile struct {
+	name    string // EPUB 内のパス
+	content string
+}
+
+// writeEPUB は EPUB を w に書き出す
+// EPUB の仕様により、mimetype を無圧縮でアーカイブの先頭に置く
+// modified はパッケージ文書の更新日時と、アーカイブ内の各ファイルの日時として記録する
+func writeEPUB(w io.Writer, bookName, version, author string, modified time.Time, entries []DictionaryEntry, css []byte) error {
+	chapters := groupEPUBChapters(entries)
+	anchors := newEPUBAnchors(chapters)
+
+	zw := zip.NewWriter(w)
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store, Modified: modified})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	files := []epubFile{
+		{"META-INF/container.xml", epubContainer},
+		{"OEBPS/content.opf", epubPackageDocument(bookName, version, author, modified, chapters)},
+		{"OEBPS/nav.xhtml", epubNavDocument(bookName, chapters)},
+		{"OEBPS/toc.ncx", epubNCX(bookName, version, chapters)},
+		{"OEBPS/style.css", string(css)},
+	}
+	for _, chapter := range chapters {
+		files = append(files, epubFile{"OEBPS/" + chapter.fileName(), epubChapterDocument(chapter, anchors)})
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// epubContainer は EPUB のパッケージ文書の場所を示す META-INF/container.xml
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// epubIdentifier は辞書の名前と版から、書籍の識別子を返す
+func epubIdentifier(bookName, version string) string {
+	return "urn:eijiro-converter:" + bookName + ":" + version
+}
+
+// epubPackageDocument は書籍の情報と、各章の読む順序を記したパッケージ文書 (content.opf) を返す
+func epubPackageDocument(bookName, version, author string, modified time.Time, chapters []epubChapter) string {
+	var manifest, spine strings.Builder
+	for _, chapter := range chapters {
+		fmt.Fprintf(&manifest, "    <item id=\"chapter-%s\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", chapter.key, chapter.fileName())
+		fmt.Fprintf(&spine, "    <itemref idref=\"chapter-%s\"/>\n", chapter.key)
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="ja">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">` + html.EscapeString(epubIdentifier(bookName, version)) + `</dc:identifier>
+    <dc:title>` + html.EscapeString(bookName) + `</dc:title>
+    <dc:language>ja</dc:language>
+    <dc:creator>` + html.EscapeString(author) + `</dc:creator>
+    <meta property="dcterms:modified">` + modified.UTC().Format("2006-01-02T15:04:05Z") + `</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+` + manifest.String() + `  </manifest>
+  <spine toc="ncx">
+` + spine.String() + `  </spine>
+</package>
+`
+}
+
+// epubNavDocument は各章への目次 (nav.xhtml) を返す
+func epubNavDocument(bookName string, chapters []epubChapter) string {
+	var items strings.Builder
+	for _, chapter := range chapters {
+		fmt.Fprintf(&items, "      <li><a href=\"%s\">%s</a></li>\n", chapter.fileName(), html.EscapeString(chapter.title))
+	}
+	return epubXHTMLHeader(bookName) + `<body>
+  <nav epub:type="toc" id="toc">
+    <h1>目次</h1>
+    <ol>
+` + items.String() + `    </ol>
+  </nav>
+</body>
+</html>
+`
+}
+
+// epubNCX は EPUB 2 のリーダー向けの目次 (toc.ncx) を返す
+func epubNCX(bookName, version string, chapters []epubChapter) string {
+	var points strings.Builder
+	for i, chapter := range chapters {
+		fmt.Fprintf(&points, "    <navPoint id=\"nav-%s\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			chapter.key, i+1, html.EscapeString(chapter.title), chapter.fileName())
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="` + html.EscapeString(epubIdentifier(bookName, version)) + `"/>
+  </head>
+  <docTitle><text>` + html.EscapeString(bookName) + `</text></docTitle>
+  <navMap>
+` + points.String() + `  </navMap>
+</ncx>
+`
+}
+
+// epubChapterDocument は章の見出し語と定義を、定義リストとして並べた XHTML を返す
+// 各項目には通し番号の ID を振り、ほかの項目の参照からリンクできるようにする
+func epubChapterDocument(chapter epubChapter, anchors epubAnchors) string {
+	var b strings.Builder
+	b.WriteString(epubXHTMLHeader(chapter.title))
+	b.WriteString("<body>\n<h1>" + html.EscapeString(chapter.title) + "</h1>\n<dl>\n")
+	for i, entry := range chapter.entries {
+		b.WriteString(`<dt id="` + epubEntryID(chapter.first+i) + `">` + html.EscapeString(entry.Headword) + "</dt>\n")
+		b.WriteString("<dd>" + renderEPUBDefinition(entry.Definition, anchors) + "</dd>\n")
+	}
+	b.WriteString("</dl>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// epubXHTMLHeader は EPUB の XHTML 文書の先頭 (<head> まで) を返す
+func epubXHTMLHeader(title string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="ja" lang="ja">
+<head>
+  <meta charset="UTF-8"/>
+  <title>` + html.EscapeString(title) + `</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+`
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestGroupEPUBChapters は見出し語が先頭の文字ごとの章に分かれ、英字以外の章が最後になることを検証します。
+func TestGroupEPUBChapters(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "door"}, {Headword: "扉"}, {Headword: "Apple"}, {Headword: "(be) fond of ～"}, {Headword: "dog"},
+	}
+	chapters := groupEPUBChapters(entries)
+	var got [][]string
+	for _, chapter := range chapters {
+		var headwords []string
+		for _, entry := range chapter.entries {
+			headwords = append(headwords, entry.Headword)
+		}
+		got = append(got, append([]string{chapter.title}, headwords...))
+	}
+	expected := [][]string{{"A", "Apple"}, {"D", "dog", "door"}, {"その他", "(be) fond of ～", "扉"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, got)
+	}
+	if chapters[2].first != 3 {
+		t.Errorf("章の最初の項目の通し番号が違います: %d", chapters[2].first)
+	}
+}
+
+// TestRenderEPUBDefinition は参照がEPUB内のリンクになり、参照先のない参照は文字列として残ることを検証します。
+func TestRenderEPUBDefinition(t *testing.T) {
+	anchors := newEPUBAnchors(groupEPUBChapters([]DictionaryEntry{{Headword: "bunkum"}, {Headword: "united nations"}}))
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"PDICリンク", "たわごと<→bunkum>", `<p>たわごと<a href="chapter-b.xhtml#e0">→bunkum</a></p>`},
+		{"大文字・小文字の違い", "<→United Nations>", `<p><a href="chapter-u.xhtml#e1">→United Nations</a></p>`},
+		{"参照先がない", "<→nonsense>", "<p>→nonsense</p>"},
+		{"リンク情報", "{名} 熊\n@@@LINK=bunkum", `<p>{名} 熊</p><p class="link"><a href="chapter-b.xhtml#e0">→bunkum</a></p>`},
+		{"用例と区切り", "■I see. : なるほど\n---\n扉｛とびら｝", `<p class="example">■I see. : なるほど</p><hr/><p><ruby>扉<rt>とびら</rt></ruby></p>`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := renderEPUBDefinition(tc.input, anchors); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// readEPUBTestFile は EPUB のファイルを読み込み、収めたファイルの名前の一覧と内容を返す
+func readEPUBTestFile(t *testing.T, data []byte) ([]string, map[string]string, []*zip.File) {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("EPUBをzipとして読み込めません: %v", err)
+	}
+	var names []string
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		names = append(names, f.Name)
+		contents[f.Name] = string(content)
+	}
+	return names, contents, zr.File
+}
+
+// TestWriteEPUB は mimetype が無圧縮で先頭に置かれ、目次と章がそろった EPUB が書き出されることを検証します。
+func TestWriteEPUB(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "bunkum", Definition: "{名} たわごと"},
+		{Headword: "balderdash", Definition: "{名} たわごと<→bunkum>"},
+		{Headword: "扉", Definition: "door"},
+	}
+	var buf bytes.Buffer
+	if err := writeEPUB(&buf, "Test", "1.0", "tester", reproducibleEpoch, entries, []byte("p {}")); err != nil {
+		t.Fatalf("writeEPUBでエラーが発生しました: %v", err)
+	}
+	names, contents, files := readEPUBTestFile(t, buf.Bytes())
+
+	expectedNames := []string{
+		"mimetype", "META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml", "OEBPS/toc.ncx", "OEBPS/style.css",
+		"OEBPS/chapter-b.xhtml", "OEBPS/chapter-other.xhtml",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("期待値: %v, 実際: %v", expectedNames, names)
+	}
+	if files[0].Method != zip.Store || contents["mimetype"] != "application/epub+zip" {
+		t.Errorf("mimetype が無圧縮で書き出されていません")
+	}
+	for _, want := range []string{`href="chapter-b.xhtml">B</a>`, `href="chapter-other.xhtml">その他</a>`} {
+		if !strings.Contains(contents["OEBPS/nav.xhtml"], want) {
+			t.Errorf("目次に %q が含まれていません", want)
+		}
+	}
+	if !strings.Contains(contents["OEBPS/content.opf"], "<dc:creator>tester</dc:creator>") {
+		t.Errorf("パッケージ文書に作者が含まれていません")
+	}
+	chapter := contents["OEBPS/chapter-b.xhtml"]
+	for _, want := range []string{`<dt id="e0">balderdash</dt>`, `<dt id="e1">bunkum</dt>`, `<a href="chapter-b.xhtml#e1">→bunkum</a>`} {
+		if !strings.Contains(chapter, want) {
+			t.Errorf("章に %q が含まれていません", want)
+		}
+	}
+}
+
+// TestRunConversionEPUB は -format epub で、StarDict の代わりに EPUB が書き出されることを検証します。
+func TestRunConversionEPUB(t *testing.T) {
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n")
+	dir := t.TempDir()
+	if _, err := runConversion(context.Background(), ConvertConfig{InputFile: path, OutputDir: dir, BookName: "Test", Format: FormatEPUB, MergeStrategy: MergeConcat}); err != nil {
+		t.Fatalf("runConversionでエラーが発生しました: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Test.epub")); err != nil {
+		t.Errorf("EPUBが書き出されていません: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Test.ifo")); err == nil {
+		t.Errorf("-format epub でStarDictファイルが書き出されました")
+	}
+
+	if _, err := runConversion(context.Background(), ConvertConfig{InputFile: path, OutputDir: dir, BookName: "Test", Format: "pdf", MergeStrategy: MergeConcat}); err == nil {
+		t.Error("未対応の出力形式でエラーになりませんでした")
+	}
+}
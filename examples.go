@@ -0,0 +1,28 @@
+package main
+
+import "strings"
+
+// examplesBookSuffix は用例辞書のファイル名に付ける接尾辞
+const examplesBookSuffix = "-examples"
+
+// buildExampleEntries はパース済みエントリから用例のみを集めた用例辞書のエントリを生成する
+// 見出し語は本体の辞書と同様に小文字に統一し、同じ見出し語の用例は出現順にまとめる
+func buildExampleEntries(entries []DictionaryEntry) []DictionaryEntry {
+	var exampleEntries []DictionaryEntry
+	positions := make(map[string]int)
+
+	for _, entry := range entries {
+		if len(entry.Examples) == 0 {
+			continue
+		}
+		key := strings.ToLower(entry.Headword)
+		lines := "■" + strings.Join(entry.Examples, "\n■")
+		if pos, exists := positions[key]; exists {
+			exampleEntries[pos].Definition += "\n" + lines
+			continue
+		}
+		positions[key] = len(exampleEntries)
+		exampleEntries = append(exampleEntries, DictionaryEntry{Headword: key, Definition: lines})
+	}
+	return exampleEntries
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// writeEijiroTestFile はテスト用の英辞郎形式テキストをShift_JISで書き出し、そのパスを返します。
+func writeEijiroTestFile(t *testing.T, content string) string {
+	t.Helper()
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(content)
+	if err != nil {
+		t.Fatalf("Shift_JISへの変換に失敗しました: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "EIJIRO-TEST.TXT")
+	if err := os.WriteFile(path, []byte(encoded), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestSplitExamples は用例が定義から分離され、用例辞書にまとめられることを検証します。
+func TestSplitExamples(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■door {名} : 扉■・Close the door. : 扉を閉めて。",
+		"■door {動} : 戸を付ける■・Open the door. : 扉を開けて。",
+		"■Door {名} : ドア■・Door prize : 入場者への景品",
+		"■know {動} : 知っている",
+	}, "\n"))
+
+	entries, err := parseEijiro(path, ParseOptions{SplitExamples: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Definition, "■") {
+			t.Errorf("'%s' の定義に用例が残っています: %q", entry.Headword, entry.Definition)
+		}
+	}
+
+	exampleEntries := buildExampleEntries(entries)
+	if len(exampleEntries) != 1 {
+		t.Fatalf("用例辞書のエントリ数が不正です: %v", exampleEntries)
+	}
+	expected := "■Close the door. : 扉を閉めて。\n■Open the door. : 扉を開けて。\n■Door prize : 入場者への景品"
+	if exampleEntries[0].Headword != "door" || exampleEntries[0].Definition != expected {
+		t.Errorf("用例辞書のエントリが不正です: %+v", exampleEntries[0])
+	}
+}
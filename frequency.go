@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// frequencyList は頻度リストの単語 (小文字) から順位 (1始まり) への対応
+type frequencyList map[string]int
+
+// loadFrequencyList は "単語<TAB>順位" の形式の頻度リストを読み込む
+// 空行と # で始まる行は読み飛ばし、同じ単語が複数回現れた場合は最も高い (小さい) 順位を使う
+func loadFrequencyList(path string) (frequencyList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := make(frequencyList)
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, rankStr, found := strings.Cut(line, "\t")
+		word = strings.ToLower(strings.TrimSpace(word))
+		rank, err := strconv.Atoi(strings.TrimSpace(rankStr))
+		if !found || word == "" || err != nil || rank < 1 {
+			return nil, errorf("%s:%d: 頻度リストの行は \"単語<TAB>順位\" の形式で、順位は1以上の整数で指定してください: %q", path, lineNo, line)
+		}
+		if existing, ok := list[word]; !ok || rank < existing {
+			list[word] = rank
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// rank は単語の順位を返す (リストにない場合は0)
+func (l frequencyList) rank(word string) int {
+	return l[strings.ToLower(word)]
+}
+
+// trimToTopN はパースしたエントリを、頻度リストの上位 n 位までの見出し語に絞り込む
+// 変化形のリンクのエントリは、リンク先の見出し語が残る場合にのみ残す
+// 変化形自身が上位の語でなくても、学習者が変化形から原形を引けるようにするため
+// 除いたエントリの数も、エントリ数の確認のために返す
+func trimToTopN(entries []DictionaryEntry, list frequencyList, n int) (trimmed []DictionaryEntry, dropped int) {
+	inTop := func(word string) bool {
+		r := list.rank(word)
+		return r > 0 && r <= n
+	}
+	trimmed = make([]DictionaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		word := entry.Headword
+		if target, ok := strings.CutPrefix(entry.Definition, "@@@LINK="); ok {
+			word = target
+		}
+		if inTop(word) {
+			trimmed = append(trimmed, entry)
+		} else {
+			dropped++
+		}
+	}
+	return trimmed, dropped
+}
+
+// annotateFrequency はマージ済みのエントリに頻度リストの順位を記録する
+// display が true の場合は、定義の末尾に「【頻度】123位」として追記する
+func annotateFrequency(entries []DictionaryEntry, list frequencyList, display bool) (annotated int) {
+	for i := range entries {
+		rank := list.rank(entries[i].Headword)
+		if rank == 0 {
+			continue
+		}
+		entries[i].FrequencyRank = rank
+		if display {
+			entries[i].Definition += fmt.Sprintf("\n【頻度】%d位", rank)
+		}
+		annotated++
+	}
+	return annotated
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFrequencyTestFile は頻度リストを一時ファイルに書き出し、そのパスを返す
+func writeFrequencyTestFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "freq.tsv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("頻度リストの作成に失敗しました: %v", err)
+	}
+	return path
+}
+
+// TestLoadFrequencyList は頻度リストの読み込みと、形式の誤りの検出を検証します。
+func TestLoadFrequencyList(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected frequencyList
+		wantErr  bool
+	}{
+		{"通常", "the\t1\nknow\t2\n", frequencyList{"the": 1, "know": 2}, false},
+		{"コメントと空行", "# word\trank\n\nDoor\t3\n", frequencyList{"door": 3}, false},
+		{"重複は高い順位", "know\t5\nknow\t2\n", frequencyList{"know": 2}, false},
+		{"順位がない", "know\n", nil, true},
+		{"順位が数値でない", "know\tfirst\n", nil, true},
+		{"順位が0", "know\t0\n", nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			list, err := loadFrequencyList(writeFrequencyTestFile(t, tc.content))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("エラーの有無が違います: %v", err)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(list, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, list)
+			}
+		})
+	}
+}
+
+// TestTrimToTopN は上位の見出し語と、そのリンクのみが残ることを検証します。
+func TestTrimToTopN(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "know", Definition: "{動} 知っている"},
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "Zebra", Definition: "{名} シマウマ"},
+		{Headword: "knew", Definition: "@@@LINK=know"},
+		{Headword: "doors", Definition: "@@@LINK=door"},
+	}
+	list := frequencyList{"know": 1, "zebra": 2, "door": 3}
+
+	trimmed, dropped := trimToTopN(entries, list, 2)
+	var got []string
+	for _, entry := range trimmed {
+		got = append(got, entry.Headword)
+	}
+	expected := []string{"know", "Zebra", "knew"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+	if dropped != len(entries)-len(expected) {
+		t.Errorf("除いたエントリの数が違います: %d", dropped)
+	}
+}
+
+// TestRunConversionWithFrequencyList は順位が定義とJSONLに記録され、-top-n で絞り込まれることを検証します。
+func TestRunConversionWithFrequencyList(t *testing.T) {
+	installFakeDictzip(t)
+	dir := t.TempDir()
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n■know {動} : 知っている【変化】《動》knows | knew\n■zebra {名} : シマウマ\n")
+	freqPath := writeFrequencyTestFile(t, "know\t1\ndoor\t2\nzebra\t300\n")
+	jsonlPath := filepath.Join(dir, "out.jsonl")
+
+	summary, err := runConversion(context.Background(), ConvertConfig{
+		InputFile:     path,
+		OutputDir:     filepath.Join(dir, "out"),
+		BookName:      "Test",
+		MergeStrategy: MergeConcat,
+		JSONLPath:     jsonlPath,
+		FrequencyList: freqPath,
+		ShowFrequency: true,
+		TopN:          100,
+		StrictCounts:  true,
+	})
+	if err != nil {
+		t.Fatalf("runConversionでエラーが発生しました: %v", err)
+	}
+	// zebra は上位100位に入らないため除かれ、know の変化形は残る
+	if summary.FinalEntries != 4 {
+		t.Errorf("エントリ数が違います: %d", summary.FinalEntries)
+	}
+
+	file, err := os.Open(jsonlPath)
+	if err != nil {
+		t.Fatalf("JSONLファイルを開けません: %v", err)
+	}
+	defer file.Close()
+	ranks := make(map[string]int)
+	definitions := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record jsonlRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("JSONLの解析に失敗しました: %v", err)
+		}
+		ranks[record.Headword] = record.FrequencyRank
+		definitions[record.Headword] = record.Definition
+	}
+	expected := map[string]int{"door": 2, "know": 1, "knows": 0, "knew": 0}
+	if !reflect.DeepEqual(ranks, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, ranks)
+	}
+	if definitions["door"] != "{名} 扉\n【頻度】2位" {
+		t.Errorf("定義に順位が追記されていません: %q", definitions["door"])
+	}
+
+	if _, err := runConversion(context.Background(), ConvertConfig{InputFile: path, OutputDir: filepath.Join(dir, "out2"), BookName: "Test", MergeStrategy: MergeConcat, TopN: 10}); err == nil {
+		t.Errorf("頻度リストなしの -top-n でエラーになりませんでした")
+	}
+}
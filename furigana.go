@@ -0,0 +1,98 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// furiganaDict は漢字を含む表記から、その読み仮名を引く辞書
+//
+// 形態素解析器 (kagome など) は外部の辞書データを含む大きな依存関係になるため使わず、JMdict の表記との完全一致のみで読み仮名を振る
+// 文脈から読みを判断できないため、漢字の並び全体が読みの一つに定まる表記と一致する場合に限り、誤った読み仮名を振らないことを優先する
+type furiganaDict map[string]string
+
+// newFuriganaDict は JMdict の索引から、読みが一つに定まる漢字表記の辞書を作成する
+// 読みが複数ある JMdict のエントリ (「日本」の にほん と にっぽん など) や、
+// 同じ表記で読みの異なるエントリがある場合 (「生物」の せいぶつ と なまもの など) は、文脈によって読みが変わるため辞書に含めない
+func newFuriganaDict(index jmdictIndex) furiganaDict {
+	dict := make(furiganaDict)
+	ambiguous := make(map[string]bool)
+	for key, entries := range index {
+		for _, entry := range entries {
+			if len(entry.Readings) == 0 || !slices.Contains(entry.Kanji, key) || !isKanjiWord(key) {
+				continue
+			}
+			if reading, ok := dict[key]; len(entry.Readings) > 1 || ok && reading != entry.Readings[0] {
+				ambiguous[key] = true
+			}
+			dict[key] = entry.Readings[0]
+		}
+	}
+	for key := range ambiguous {
+		delete(dict, key)
+	}
+	return dict
+}
+
+// isKanji は r が漢字 (々〆ヶ を含む) かどうかを返す
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) || strings.ContainsRune("々〆ヶ", r)
+}
+
+// isKanjiWord は s が漢字のみからなるかどうかを返す
+// 読み仮名は直前の漢字の並びに振るため (renderRuby)、送り仮名を含む表記 (受け取る など) は対象にしない
+func isKanjiWord(s string) bool {
+	return s != "" && !strings.ContainsFunc(s, func(r rune) bool { return !isKanji(r) })
+}
+
+// addFurigana は定義の日本語に含まれる漢字の語に、英辞郎と同じ "扉｛とびら｝" の形式で読み仮名を加える
+// 漢字の並び全体が辞書の表記と一致する場合のみ読み仮名を振る
+// 複合語を辞書にある部分に区切ると、誤った区切り (大学生活 -> 大学生 + 活) で誤った読み仮名を振るおそれがあるため、一致しない並びはそのまま残す
+// 既に読み仮名がある語や、ラベル (【…】)・読み仮名 (｛…｝)・PDICリンク (<→…>) の中は対象外とする
+func (d furiganaDict) addFurigana(def string) string {
+	var b strings.Builder
+	depth := 0 // 対象外の括弧の深さ
+	for i := 0; i < len(def); {
+		r, size := utf8.DecodeRuneInString(def[i:])
+		switch r {
+		case '【', '｛', '<':
+			depth++
+		case '】', '｝', '>':
+			if depth > 0 {
+				depth--
+			}
+		}
+		if depth > 0 || !isKanji(r) {
+			b.WriteString(def[i : i+size])
+			i += size
+			continue
+		}
+		// 英辞郎の読み仮名が既にある漢字の並びや、辞書にない漢字の並びは、そのまま残す
+		run := strings.IndexFunc(def[i:], func(r rune) bool { return !isKanji(r) })
+		if run < 0 {
+			run = len(def) - i
+		}
+		word := def[i : i+run]
+		if reading, ok := d[word]; ok && !strings.HasPrefix(def[i+run:], "｛") {
+			b.WriteString(word + "｛" + reading + "｝")
+		} else {
+			b.WriteString(word)
+		}
+		i += run
+	}
+	return b.String()
+}
+
+// addFuriganaToEntries は各エントリの定義に読み仮名を加え、読み仮名を加えたエントリの数を返す
+func addFuriganaToEntries(entries []DictionaryEntry, dict furiganaDict) int {
+	annotated := 0
+	for i := range entries {
+		if def := dict.addFurigana(entries[i].Definition); def != entries[i].Definition {
+			entries[i].Definition = def
+			annotated++
+		}
+	}
+	return annotated
+}
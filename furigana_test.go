@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNewFuriganaDict は読みが一つに定まる漢字のみの表記だけが辞書に含まれることを検証します。
+func TestNewFuriganaDict(t *testing.T) {
+	door := &jmdictEntry{Kanji: []string{"扉"}, Readings: []string{"とびら"}}
+	japan := &jmdictEntry{Kanji: []string{"日本"}, Readings: []string{"にほん", "にっぽん"}}
+	creature := &jmdictEntry{Kanji: []string{"生物"}, Readings: []string{"せいぶつ"}}
+	rawFood := &jmdictEntry{Kanji: []string{"生物"}, Readings: []string{"なまもの"}}
+	receive := &jmdictEntry{Kanji: []string{"受け取る"}, Readings: []string{"うけとる"}}
+	index := jmdictIndex{
+		"扉": {door}, "とびら": {door},
+		"日本":   {japan},
+		"生物":   {creature, rawFood},
+		"受け取る": {receive},
+	}
+	expected := furiganaDict{"扉": "とびら"}
+	if got := newFuriganaDict(index); !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, got)
+	}
+}
+
+// TestAddFurigana は漢字の並びに読み仮名が加わり、対象外の部分はそのまま残ることを検証します。
+func TestAddFurigana(t *testing.T) {
+	dict := furiganaDict{"扉": "とびら", "電子": "でんし", "計算機": "けいさんき", "医学": "いがく", "大学生": "だいがくせい", "生活": "せいかつ"}
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"漢字の語", "{名} 扉、戸", "{名} 扉｛とびら｝、戸"},
+		{"漢字の並び全体が一致する", "大学生の生活", "大学生｛だいがくせい｝の生活｛せいかつ｝"},
+		{"辞書にある部分に区切らない", "電子計算機", "電子計算機"},
+		{"誤った区切りになる複合語", "大学生活", "大学生活"},
+		{"辞書にない部分を含む", "超電子", "超電子"},
+		{"読み仮名が既にある", "扉｛と｝", "扉｛と｝"},
+		{"ラベルの中", "扉【医学】", "扉｛とびら｝【医学】"},
+		{"漢字なし", "door", "door"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := dict.addFurigana(tc.input); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import "strings"
+
+// minFuzzyWordLength は綴り誤りの検索キーを生成する見出し語の最小の長さ
+// 短い単語の綴りを変えると別の単語と紛らわしくなるため、対象外とする
+const minFuzzyWordLength = 4
+
+// confusableSuffixes は綴りを取り違えやすい接尾辞の組 (independent -> independant など)
+var confusableSuffixes = [][2]string{
+	{"ance", "ence"},
+	{"ant", "ent"},
+	{"able", "ible"},
+	{"ary", "ery"},
+	{"ise", "ize"},
+}
+
+// misspellings は単語の綴り誤りとしてよくある形を返す
+// 自前のあいまい検索を持たない辞書アプリでも誤った綴りから引けるよう、次の規則をそれぞれ一か所だけ適用した形を生成する
+//   - ie と ei の入れ替え (receive -> recieve)
+//   - 重ねた文字を一つにする (accommodate -> accomodate)
+//   - 取り違えやすい接尾辞の入れ替え (independent -> independant)
+func misspellings(word string) []string {
+	var variants []string
+	add := func(variant string) {
+		if variant != word {
+			variants = appendUnique(variants, variant)
+		}
+	}
+	for i := 0; i+1 < len(word); i++ {
+		switch word[i : i+2] {
+		case "ie":
+			add(word[:i] + "ei" + word[i+2:])
+		case "ei":
+			add(word[:i] + "ie" + word[i+2:])
+		}
+		if word[i] == word[i+1] {
+			add(word[:i] + word[i+1:])
+		}
+	}
+	for _, pair := range confusableSuffixes {
+		for _, suffix := range [][2]string{pair, {pair[1], pair[0]}} {
+			if stem, ok := strings.CutSuffix(word, suffix[0]); ok && len(stem) >= 2 {
+				add(stem + suffix[1])
+			}
+		}
+	}
+	return variants
+}
+
+// addFuzzyKeys は英単語1語の見出し語に、綴り誤りの形を検索用キーワードとして加え、加えたキーワードの数を返す
+// 別の見出し語と同じ綴りになる形 (diner と dinner など) は、その見出し語を引けなくしないよう加えない
+func addFuzzyKeys(entries []DictionaryEntry) int {
+	headwords := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		headwords[strings.ToLower(entry.Headword)] = true
+	}
+	added := 0
+	for i := range entries {
+		entry := &entries[i]
+		word := strings.ToLower(entry.Headword)
+		if len(word) < minFuzzyWordLength || !reSimpleWord.MatchString(word) {
+			continue
+		}
+		for _, variant := range misspellings(word) {
+			if headwords[variant] {
+				continue
+			}
+			before := len(entry.Keywords)
+			entry.Keywords = appendUnique(entry.Keywords, variant)
+			added += len(entry.Keywords) - before
+		}
+	}
+	return added
+}
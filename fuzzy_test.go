@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMisspellings はよくある綴り誤りの形が生成されることを検証します。
+func TestMisspellings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"ieとeiの入れ替え", "receive", []string{"recieve"}},
+		{"重ねた文字", "accommodate", []string{"acommodate", "accomodate"}},
+		{"接尾辞の取り違え", "independent", []string{"independant"}},
+		{"規則に当てはまらない", "door", []string{"dor"}},
+		{"該当なし", "cat", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := misspellings(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestAddFuzzyKeys は綴り誤りの形が検索用キーワードに加わり、別の見出し語と同じ綴りの形は加わらないことを検証します。
+func TestAddFuzzyKeys(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "receive", Definition: "{動} 受け取る"},
+		{Headword: "dinner", Definition: "{名} 夕食"},
+		{Headword: "diner", Definition: "{名} 食事をする人"},
+		{Headword: "ice cream", Definition: "{名} アイスクリーム"},
+		{Headword: "Believe", Definition: "{動} 信じる", Keywords: []string{"believe"}},
+	}
+	if added := addFuzzyKeys(entries); added != 2 {
+		t.Errorf("加えたキーワードの数が違います: %d", added)
+	}
+	expected := [][]string{{"recieve"}, nil, nil, nil, {"believe", "beleive"}}
+	for i, entry := range entries {
+		if !reflect.DeepEqual(entry.Keywords, expected[i]) {
+			t.Errorf("%s: 期待値: %v, 実際: %v", entry.Headword, expected[i], entry.Keywords)
+		}
+	}
+}
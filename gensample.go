@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// sampleLines は gensample サブコマンドが書き出す英辞郎形式のサンプルデータ
+// 英辞郎の内容は含まず、パーサーが扱う書式をひととおり含むように作った架空の見出し語と定義からなる
+// 行を追加する場合は、その行で確かめられる書式をコメントに書いておくこと
+var sampleLines = []string{
+	// どの見出し語にも属さない行 (無視した行として数える)
+	"これはどの見出し語にも属さない行です",
+	// 品詞・変化形・発音・カタカナ発音・単語レベル・分節と、同じ行の用例 (■・)
+	"■door {名} : 扉、戸、ドア【変化】《複》doors、【発音】do':r、【＠】ドー、【レベル】1、【分節】door■・Please close the door. : ドアを閉めてください。",
+	// 補足説明 (◆) の行
+	"◆玄関の扉は front door と言う。",
+	// 同じ見出し語の別の品詞の行と、読み仮名 (｛…｝)
+	"■door {動} : 〔門を〕閉ざす｛とざす｝",
+	// 動詞の活用形から原形へのリンク
+	"■knew : knowの過去形",
+	// 「|」で区切った複数の変化形と、強勢のある発音 (【発音！】)
+	"■know {動} : 知っている【変化】《動》knows | knowing | knew | known、【発音！】no'u",
+	// 番号付きの語義と、ラベル (【大学入試】)
+	"■run {動} : 1. 走る、2. 運営する【大学入試】",
+	// 分節の区切り (・) と、補足説明に書かれた略語 (【略】)
+	"■tactical {形} : 戦術的な【分節】tac・ti・cal",
+	"■United Nations : 国際連合◆【略】UN",
+	// 分野のラベル (《…》)
+	"■virus {名} : 《医》ウイルス、《コ》コンピューター・ウイルス",
+	// 別の見出し語の参照 (＝<→…>) と、定義中のPDICリンク (<→…>)
+	"■color {名} : 色",
+	"■colour {名} : ＝<→color>",
+	"■hue {名} : 色合い◆<→color>",
+	// 括弧と目的語の位置を表す記号 (～) を含む成句
+	"■(be) fond of ～ : ～が好きである",
+	// カタカナのみの訳語 (外来語)
+	"■computer {名} : コンピューター",
+	// Shift_JIS特有の文字: 全角英数字、NEC特殊文字、半角カナ、波ダッシュ
+	"■ＵＳＢ memory {名} : ＵＳＢメモリー、①記憶装置 ②㈱の製品名、ｶﾀｶﾅ、10～20GB",
+	// 空行
+	"",
+	// 大文字・小文字のみ異なる見出し語
+	"■Door {名} : ドア（固有名詞）",
+}
+
+// writeSample はサンプルデータを英辞郎ファイルと同じ Shift_JIS・CRLF の形式で書き出す
+func writeSample(w io.Writer) error {
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(strings.Join(sampleLines, "\r\n") + "\r\n")
+	if err != nil {
+		return errorf("サンプルデータをShift_JISに変換できません: %w", err)
+	}
+	_, err = io.WriteString(w, encoded)
+	return err
+}
+
+// runGenSampleCommand は gensample サブコマンドを実行する
+// 英辞郎ファイルを共有せずにオプションの組み合わせを試したり、不具合を報告したりできるよう、架空の内容のサンプルデータを書き出す
+func runGenSampleCommand(args []string) error {
+	fs := flag.NewFlagSet("gensample", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter gensample [オプション]"))
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", "EIJIRO-SAMPLE.TXT", "サンプルデータを書き出すファイル名 (- の場合は標準出力)")
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if *output == "-" {
+		return writeSample(os.Stdout)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeSample(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	logger.Info(sprintf("サンプルデータを書き出しました: %s", *output))
+	return nil
+}
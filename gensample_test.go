@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteSample はサンプルデータが英辞郎ファイルとして読み込め、主な書式がパースされることを検証します。
+func TestWriteSample(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSample(&buf); err != nil {
+		t.Fatalf("writeSampleでエラーが発生しました: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("\r\n")) {
+		t.Error("改行がCRLFになっていません")
+	}
+	path := filepath.Join(t.TempDir(), "EIJIRO-SAMPLE.TXT")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, stats, err := parseEijiroWithStats(path, ParseOptions{AbbreviationLinks: true, ResolveAliases: true})
+	if err != nil {
+		t.Fatalf("サンプルデータのパースでエラーが発生しました: %v", err)
+	}
+	if stats.Lines != len(sampleLines) || stats.IgnoredLines != 1 {
+		t.Errorf("読み込んだ行の内訳が違います: %+v", stats)
+	}
+	if stats.AbbreviationLinks != 1 || stats.AliasLinks != 1 {
+		t.Errorf("略語と参照のリンクの数が違います: %+v", stats)
+	}
+
+	headwords := make(map[string]bool)
+	for _, entry := range resolveAndMergeEntries(entries) {
+		headwords[entry.Headword] = true
+	}
+	for _, headword := range []string{"door", "doors", "knew", "un", "ｕｓｂ memory", "(be) fond of ～"} {
+		if !headwords[headword] {
+			t.Errorf("見出し語 '%s' がありません", headword)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// reRubyReading は読み仮名(｛…｝)と、その直前の漢字の並びを取り出す正規表現
+var reRubyReading = regexp.MustCompile(`([\p{Han}々〆ヶ]+)｛(.*?)｝`)
+
+// reEscapedPDICLink はHTMLエスケープ後のPDICリンク(<→…>)を取り出す正規表現
+var reEscapedPDICLink = regexp.MustCompile(`&lt;→(.+?)&gt;`)
+
+// htmlRenderer は定義をHTML形式(sametypesequence=h)で出力する
+// paragraphTag が true の場合は段落を <p> で囲み、false の場合は <br> で区切る
+// accessiblePronunciation が true の場合は、定義の前に発音情報を読み上げ用の要素として置く
+// hyphenation が true の場合は、定義の前に見出し語の分節を置く
+type htmlRenderer struct {
+	paragraphTag            bool
+	accessiblePronunciation bool
+	hyphenation             bool
+}
+
+// Render は定義中の文字をエスケープし、読み仮名を <ruby> に、PDICリンクを bword:// のリンクに置き換えた上で段落を組み立てる
+// リンク先の定義との区切り("---")は <hr> になる
+func (r htmlRenderer) Render(entry DictionaryEntry) string {
+	var b strings.Builder
+	if entry.Audio != "" {
+		// 音声ファイルは res/ ディレクトリからの相対パスで参照する
+		b.WriteString(`<audio controls src="` + html.EscapeString(url.PathEscape(entry.Audio)) + `"></audio>`)
+		if !r.paragraphTag {
+			b.WriteString("<br>")
+		}
+	}
+
+	tag := "span"
+	if r.paragraphTag {
+		tag = "p"
+	}
+	// 分節は見出し語のすぐ下に表示するため、発音情報より前に置く
+	if r.hyphenation {
+		if hyphenation := renderHyphenation(entry, tag); hyphenation != "" {
+			b.WriteString(hyphenation)
+			if !r.paragraphTag {
+				b.WriteString("<br>")
+			}
+			// 定義中の【分節】は、表示した分節と重複するため取り除く
+			entry.Definition = reSyllabification.ReplaceAllString(entry.Definition, "")
+		}
+	}
+	if r.accessiblePronunciation {
+		if pron := renderAccessiblePronunciation(entry, tag); pron != "" {
+			b.WriteString(pron)
+			if !r.paragraphTag {
+				b.WriteString("<br>")
+			}
+		}
+	}
+
+	// <hr> の直後に <br> が続かないよう、直前に書いたものが段落かどうかを覚えておく
+	afterParagraph := false
+	for _, paragraph := range splitParagraphs(entry.Definition) {
+		if paragraph == mergeSeparator {
+			b.WriteString("<hr>")
+			afterParagraph = false
+			continue
+		}
+		text := renderDomainLabels(renderCrossReferences(renderRuby(html.EscapeString(paragraph))))
+		class := paragraphClass(paragraph)
+		switch {
+		case r.paragraphTag && class != "":
+			b.WriteString(`<p class="` + class + `">` + text + "</p>")
+		case r.paragraphTag:
+			b.WriteString("<p>" + text + "</p>")
+		default:
+			if afterParagraph {
+				b.WriteString("<br>")
+			}
+			if class != "" {
+				text = `<span class="` + class + `">` + text + "</span>"
+			}
+			b.WriteString(text)
+		}
+		afterParagraph = true
+	}
+	return b.String()
+}
+
+func (htmlRenderer) TypeSequence() string {
+	return "h" // 'h' はHTML形式を意味する
+}
+
+// paragraphClass はテーマのスタイルシートで装飾するための、段落の種類を表すクラス名を返す
+func paragraphClass(paragraph string) string {
+	switch {
+	case strings.HasPrefix(paragraph, "■"):
+		return "example" // 用例
+	case strings.HasPrefix(paragraph, "◆"):
+		return "note" // 補足説明
+	}
+	return ""
+}
+
+// renderCrossReferences はPDICリンク "<→bunkum>" を、参照先の見出し語へ移動できる bword:// のリンクに変換する
+// 引数はHTMLエスケープ済みの文字列で、参照先もエスケープ済みのまま属性値に使う
+func renderCrossReferences(s string) string {
+	return reEscapedPDICLink.ReplaceAllString(s, `<a href="bword://$1">→$1</a>`)
+}
+
+// renderRuby は "扉｛とびら｝" のような読み仮名を、直前の漢字に振る <ruby> 要素に変換する
+// 直前に漢字がない読み仮名はそのまま残す
+func renderRuby(s string) string {
+	return reRubyReading.ReplaceAllString(s, "<ruby>$1<rt>$2</rt></ruby>")
+}
@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+// TestHTMLRenderer は定義がHTML形式に変換されることを検証します。
+func TestHTMLRenderer(t *testing.T) {
+	testCases := []struct {
+		name         string
+		paragraphTag bool
+		definition   string
+		expected     string
+	}{
+		{
+			name:       "特殊文字のエスケープと改行",
+			definition: "{名} 扉 & 戸\n<b>",
+			expected:   "{名} 扉 &amp; 戸<br>&lt;b&gt;",
+		},
+		{
+			name:       "PDICリンクがbwordのリンクになる",
+			definition: "{名} たわごと<→bunkum>、<→kick the \"bucket\">",
+			expected:   `{名} たわごと<a href="bword://bunkum">→bunkum</a>、<a href="bword://kick the &#34;bucket&#34;">→kick the &#34;bucket&#34;</a>`,
+		},
+		{
+			name:       "読み仮名がrubyになる",
+			definition: "{名} 扉｛とびら｝、戸口｛とぐち｝",
+			expected:   "{名} <ruby>扉<rt>とびら</rt></ruby>、<ruby>戸口<rt>とぐち</rt></ruby>",
+		},
+		{
+			name:       "直前に漢字がない読み仮名は残す",
+			definition: "ドア｛どあ｝",
+			expected:   "ドア｛どあ｝",
+		},
+		{
+			name:       "原形の定義との区切りはhrになり、空行は取り除かれる",
+			definition: "{動} driveの過去形\n\n---\n{動} 運転する",
+			expected:   "{動} driveの過去形<hr>{動} 運転する",
+		},
+		{
+			name:         "段落をpで囲む",
+			paragraphTag: true,
+			definition:   "{名} 扉\n---\n◆ドア",
+			expected:     `<p>{名} 扉</p><hr><p class="note">◆ドア</p>`,
+		},
+		{
+			name:       "用例にはクラスが付く",
+			definition: "{名} 扉\n■Close the door.",
+			expected:   `{名} 扉<br><span class="example">■Close the door.</span>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := htmlRenderer{paragraphTag: tc.paragraphTag}
+			if got := r.Render(DictionaryEntry{Definition: tc.definition}); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestNewRenderer は出力オプションに応じた Renderer が選ばれることを検証します。
+func TestNewRenderer(t *testing.T) {
+	if r, _ := newRenderer(WriteOptions{}); r.TypeSequence() != "g" {
+		t.Errorf("プレーンテキストの sametypesequence が不正です: %s", r.TypeSequence())
+	}
+	if r, _ := newRenderer(WriteOptions{HTML: true, ParagraphStyle: "p"}); r.TypeSequence() != "h" {
+		t.Errorf("HTMLの sametypesequence が不正です: %s", r.TypeSequence())
+	}
+	if _, err := newRenderer(WriteOptions{HTML: true, ParagraphStyle: "div"}); err == nil {
+		t.Errorf("未対応の段落の形式でエラーになりませんでした")
+	}
+
+	got := plainRenderer{}.Render(DictionaryEntry{Definition: "{名} 扉\n\n■Close the door."})
+	if got != "{名} 扉\n■Close the door." {
+		t.Errorf("プレーンテキストの段落が不正です: %q", got)
+	}
+}
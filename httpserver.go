@@ -0,0 +1,130 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// HTTPサーバーに関する定数
+const (
+	httpDefaultAddr  = ":8080"
+	httpDefaultLimit = 20   // /prefix で返す件数の既定値
+	httpMaxLimit     = 1000 // /prefix で返す件数の上限
+)
+
+// webUIHTML はブラウザから検索するための1ページのUI
+// サーバーの /lookup と /prefix を利用し、LAN内の家族などがブラウザだけで辞書を引けるようにする
+//
+//go:embed web/index.html
+var webUIHTML []byte
+
+// apiServer は変換済みのエントリをJSONで返すHTTPサーバー
+// エントリは JSONL 出力と同じ形式 (schema/entry.schema.json) で返す
+type apiServer struct {
+	dict *Dictionary
+}
+
+// entryResponse は1件のエントリを返すレスポンス
+type entryResponse struct {
+	Entry jsonlRecord `json:"entry"`
+}
+
+// entriesResponse は複数のエントリを返すレスポンス
+type entriesResponse struct {
+	Entries []jsonlRecord `json:"entries"`
+}
+
+// errorResponse はエラーを返すレスポンス
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// handler はAPIのエンドポイントを登録したハンドラを返す
+//
+//	GET /lookup?q=word      見出し語(またはキーワード)に完全一致するエントリ
+//	GET /prefix?q=wo&limit=20 見出し語が前方一致するエントリの一覧
+//	GET /random             無作為に選んだエントリ
+//
+// あわせて / で検索用のUIを、/theme.css でそのスタイルシートを返す
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", handleWebUI)
+	mux.HandleFunc("GET /theme.css", handleWebUITheme)
+	mux.HandleFunc("GET /lookup", s.handleLookup)
+	mux.HandleFunc("GET /prefix", s.handlePrefix)
+	mux.HandleFunc("GET /random", s.handleRandom)
+	return mux
+}
+
+func (s *apiServer) handleLookup(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeJSONResponse(w, http.StatusBadRequest, errorResponse{Error: "パラメータ q を指定してください"})
+		return
+	}
+	entry, found := s.dict.Lookup(q)
+	if !found {
+		writeJSONResponse(w, http.StatusNotFound, errorResponse{Error: "見出し語が見つかりません: " + q})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, entryResponse{Entry: newJSONLRecord(entry)})
+}
+
+func (s *apiServer) handlePrefix(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeJSONResponse(w, http.StatusBadRequest, errorResponse{Error: "パラメータ q を指定してください"})
+		return
+	}
+	limit := httpDefaultLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > httpMaxLimit {
+			writeJSONResponse(w, http.StatusBadRequest, errorResponse{Error: "パラメータ limit は1から" + strconv.Itoa(httpMaxLimit) + "の整数で指定してください"})
+			return
+		}
+		limit = n
+	}
+	records := []jsonlRecord{}
+	for _, entry := range s.dict.Prefix(q, limit) {
+		records = append(records, newJSONLRecord(entry))
+	}
+	writeJSONResponse(w, http.StatusOK, entriesResponse{Entries: records})
+}
+
+func (s *apiServer) handleRandom(w http.ResponseWriter, r *http.Request) {
+	entry, found := s.dict.Random()
+	if !found {
+		writeJSONResponse(w, http.StatusNotFound, errorResponse{Error: "辞書にエントリがありません"})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, entryResponse{Entry: newJSONLRecord(entry)})
+}
+
+// handleWebUI は検索用のUIを返す
+func handleWebUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(webUIHTML)
+}
+
+// handleWebUITheme は検索用のUIのスタイルシートとして、HTML出力と同じ既定のテーマを返す
+func handleWebUITheme(w http.ResponseWriter, r *http.Request) {
+	css, err := loadThemeCSS(defaultTheme, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Write(css)
+}
+
+// writeJSONResponse は値をJSONに変換してレスポンスとして書き出す
+func writeJSONResponse(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(v)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestAPIServer はHTTPサーバーの各エンドポイントのレスポンスを検証します。
+func TestAPIServer(t *testing.T) {
+	server := httptest.NewServer((&apiServer{dict: NewDictionary([]DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉", Senses: []Sense{{POS: "名", Gloss: "扉"}}},
+		{Headword: "doorbell", Definition: "{名} 呼び鈴"},
+		{Headword: "know", Definition: "{動} 知っている", Keywords: []string{"knew"}},
+	})}).handler())
+	defer server.Close()
+
+	testCases := []struct {
+		name      string
+		path      string
+		status    int
+		headwords []string
+	}{
+		{name: "完全一致", path: "/lookup?q=Door", status: http.StatusOK, headwords: []string{"door"}},
+		{name: "キーワードでの完全一致", path: "/lookup?q=knew", status: http.StatusOK, headwords: []string{"know"}},
+		{name: "見つからない見出し語", path: "/lookup?q=gate", status: http.StatusNotFound},
+		{name: "検索語の指定なし", path: "/lookup", status: http.StatusBadRequest},
+		{name: "前方一致", path: "/prefix?q=doo", status: http.StatusOK, headwords: []string{"door", "doorbell"}},
+		{name: "前方一致の件数の制限", path: "/prefix?q=doo&limit=1", status: http.StatusOK, headwords: []string{"door"}},
+		{name: "前方一致の該当なし", path: "/prefix?q=x", status: http.StatusOK, headwords: []string{}},
+		{name: "不正な件数", path: "/prefix?q=doo&limit=0", status: http.StatusBadRequest},
+		{name: "無作為なエントリ", path: "/random", status: http.StatusOK},
+		{name: "未定義のエンドポイント", path: "/define?q=door", status: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.status {
+				t.Fatalf("期待するステータス: %d, 実際: %d", tc.status, resp.StatusCode)
+			}
+			if tc.headwords == nil {
+				return
+			}
+
+			var body struct {
+				Entry   *jsonlRecord  `json:"entry"`
+				Entries []jsonlRecord `json:"entries"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("JSONとして読み込めません: %v", err)
+			}
+			headwords := []string{}
+			if body.Entry != nil {
+				headwords = append(headwords, body.Entry.Headword)
+			}
+			for _, record := range body.Entries {
+				headwords = append(headwords, record.Headword)
+			}
+			if len(headwords) != len(tc.headwords) {
+				t.Fatalf("期待値: %v, 実際: %v", tc.headwords, headwords)
+			}
+			for i := range headwords {
+				if headwords[i] != tc.headwords[i] {
+					t.Errorf("期待値: %v, 実際: %v", tc.headwords, headwords)
+				}
+			}
+		})
+	}
+}
+
+// TestWebUI は検索用のUIとスタイルシートが返されることを検証します。
+func TestWebUI(t *testing.T) {
+	server := httptest.NewServer((&apiServer{dict: NewDictionary(nil)}).handler())
+	defer server.Close()
+
+	testCases := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{path: "/", contentType: "text/html; charset=utf-8", contains: `<input id="q"`},
+		{path: "/theme.css", contentType: "text/css; charset=utf-8", contains: "--accent"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != tc.contentType {
+				t.Fatalf("不正なレスポンスです: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+			if !strings.Contains(string(body), tc.contains) {
+				t.Errorf("'%s' が含まれていません", tc.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"html"
+	"strings"
+)
+
+// syllableSeparators は英辞郎の分節 (【分節】) で音節を区切る記号
+const syllableSeparators = "・･·"
+
+// hyphenationMark は分節を表示する際に音節の間に置く記号 (中黒。辞書の慣例に合わせる)
+const hyphenationMark = "·"
+
+// splitSyllables は分節 (【分節】の内容) を音節に分ける
+// 例: "tac・ti・cal" -> ["tac", "ti", "cal"]
+// 区切りのない分節 (1音節の語) は、その語のみを返す
+func splitSyllables(syllabification string) []string {
+	var syllables []string
+	for _, part := range strings.FieldsFunc(syllabification, func(r rune) bool {
+		return strings.ContainsRune(syllableSeparators, r)
+	}) {
+		if part = strings.TrimSpace(part); part != "" {
+			syllables = append(syllables, part)
+		}
+	}
+	return syllables
+}
+
+// renderHyphenation は見出し語の分節を "tac·ti·cal" の形で表示するHTML要素にする
+// 分節がない場合は空文字列を返す
+func renderHyphenation(entry DictionaryEntry, tag string) string {
+	if len(entry.Hyphenation) == 0 {
+		return ""
+	}
+	return "<" + tag + ` class="hyphenation">` + html.EscapeString(strings.Join(entry.Hyphenation, hyphenationMark)) + "</" + tag + ">"
+}
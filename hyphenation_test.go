@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSplitSyllables は分節が音節に分けられることを検証します。
+func TestSplitSyllables(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"全角の中黒", "tac・ti・cal", []string{"tac", "ti", "cal"}},
+		{"半角の中黒", "tac･ti･cal", []string{"tac", "ti", "cal"}},
+		{"1音節", "know", []string{"know"}},
+		{"空", "", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := splitSyllables(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseHyphenation は【分節】が構造化データの音節の一覧になることを検証します。
+func TestParseHyphenation(t *testing.T) {
+	path := writeEijiroTestFile(t, "■tactical {形} : 戦術的な、【分節】tac・ti・cal\n")
+
+	entries, err := parseEijiro(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if expected := []string{"tac", "ti", "cal"}; !reflect.DeepEqual(entries[0].Hyphenation, expected) {
+		t.Errorf("期待値: %q, 実際: %q", expected, entries[0].Hyphenation)
+	}
+
+	// 分節を削除する場合は、構造化データにも含めない
+	entries, err = parseEijiro(path, ParseOptions{StripSyllabification: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if entries[0].Hyphenation != nil {
+		t.Errorf("分節が残っています: %q", entries[0].Hyphenation)
+	}
+}
+
+// TestRenderHyphenation は -hyphenation 指定時に分節が定義の前に表示され、定義中の【分節】が取り除かれることを検証します。
+func TestRenderHyphenation(t *testing.T) {
+	entry := DictionaryEntry{Headword: "tactical", Definition: "{形} 戦術的な、【分節】tac・ti・cal", Hyphenation: []string{"tac", "ti", "cal"}}
+
+	got := htmlRenderer{hyphenation: true}.Render(entry)
+	expected := `<span class="hyphenation">tac·ti·cal</span><br>{形} 戦術的な、`
+	if got != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+	got = htmlRenderer{paragraphTag: true, hyphenation: true}.Render(entry)
+	if !strings.HasPrefix(got, `<p class="hyphenation">tac·ti·cal</p>`) {
+		t.Errorf("段落として表示されていません: %q", got)
+	}
+
+	if got := (htmlRenderer{}).Render(entry); strings.Contains(got, "hyphenation") || !strings.Contains(got, "【分節】") {
+		t.Errorf("オプションが無効なのに分節が表示されています: %q", got)
+	}
+	if got := renderHyphenation(DictionaryEntry{Headword: "NASA"}, "span"); got != "" {
+		t.Errorf("分節がない場合は空のはずです: %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// 表示する言語 (-lang に指定する値)
+const (
+	LangJapanese = "ja"
+	LangEnglish  = "en"
+)
+
+// language はフラグの説明・ログ・エラーを表示する言語
+// 英辞郎のデータ自体は日本語のため、辞書の内容や定義の加工には影響しない
+var language = LangJapanese
+
+// detectLanguage はコマンドライン引数の -lang、または環境変数 LC_ALL, LC_MESSAGES, LANG から表示する言語を決める
+// フラグの説明を翻訳してから解析する必要があるため、フラグの解析より前に引数を直接調べる
+// 環境変数が日本語のロケール、C/POSIX、または未設定の場合は日本語とする
+func detectLanguage(args []string, getenv func(string) string) (string, error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "lang" {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				break
+			}
+			value = args[i+1]
+		}
+		switch value {
+		case LangJapanese, LangEnglish:
+			return value, nil
+		}
+		return LangJapanese, fmt.Errorf("未対応の言語です: %s (%s または %s を指定してください)", value, LangJapanese, LangEnglish)
+	}
+
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := getenv(key)
+		if locale == "" {
+			continue
+		}
+		if strings.HasPrefix(locale, "ja") || locale == "C" || locale == "POSIX" || strings.HasPrefix(locale, "C.") {
+			return LangJapanese, nil
+		}
+		return LangEnglish, nil
+	}
+	return LangJapanese, nil
+}
+
+// tr は表示する言語に応じて、日本語の文言 (書式文字列) を翻訳して返す
+// 翻訳がない場合は日本語のまま返す
+func tr(message string) string {
+	if language == LangEnglish {
+		if translated, ok := englishMessages[message]; ok {
+			return translated
+		}
+	}
+	return message
+}
+
+// sprintf は書式文字列を翻訳してから fmt.Sprintf で整形する
+func sprintf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// errorf は書式文字列を翻訳してから fmt.Errorf でエラーを作る
+func errorf(format string, args ...any) error {
+	return fmt.Errorf(tr(format), args...)
+}
+
+// localizeFlags は -lang フラグを fs に登録し、すべてのフラグの説明を表示する言語に翻訳する
+// 言語は detectLanguage で決めるため、-lang の値はここでは使わない
+// すべてのフラグを登録した後、解析の前に呼び出すこと
+func localizeFlags(fs *flag.FlagSet) {
+	if fs.Lookup("lang") == nil {
+		fs.String("lang", "", "表示する言語 (ja: 日本語, en: 英語。空の場合は環境変数 LANG などから判断する)")
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		f.Usage = tr(f.Usage)
+	})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// TestDetectLanguage は -lang の指定と環境変数から表示する言語を決められることを検証します。
+func TestDetectLanguage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{"指定なし", nil, nil, LangJapanese, false},
+		{"-lang en", []string{"-i", "a.txt", "-lang", "en"}, nil, LangEnglish, false},
+		{"--lang=ja が環境変数より優先", []string{"--lang=ja"}, map[string]string{"LANG": "en_US.UTF-8"}, LangJapanese, false},
+		{"-- 以降は見ない", []string{"--", "-lang", "en"}, nil, LangJapanese, false},
+		{"未対応の言語", []string{"-lang=fr"}, nil, LangJapanese, true},
+		{"LANG=en_US", nil, map[string]string{"LANG": "en_US.UTF-8"}, LangEnglish, false},
+		{"LANG=ja_JP", nil, map[string]string{"LANG": "ja_JP.UTF-8"}, LangJapanese, false},
+		{"LANG=C.UTF-8", nil, map[string]string{"LANG": "C.UTF-8"}, LangJapanese, false},
+		{"LC_ALL が LANG より優先", nil, map[string]string{"LC_ALL": "ja_JP.UTF-8", "LANG": "en_US.UTF-8"}, LangJapanese, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lang, err := detectLanguage(tc.args, func(key string) string { return tc.env[key] })
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("エラーの有無が違います: %v", err)
+			}
+			if lang != tc.expected {
+				t.Errorf("期待値: %s, 実際: %s", tc.expected, lang)
+			}
+		})
+	}
+}
+
+// TestTranslation は英語を選んだ場合に文言とフラグの説明が翻訳され、翻訳のない文言はそのまま表示されることを検証します。
+func TestTranslation(t *testing.T) {
+	defer func() { language = LangJapanese }()
+	language = LangEnglish
+
+	if got := errorf("JSONLファイルの書き込みに失敗しました: %w", os.ErrNotExist).Error(); !strings.HasPrefix(got, "failed to write the JSONL file: ") {
+		t.Errorf("エラーが翻訳されていません: %q", got)
+	}
+	if got := tr("翻訳のない文言"); got != "翻訳のない文言" {
+		t.Errorf("翻訳のない文言が変わっています: %q", got)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("o", "", "出力先ディレクトリ")
+	localizeFlags(fs)
+	if got := fs.Lookup("o").Usage; got != "Output directory" {
+		t.Errorf("フラグの説明が翻訳されていません: %q", got)
+	}
+	if fs.Lookup("lang") == nil {
+		t.Errorf("-lang フラグが登録されていません")
+	}
+}
+
+// formatVerb は書式指定子 (%[2]d などの引数の位置の指定を含む) に一致する
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?([a-zA-Z%])`)
+
+// formatVerbs は書式文字列の書式指定子を、対応する引数の位置ごとに返す
+func formatVerbs(format string) map[int]string {
+	verbs := make(map[int]string)
+	arg := 0
+	for _, m := range formatVerb.FindAllStringSubmatch(format, -1) {
+		if m[3] == "%" {
+			continue
+		}
+		if m[1] != "" {
+			arg, _ = strconv.Atoi(strings.Trim(m[1], "[]"))
+		} else {
+			arg++
+		}
+		verbs[arg] = m[3]
+	}
+	return verbs
+}
+
+// TestEnglishMessagesVerbs は英語の訳が日本語の文言と同じ引数を同じ書式指定子で使うことを検証します。
+func TestEnglishMessagesVerbs(t *testing.T) {
+	for ja, en := range englishMessages {
+		want, got := formatVerbs(ja), formatVerbs(en)
+		if len(want) != len(got) {
+			t.Errorf("%q: 書式指定子の数が違います: %q", ja, en)
+			continue
+		}
+		for arg, verb := range want {
+			if got[arg] != verb {
+				t.Errorf("%q: %d番目の引数の書式指定子が違います: %q", ja, arg, en)
+			}
+		}
+	}
+}
+
+// TestEnglishMessagesCoverage はフラグの説明と、tr・sprintf・errorf に渡す日本語の文言に英語の訳があることを検証します。
+func TestEnglishMessagesCoverage(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	isJapanese := func(s string) bool {
+		for _, r := range s {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+				return true
+			}
+		}
+		return false
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check := func(e ast.Expr) {
+			lit, ok := e.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return
+			}
+			s, _ := strconv.Unquote(lit.Value)
+			if _, ok := englishMessages[s]; isJapanese(s) && !ok {
+				t.Errorf("%s: 英語の訳がありません: %q", fset.Position(lit.Pos()), s)
+			}
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				switch fun.Name {
+				case "tr", "sprintf", "errorf", "report":
+					check(call.Args[0])
+				}
+			case *ast.SelectorExpr:
+				// フラグの定義 (fs.String, flag.Bool など) の説明は最後の引数
+				if x, ok := fun.X.(*ast.Ident); ok && (x.Name == "fs" || x.Name == "flag") {
+					check(call.Args[len(call.Args)-1])
+				}
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// idiomBookSuffix は成句辞書のファイル名に付ける接尾辞
+const idiomBookSuffix = "-idioms"
+
+// idiomStopWords は成句を構成する語のうち、検索用キーワードとしない語
+// 冠詞・前置詞・代名詞などの機能語と、英辞郎の成句で目的語などの代わりに使われる語 (one's, something など)
+var idiomStopWords = map[string]bool{
+	"a": true, "an": true, "the": true,
+	"and": true, "or": true, "but": true, "nor": true, "if": true, "than": true, "so": true, "not": true, "no": true,
+	"about": true, "above": true, "across": true, "after": true, "against": true, "along": true, "around": true, "as": true,
+	"at": true, "away": true, "back": true, "before": true, "behind": true, "below": true, "by": true, "down": true,
+	"for": true, "from": true, "in": true, "into": true, "of": true, "off": true, "on": true, "onto": true, "out": true,
+	"over": true, "through": true, "to": true, "under": true, "up": true, "upon": true, "with": true, "without": true,
+	"be": true, "is": true, "are": true, "was": true, "been": true, "do": true, "does": true, "have": true, "has": true,
+	"i": true, "you": true, "he": true, "she": true, "it": true, "we": true, "they": true,
+	"me": true, "him": true, "her": true, "us": true, "them": true,
+	"my": true, "your": true, "his": true, "its": true, "our": true, "their": true,
+	"one": true, "one's": true, "oneself": true, "someone": true, "someone's": true, "somebody": true, "somebody's": true,
+	"something": true, "something's": true, "sb": true, "sth": true,
+	"that": true, "this": true, "what": true, "which": true, "who": true,
+}
+
+// isIdiomHeadword は見出し語が複数の単語からなる成句 (句動詞、慣用句、連語など) かどうかを判断する
+// -single-word-only と同じく、スペースを含む見出し語を成句とみなす
+func isIdiomHeadword(headword string) bool {
+	return strings.Contains(headword, " ")
+}
+
+// idiomKeywords は成句を構成する語のうち、検索用キーワードとする語を出現順に返す
+// 括弧や「～」などの記号は語の区切りとみなし、機能語と1文字の語は除く
+func idiomKeywords(headword string) []string {
+	words := strings.FieldsFunc(strings.ToLower(headword), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+	var keywords []string
+	for _, word := range words {
+		word = strings.Trim(word, "'-")
+		if len([]rune(word)) < 2 || idiomStopWords[word] {
+			continue
+		}
+		keywords = appendUnique(keywords, word)
+	}
+	return keywords
+}
+
+// splitIdiomEntries はエントリを単語の見出し語と成句の見出し語に分ける
+// 成句のエントリには、構成する語を検索用キーワードとして加え、"kick the bucket" を "bucket" からも引けるようにする
+// エントリの並び順は保つ
+func splitIdiomEntries(entries []DictionaryEntry) (words, idioms []DictionaryEntry) {
+	for _, entry := range entries {
+		if !isIdiomHeadword(entry.Headword) {
+			words = append(words, entry)
+			continue
+		}
+		entry.Keywords = appendUnique(append([]string(nil), entry.Keywords...), idiomKeywords(entry.Headword)...)
+		idioms = append(idioms, entry)
+	}
+	return words, idioms
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestIdiomKeywords は成句を構成する語のうち、機能語や記号を除いた語が検索用キーワードになることを検証します。
+func TestIdiomKeywords(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headword string
+		expected []string
+	}{
+		{"慣用句", "kick the bucket", []string{"kick", "bucket"}},
+		{"句動詞", "give up", []string{"give"}},
+		{"括弧と記号", "(be) fond of ～", []string{"fond"}},
+		{"目的語の代わりの語", "make up one's mind", []string{"make", "mind"}},
+		{"大文字と重複", "Time after time", []string{"time"}},
+		{"ハイフンを含む語", "a well-known fact", []string{"well-known", "fact"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := idiomKeywords(tc.headword); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestSplitIdiomEntries は成句のエントリが分けられ、元のエントリの検索用キーワードを変えずに構成する語が加わることを検証します。
+func TestSplitIdiomEntries(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "bucket", Definition: "{名} バケツ"},
+		{Headword: "kick the bucket", Definition: "死ぬ", Keywords: []string{"kick the pail"}},
+		{Headword: "door", Definition: "{名} 扉"},
+	}
+	words, idioms := splitIdiomEntries(entries)
+	if len(words) != 2 || words[0].Headword != "bucket" || words[1].Headword != "door" {
+		t.Errorf("単語のエントリが違います: %+v", words)
+	}
+	if len(idioms) != 1 || idioms[0].Headword != "kick the bucket" {
+		t.Fatalf("成句のエントリが違います: %+v", idioms)
+	}
+	if expected := []string{"kick the pail", "kick", "bucket"}; !reflect.DeepEqual(idioms[0].Keywords, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, idioms[0].Keywords)
+	}
+	if !reflect.DeepEqual(entries[1].Keywords, []string{"kick the pail"}) {
+		t.Errorf("元のエントリの検索用キーワードが変わりました: %v", entries[1].Keywords)
+	}
+}
+
+// TestRunConversionIdiomDict は -idiom-dict で、成句が本体の辞書から除かれ、構成する語から引ける成句辞書に書き出されることを検証します。
+func TestRunConversionIdiomDict(t *testing.T) {
+	path := writeEijiroTestFile(t, `■bucket {名} : バケツ
+■kick the bucket : 死ぬ
+■give up : あきらめる
+`)
+	dir := t.TempDir()
+	cfg := ConvertConfig{InputFile: path, OutputDir: dir, BookName: "Test", MergeStrategy: MergeConcat, IdiomDict: true}
+	if _, err := runConversion(context.Background(), cfg); err != nil {
+		t.Fatalf("runConversionでエラーが発生しました: %v", err)
+	}
+	info, err := readIfoFile(filepath.Join(dir, "Test.ifo"))
+	if err != nil {
+		t.Fatalf("本体の辞書が書き出されていません: %v", err)
+	}
+	if info["wordcount"] != "1" {
+		t.Errorf("本体の辞書の見出し語数が違います: %s", info["wordcount"])
+	}
+	idioms, err := readIfoFile(filepath.Join(dir, "Test"+idiomBookSuffix+".ifo"))
+	if err != nil {
+		t.Fatalf("成句辞書が書き出されていません: %v", err)
+	}
+	// kick, bucket, give の3語が .syn に記録される
+	if idioms["wordcount"] != "2" || idioms["synwordcount"] != "3" {
+		t.Errorf("成句辞書の見出し語数が違います: %v", idioms)
+	}
+
+	cfg.ParseOptions.SingleWordOnly = true
+	if _, err := runConversion(context.Background(), cfg); err == nil {
+		t.Error("-single-word-only との同時指定でエラーになりませんでした")
+	}
+}
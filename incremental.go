@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reHunkHeader は統一形式の差分 (diff -u) のまとまりの先頭行に一致する
+// 行数を省略した場合は1行を表す (例: "@@ -10,3 +10,4 @@", "@@ -5 +5 @@")
+var reHunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// sjisHeadwordMark は Shift_JIS で符号化した "■"
+var sjisHeadwordMark = []byte{0x81, 0xa1}
+
+// sjisExampleMark は Shift_JIS で符号化した "■・"
+var sjisExampleMark = []byte{0x81, 0xa1, 0x81, 0x45}
+
+// sourceHunk は英辞郎ファイルの差分の1つのまとまり
+type sourceHunk struct {
+	oldStart, oldLines int
+	newLines           int
+	lineMap            map[int]int // 変更されずに残った旧版の行番号から新版の行番号への対応
+}
+
+// sourcePatch は旧版から新版への英辞郎ファイルの差分
+// 差分は Shift_JIS のまま読み込み、見出し語の比較もバイト列のまま行う
+type sourcePatch struct {
+	hunks []sourceHunk
+	bases map[string]bool // 変更された行が属する見出し語 (品詞情報を除いたもの)
+}
+
+// readSourcePatch は統一形式の差分 (diff -u 旧版 新版) を読み込み、変更された行が属する見出し語を集める
+// 変更された行が用例 (■・) や補足説明 (◆) の行の場合は、差分の中でその行より前にある見出し語の行から見出し語を決める
+func readSourcePatch(r io.Reader) (sourcePatch, error) {
+	patch := sourcePatch{bases: make(map[string]bool)}
+	files := 0
+	var hunk *sourceHunk
+	var oldLine, newLine, oldRest, newRest int
+	var lastOld, lastNew []byte // 旧版・新版それぞれで直前に現れた見出し語
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		line := bytes.TrimRight(scanner.Bytes(), "\r")
+		lineNo++
+		// まとまりの外では、ファイルの見出し (--- と +++) とまとまりの先頭行以外は読み飛ばす
+		if oldRest == 0 && newRest == 0 {
+			if bytes.HasPrefix(line, []byte("--- ")) {
+				files++
+				continue
+			}
+			m := reHunkHeader.FindSubmatch(line)
+			if m == nil {
+				continue
+			}
+			h := sourceHunk{oldLines: 1, newLines: 1, lineMap: make(map[int]int)}
+			h.oldStart, _ = strconv.Atoi(string(m[1]))
+			newStart, _ := strconv.Atoi(string(m[3]))
+			if len(m[2]) > 0 {
+				h.oldLines, _ = strconv.Atoi(string(m[2]))
+			}
+			if len(m[4]) > 0 {
+				h.newLines, _ = strconv.Atoi(string(m[4]))
+			}
+			// 行数が0の場合、開始行はその直後に挿入・削除されたことを表す
+			if h.oldLines == 0 {
+				h.oldStart++
+			}
+			if h.newLines == 0 {
+				newStart++
+			}
+			patch.hunks = append(patch.hunks, h)
+			hunk = &patch.hunks[len(patch.hunks)-1]
+			oldLine, newLine = h.oldStart, newStart
+			oldRest, newRest = h.oldLines, h.newLines
+			lastOld, lastNew = nil, nil
+			continue
+		}
+
+		// 空行の前後関係の行は、先頭の空白が省かれることがある
+		var mark byte = ' '
+		var text []byte
+		if len(line) > 0 {
+			mark, text = line[0], line[1:]
+		}
+		base := headwordBase(text)
+		switch mark {
+		case ' ':
+			hunk.lineMap[oldLine] = newLine
+			oldLine++
+			newLine++
+			oldRest--
+			newRest--
+			if base != nil {
+				lastOld, lastNew = base, base
+			}
+			continue
+		case '-':
+			oldLine++
+			oldRest--
+			if base == nil {
+				base = lastOld
+			} else {
+				lastOld = base
+			}
+		case '+':
+			newLine++
+			newRest--
+			if base == nil {
+				base = lastNew
+			} else {
+				lastNew = base
+			}
+		case '\\':
+			// "\ No newline at end of file"
+			continue
+		default:
+			return sourcePatch{}, errorf("差分の%d行目を解釈できません", lineNo)
+		}
+		if oldRest < 0 || newRest < 0 {
+			return sourcePatch{}, errorf("差分の%d行目を解釈できません", lineNo)
+		}
+		if base == nil {
+			if len(bytes.TrimSpace(text)) == 0 {
+				continue
+			}
+			return sourcePatch{}, errorf("差分の%d行目の変更がどの見出し語に属するかを決められません。前後の行を多く含めて差分を作り直してください (例: diff -U 10)", lineNo)
+		}
+		patch.bases[string(base)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return sourcePatch{}, err
+	}
+	if files > 1 {
+		return sourcePatch{}, errorf("差分には1つのファイルの変更のみを含めてください (%d個のファイルが含まれています)", files)
+	}
+	return patch, nil
+}
+
+// headwordBase は Shift_JIS の英辞郎の行から、品詞情報({名}など)を除いた見出し語をバイト列のまま取り出す
+// 見出し語の行でない場合 (用例・補足説明・空行など) は nil を返す
+// 同じ見出し語の行からは常に同じバイト列が得られればよく、パーサーと完全に同じ結果でなくてもよい
+func headwordBase(line []byte) []byte {
+	if !bytes.HasPrefix(line, sjisHeadwordMark) || bytes.HasPrefix(line, sjisExampleMark) {
+		return nil
+	}
+	// ':' は Shift_JIS の2バイト文字の2バイト目には現れないため、そのまま区切りとして探せる
+	head, _, found := bytes.Cut(line[len(sjisHeadwordMark):], []byte(":"))
+	if !found {
+		return nil
+	}
+	head = bytes.TrimSpace(head)
+	if bytes.HasSuffix(head, []byte("}")) {
+		if i := bytes.IndexByte(head, '{'); i >= 0 {
+			head = bytes.TrimSpace(head[:i])
+		}
+	}
+	return head
+}
+
+// newLine は旧版の行番号を新版の行番号に変換する (その行が削除された場合は false)
+func (p sourcePatch) newLine(old int) (int, bool) {
+	offset := 0
+	for _, h := range p.hunks {
+		if old < h.oldStart {
+			break
+		}
+		if old < h.oldStart+h.oldLines {
+			line, ok := h.lineMap[old]
+			return line, ok
+		}
+		offset += h.newLines - h.oldLines
+	}
+	return old + offset, true
+}
+
+// selectPatchedLines は新版の英辞郎ファイルから、変更された見出し語の行 (続く用例・補足説明の行を含む) を取り出す
+// 文字コードを変換せずに見出し語を比べるため、変更のない大部分の行はパースせずに読み飛ばせる
+// 取り出した行の内容 (Shift_JIS) と、各行の新版での行番号、新版の行数を返す
+func selectPatchedLines(r io.Reader, bases map[string]bool) ([]byte, []int, int, error) {
+	var selected bytes.Buffer
+	var lineNumbers []int
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lines := 0
+	inBlock := false
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		lines++
+		if base := headwordBase(line); base != nil {
+			inBlock = bases[string(base)]
+		}
+		if inBlock {
+			selected.Write(line)
+			selected.WriteByte('\n')
+			lineNumbers = append(lineNumbers, lines)
+		}
+	}
+	return selected.Bytes(), lineNumbers, lines, scanner.Err()
+}
+
+// applySourcePatch は前回のパース結果のキャッシュに英辞郎ファイルの差分を適用し、新版をパースした場合と同じエントリを求める
+// 差分で変更された見出し語の行のみを新版の英辞郎ファイルから取り出してパースし、それ以外のエントリはキャッシュのものを行番号だけ付け替えて使う
+// 変更された見出し語の数を返す
+// 新版で離れた位置に現れる同じ見出し語の行は、取り出した時点で隣り合うため一つのエントリにまとまることがある
+func applySourcePatch(ctx context.Context, cache parseCache, patchPath, newInput string) (parseCache, int, error) {
+	opts := cache.Options
+	if cache.Stats.Truncated {
+		return cache, 0, errorf("読み込みを途中で打ち切ったキャッシュには差分を適用できません")
+	}
+	if opts.GenerateInflections {
+		return cache, 0, errorf("-generate-inflections を指定して作成したキャッシュには差分を適用できません (生成する変化形が辞書全体に依存するため)")
+	}
+	if opts.HeadwordRange != "" || opts.Offset > 0 || opts.Limit > 0 {
+		return cache, 0, errorf("-headword-range, -offset, -limit を指定して作成したキャッシュには差分を適用できません (対象の見出し語が辞書全体での位置に依存するため)")
+	}
+
+	patchFile, err := os.Open(patchPath)
+	if err != nil {
+		return cache, 0, err
+	}
+	patch, err := readSourcePatch(patchFile)
+	patchFile.Close()
+	if err != nil {
+		return cache, 0, err
+	}
+
+	// 変更された見出し語を、パースした場合の見出し語 (括弧の展開や正規化を行ったもの) に変換する
+	var probe bytes.Buffer
+	for base := range patch.bases {
+		probe.Write(sjisHeadwordMark)
+		probe.WriteString(base)
+		probe.WriteString(" : -\n")
+	}
+	probeEntries, probeStats, err := parseEijiroReader(ctx, &probe, opts)
+	if err != nil {
+		return cache, 0, err
+	}
+	affected := make(map[string]bool, probeStats.Headwords)
+	for _, entry := range probeEntries[:probeStats.Headwords] {
+		affected[entry.Headword] = true
+	}
+
+	// 新版から変更された見出し語の行を取り出してパースする
+	input, err := openInput(newInput)
+	if err != nil {
+		return cache, 0, err
+	}
+	selected, lineNumbers, totalLines, err := selectPatchedLines(input, patch.bases)
+	input.Close()
+	if err != nil {
+		return cache, 0, err
+	}
+	patched, patchedStats, err := parseEijiroReader(ctx, bytes.NewReader(selected), opts)
+	if err != nil {
+		return cache, 0, err
+	}
+	for i := range patched {
+		// 取り出した行の中での行番号を、新版での行番号に置き換える
+		if n := patched[i].SourceLine; n > 0 && n <= len(lineNumbers) {
+			patched[i].SourceLine = lineNumbers[n-1]
+		}
+		if i < patchedStats.Headwords {
+			affected[patched[i].Headword] = true
+		}
+	}
+
+	// キャッシュのエントリのうち、変更された見出し語とそこからのリンクを除き、行番号を新版のものにする
+	// パーサーと同じく、見出し語のエントリの後にリンクのエントリを並べる
+	var headwords, links []DictionaryEntry
+	for i, entry := range cache.Entries {
+		isLink := i >= cache.Stats.Headwords
+		if isLink {
+			if target, ok := strings.CutPrefix(entry.Definition, "@@@LINK="); ok && affected[target] {
+				continue
+			}
+		} else if affected[entry.Headword] {
+			continue
+		}
+		line, ok := patch.newLine(entry.SourceLine)
+		if !ok {
+			continue
+		}
+		entry.SourceLine = line
+		if isLink {
+			links = append(links, entry)
+		} else {
+			headwords = append(headwords, entry)
+		}
+	}
+	headwords = append(headwords, patched[:patchedStats.Headwords]...)
+	links = append(links, patched[patchedStats.Headwords:]...)
+	for _, list := range [][]DictionaryEntry{headwords, links} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].SourceLine < list[j].SourceLine
+		})
+	}
+
+	// 行数とエントリ数は新版のものにする (除外・無視した行などの内訳は前回の値のままのため、行数とは一致しない)
+	stats := cache.Stats
+	stats.Lines = totalLines
+	stats.Patched = true
+	stats.Headwords = len(headwords)
+	stats.LinkEntries = len(links)
+	return parseCache{
+		Input:   newInput,
+		Options: opts,
+		Stats:   stats,
+		Entries: append(headwords, links...),
+	}, len(patch.bases), nil
+}
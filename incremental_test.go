@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// writePatchTestFile は差分 (Shift_JISに変換する) をファイルに書き出します。
+func writePatchTestFile(t *testing.T, content string) string {
+	t.Helper()
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(content)
+	if err != nil {
+		t.Fatalf("Shift_JISへの変換に失敗しました: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "eijiro.diff")
+	if err := os.WriteFile(path, []byte(encoded), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestApplySourcePatch は差分を適用したキャッシュが、新版をすべてパースした結果と一致することを検証します。
+func TestApplySourcePatch(t *testing.T) {
+	oldPath := writeEijiroTestFile(t, strings.Join([]string{
+		"■apple {名} : りんご【変化】《複》apples",
+		"■door {名} : 扉",
+		"◆古い補足",
+		"■door {動} : 戸を閉める",
+		"■egg {名} : 卵",
+		"■fish {名} : 魚【変化】《複》fishes",
+	}, "\n")+"\n")
+	newPath := writeEijiroTestFile(t, strings.Join([]string{
+		"■apple {名} : りんご【変化】《複》apples",
+		"■cat {名} : 猫【変化】《複》cats",
+		"■door {名} : 扉",
+		"◆新しい補足",
+		"■door {動} : 戸を閉める",
+		"■egg {名} : 卵",
+	}, "\n")+"\n")
+	patchPath := writePatchTestFile(t, strings.Join([]string{
+		"--- EIJIRO-1448.TXT",
+		"+++ EIJIRO-1450.TXT",
+		"@@ -1,6 +1,6 @@",
+		" ■apple {名} : りんご【変化】《複》apples",
+		"+■cat {名} : 猫【変化】《複》cats",
+		" ■door {名} : 扉",
+		"-◆古い補足",
+		"+◆新しい補足",
+		" ■door {動} : 戸を閉める",
+		" ■egg {名} : 卵",
+		"-■fish {名} : 魚【変化】《複》fishes",
+	}, "\n")+"\n")
+
+	opts := ParseOptions{}
+	oldEntries, oldStats, err := parseEijiroWithStats(oldPath, opts)
+	if err != nil {
+		t.Fatalf("旧版のパースでエラーが発生しました: %v", err)
+	}
+	cache := parseCache{Input: oldPath, Options: opts, Stats: oldStats, Entries: oldEntries}
+	patched, changed, err := applySourcePatch(context.Background(), cache, patchPath, newPath)
+	if err != nil {
+		t.Fatalf("applySourcePatchでエラーが発生しました: %v", err)
+	}
+	if changed != 3 {
+		t.Errorf("変更された見出し語の数が違います: %d", changed)
+	}
+
+	newEntries, newStats, err := parseEijiroWithStats(newPath, opts)
+	if err != nil {
+		t.Fatalf("新版のパースでエラーが発生しました: %v", err)
+	}
+	if !reflect.DeepEqual(patched.Entries, newEntries) {
+		t.Errorf("エントリが新版のパース結果と異なります\n期待値: %+v\n実際: %+v", newEntries, patched.Entries)
+	}
+	// 除外・無視した行などの内訳は前回の値のままのため、行数とエントリ数のみを比べる
+	if got := patched.Stats; !got.Patched || got.Lines != newStats.Lines || got.Headwords != newStats.Headwords || got.LinkEntries != newStats.LinkEntries {
+		t.Errorf("読み込んだ行数とエントリ数が違います\n期待値: %+v\n実際: %+v", newStats, got)
+	}
+	if patched.Input != newPath {
+		t.Errorf("入力ファイル名が新版のものになっていません: %s", patched.Input)
+	}
+
+	// 辞書全体に依存する指定で作成したキャッシュには、差分を適用しない
+	rejected := []struct {
+		name  string
+		cache parseCache
+	}{
+		{"読み込みの打ち切り", parseCache{Options: opts, Stats: ParseStats{Truncated: true}}},
+		{"変化形の生成", parseCache{Options: ParseOptions{GenerateInflections: true}}},
+		{"見出し語の範囲", parseCache{Options: ParseOptions{HeadwordRange: "a-c"}}},
+		{"読み飛ばす見出し語の数", parseCache{Options: ParseOptions{Offset: 1}}},
+		{"見出し語の最大数", parseCache{Options: ParseOptions{Limit: 2}}},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := applySourcePatch(context.Background(), tc.cache, patchPath, newPath); err == nil {
+				t.Error("差分の適用がエラーになりませんでした")
+			}
+		})
+	}
+}
+
+// TestReadSourcePatch は差分から変更された見出し語が集まり、見出し語を決められない変更がエラーになることを検証します。
+func TestReadSourcePatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		patch    string
+		expected []string
+		wantErr  bool
+	}{
+		{"見出し語の行の変更", "@@ -1 +1 @@\n-■door {名} : 扉\n+■door {名} : 扉、戸\n", []string{"door"}, false},
+		{"前後の行から見出し語を決める", "@@ -1,2 +1,2 @@\n ■door {名} : 扉\n-◆古い\n+◆新しい\n", []string{"door"}, false},
+		{"見出し語を決められない", "@@ -5 +5 @@\n-◆古い\n+◆新しい\n", nil, true},
+		{"複数のファイル", "--- a\n+++ b\n@@ -1 +1 @@\n-■a : 1\n+■a : 2\n--- c\n+++ d\n", nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, _ := japanese.ShiftJIS.NewEncoder().String(tc.patch)
+			patch, err := readSourcePatch(strings.NewReader(encoded))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("エラーの有無が違います: %v", err)
+			}
+			var got []string
+			for base := range patch.bases {
+				got = append(got, base)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
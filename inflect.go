@@ -0,0 +1,184 @@
+package main
+
+import "strings"
+
+// 規則変化を生成する品詞の分類
+const (
+	inflectNoun = 1 << iota // 名詞: 複数形
+	inflectVerb             // 動詞: 三人称単数現在形・過去形 (過去分詞)・現在分詞
+	inflectAdj              // 形容詞: 比較級・最上級
+)
+
+// inflectionClass は語義の品詞 (例: "名", "他動", "形-1") から、規則変化を生成する品詞の分類を返す
+func inflectionClass(pos string) int {
+	switch {
+	case strings.HasPrefix(pos, "名"):
+		return inflectNoun
+	case strings.HasPrefix(pos, "助動"):
+		// 助動詞 (can, must など) は変化しない
+		return 0
+	case strings.Contains(pos, "動"):
+		return inflectVerb
+	case strings.HasPrefix(pos, "形"):
+		return inflectAdj
+	}
+	return 0
+}
+
+// generateInflections は【変化】のない見出し語について、規則変化の変化形から原形へのリンクのエントリを生成する
+// headwords はパースした見出し語のエントリ、links は【変化】から生成したリンクのエントリ
+// 小文字の英字のみからなる単語を対象とし、既に【変化】がある見出し語 (不規則変化を含む) と、
+// 既に見出し語や変化形として存在する語は、誤ったリンクにならないよう生成しない
+func generateInflections(headwords, links []DictionaryEntry) []DictionaryEntry {
+	known := make(map[string]bool, len(headwords)+len(links))
+	hasForms := make(map[string]bool)
+	for _, entry := range headwords {
+		known[strings.ToLower(entry.Headword)] = true
+	}
+	for _, entry := range links {
+		known[strings.ToLower(entry.Headword)] = true
+		hasForms[strings.ToLower(strings.TrimPrefix(entry.Definition, "@@@LINK="))] = true
+	}
+
+	// 見出し語ごとの変化形を先に求め、それ自体が他の見出し語の変化形である語 (例: 見出し語 "cats") からは生成しない
+	candidates := make([][]string, len(headwords))
+	isForm := make(map[string]bool)
+	for i, entry := range headwords {
+		if !isInflectableWord(entry.Headword) || hasForms[entry.Headword] {
+			continue
+		}
+		class := 0
+		for _, sense := range entry.Senses {
+			class |= inflectionClass(sense.POS)
+		}
+		candidates[i] = regularInflections(entry.Headword, class)
+		for _, form := range candidates[i] {
+			isForm[form] = true
+		}
+	}
+
+	var generated []DictionaryEntry
+	for i, entry := range headwords {
+		base := entry.Headword
+		if isForm[base] {
+			continue
+		}
+		for _, form := range candidates[i] {
+			if known[form] {
+				continue
+			}
+			known[form] = true
+			generated = append(generated, DictionaryEntry{
+				Headword:   form,
+				Definition: "@@@LINK=" + base,
+				SourceLine: entry.SourceLine,
+			})
+		}
+	}
+	return generated
+}
+
+// isInflectableWord は規則変化を生成する対象の単語 (2文字以上の小文字の英字のみ) かどうかを返す
+func isInflectableWord(word string) bool {
+	if len(word) < 2 {
+		return false
+	}
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// regularInflections は品詞の分類に応じた規則変化の変化形を返す (重複は除く)
+// 例: "stop" (動詞) -> ["stops", "stopped", "stopping"]
+func regularInflections(word string, class int) []string {
+	var forms []string
+	if class&(inflectNoun|inflectVerb) != 0 {
+		forms = appendUnique(forms, pluralForm(word))
+	}
+	if class&inflectVerb != 0 {
+		forms = appendUnique(forms, suffixForm(word, "ed"), suffixForm(word, "ing"))
+	}
+	// 比較級・最上級は、1音節の語と -y で終わる2音節の語のみ規則変化とみなす (それ以外は more, most を使う)
+	if class&inflectAdj != 0 && (countVowelGroups(word) == 1 || (countVowelGroups(word) == 2 && endsWithConsonantY(word))) {
+		forms = appendUnique(forms, suffixForm(word, "er"), suffixForm(word, "est"))
+	}
+	return forms
+}
+
+// pluralForm は名詞の複数形・動詞の三人称単数現在形を返す
+// 例: "box" -> "boxes", "city" -> "cities", "go" -> "goes"
+func pluralForm(word string) string {
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case endsWithConsonantY(word):
+		return word[:len(word)-1] + "ies"
+	case len(word) == 2 && word[1] == 'o' && !isVowel(word[0]):
+		// go, do などの短い語のみ -es とする (photo, piano などは -s)
+		return word + "es"
+	}
+	return word + "s"
+}
+
+// suffixForm は母音で始まる接尾辞 (ed, ing, er, est) を付けた形を返す
+// 語末の e の脱落、子音字 + y の y → i、1音節の短母音の語の語末の子音字の重複を行う
+// 例: "make" + "ing" -> "making", "try" + "ed" -> "tried", "stop" + "ed" -> "stopped"
+func suffixForm(word, suffix string) string {
+	last := word[len(word)-1]
+	switch {
+	case suffix == "ing" && strings.HasSuffix(word, "ie"):
+		return word[:len(word)-2] + "ying"
+	case last == 'e':
+		if suffix == "ing" && (strings.HasSuffix(word, "ee") || strings.HasSuffix(word, "ye") || strings.HasSuffix(word, "oe")) {
+			return word + suffix
+		}
+		return word[:len(word)-1] + suffix
+	case endsWithConsonantY(word) && suffix != "ing":
+		return word[:len(word)-1] + "i" + suffix
+	case shouldDoubleFinal(word):
+		return word + string(last) + suffix
+	}
+	return word + suffix
+}
+
+// shouldDoubleFinal は語末の子音字を重ねるかどうか (1音節で、子音字 + 母音字1つ + 子音字で終わる語) を返す
+// w, x, y で終わる語は重ねない
+func shouldDoubleFinal(word string) bool {
+	n := len(word)
+	if n < 3 || countVowelGroups(word) != 1 || strings.ContainsRune("wxy", rune(word[n-1])) {
+		return false
+	}
+	return !isVowel(word[n-1]) && isVowel(word[n-2]) && !isVowel(word[n-3])
+}
+
+// endsWithConsonantY は子音字 + y で終わるかどうかを返す
+func endsWithConsonantY(word string) bool {
+	n := len(word)
+	return n >= 2 && word[n-1] == 'y' && !isVowel(word[n-2])
+}
+
+// countVowelGroups は連続する母音字を一つと数えた母音字のまとまりの数を返す (音節数の目安)
+// 語末の e は黙字として数えない
+func countVowelGroups(word string) int {
+	word = strings.TrimSuffix(word, "e")
+	count := 0
+	inVowel := false
+	for i := 0; i < len(word); i++ {
+		// 語頭以外の y は母音として扱う (例: "dry", "happy")
+		vowel := isVowel(word[i]) || (word[i] == 'y' && i > 0)
+		if vowel && !inVowel {
+			count++
+		}
+		inVowel = vowel
+	}
+	return count
+}
+
+// isVowel は母音字 (a, e, i, o, u) かどうかを返す
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRegularInflections は品詞ごとの規則変化の変化形を検証します。
+func TestRegularInflections(t *testing.T) {
+	testCases := []struct {
+		name     string
+		word     string
+		class    int
+		expected []string
+	}{
+		{"名詞", "book", inflectNoun, []string{"books"}},
+		{"名詞 -es", "box", inflectNoun, []string{"boxes"}},
+		{"名詞 -ies", "city", inflectNoun, []string{"cities"}},
+		{"動詞", "walk", inflectVerb, []string{"walks", "walked", "walking"}},
+		{"動詞 語末のe", "bake", inflectVerb, []string{"bakes", "baked", "baking"}},
+		{"動詞 -ie", "tie", inflectVerb, []string{"ties", "tied", "tying"}},
+		{"動詞 子音字+y", "try", inflectVerb, []string{"tries", "tried", "trying"}},
+		{"動詞 母音字+y", "play", inflectVerb, []string{"plays", "played", "playing"}},
+		{"動詞 子音字の重複", "stop", inflectVerb, []string{"stops", "stopped", "stopping"}},
+		{"動詞 2音節は重ねない", "visit", inflectVerb, []string{"visits", "visited", "visiting"}},
+		{"形容詞", "big", inflectAdj, []string{"bigger", "biggest"}},
+		{"形容詞 -y", "happy", inflectAdj, []string{"happier", "happiest"}},
+		{"形容詞 長い語", "beautiful", inflectAdj, nil},
+		{"名詞と動詞", "watch", inflectNoun | inflectVerb, []string{"watches", "watched", "watching"}},
+		{"対象外の品詞", "very", 0, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := regularInflections(tc.word, tc.class); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestInflectionClass は語義の品詞から規則変化を生成する品詞の分類を判定できることを検証します。
+func TestInflectionClass(t *testing.T) {
+	testCases := []struct {
+		pos      string
+		expected int
+	}{
+		{"名", inflectNoun},
+		{"名-1", inflectNoun},
+		{"他動", inflectVerb},
+		{"自動-2", inflectVerb},
+		{"助動", 0},
+		{"形", inflectAdj},
+		{"副", 0},
+		{"", 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pos, func(t *testing.T) {
+			if got := inflectionClass(tc.pos); got != tc.expected {
+				t.Errorf("期待値: %d, 実際: %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithGenerateInflections は【変化】のない見出し語にのみ、規則変化のリンクが生成されることを検証します。
+func TestParseWithGenerateInflections(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■walk  {自動-1} : 歩く",
+		"■run  {自動-1} : 走る【変化】《動》runs | running | ran",
+		"■cat  {名-1} : 猫",
+		"■cats  {名} : 〔俗〕ジャズ好き",
+		"■New York  {名} : ニューヨーク",
+	}, "\n"))
+
+	entries, stats, err := parseEijiroWithStats(path, ParseOptions{GenerateInflections: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	links := make(map[string]string)
+	for _, entry := range entries {
+		if target, ok := strings.CutPrefix(entry.Definition, "@@@LINK="); ok {
+			links[entry.Headword] = target
+		}
+	}
+	expected := map[string]string{
+		// 【変化】から生成したリンク
+		"runs": "run", "running": "run", "ran": "run",
+		// 規則変化から生成したリンク (既に見出し語の cats は生成しない)
+		"walks": "walk", "walked": "walk", "walking": "walk",
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("期待値: %v\n実際: %v", expected, links)
+	}
+	if stats.GeneratedLinks != 3 {
+		t.Errorf("規則変化から生成したリンクの件数が違います: %d", stats.GeneratedLinks)
+	}
+	if stats.LinkEntries != len(links) {
+		t.Errorf("変化形のリンクの件数が違います: %d", stats.LinkEntries)
+	}
+}
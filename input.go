@@ -0,0 +1,113 @@
+package main
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// openInput は英辞郎ファイルを開き、Shift_JISのままの内容を読み込むリーダーを返す
+// 配布されている圧縮ファイルをそのまま指定できるよう、.gz はgzipを、.zip は英辞郎のファイルを選んで展開しながら読み込む
+// 展開した内容を一時ファイルに書き出さないため、圧縮ファイルの中身の大きさの空き容量は不要
+func openInput(name string) (io.ReadCloser, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gz":
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, errorf("gzipの展開に失敗: %w", err)
+		}
+		return &multiCloser{Reader: gz, closers: []io.Closer{gz, file}}, nil
+	case ".zip":
+		zr, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, errorf("ZIPファイルを開けません: %w", err)
+		}
+		member, err := selectZipMember(zr.File)
+		if err != nil {
+			zr.Close()
+			return nil, errorf("%s: %w", name, err)
+		}
+		rc, err := member.Open()
+		if err != nil {
+			zr.Close()
+			return nil, err
+		}
+		return &multiCloser{Reader: rc, closers: []io.Closer{rc, zr}}, nil
+	}
+	return os.Open(name)
+}
+
+// inputName は英辞郎ファイルの名前 (辞書のバージョンの決定に使う) を返す
+// 圧縮ファイルの場合は、展開して読み込む英辞郎のファイルの名前を返す (例: "EIJIRO144.zip" -> "EIJIRO-1448.TXT")
+// ZIPファイルを開けない場合は、指定した名前をそのまま返す
+func inputName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gz":
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	case ".zip":
+		zr, err := zip.OpenReader(name)
+		if err != nil {
+			return name
+		}
+		defer zr.Close()
+		if member, err := selectZipMember(zr.File); err == nil {
+			return member.Name
+		}
+	}
+	return name
+}
+
+// selectZipMember はZIPファイルの中から変換する英辞郎のファイルを選ぶ
+// 配布物には略語郎 (RYAKU-*.TXT) や例辞郎 (REIJI-*.TXT) などが同梱されていることがあるため、
+// 名前が EIJIRO で始まるテキストファイルを優先し、なければテキストファイルが一つだけの場合にそれを使う
+func selectZipMember(files []*zip.File) (*zip.File, error) {
+	var texts, eijiro []*zip.File
+	for _, f := range files {
+		base := path.Base(f.Name)
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(base), ".txt") {
+			continue
+		}
+		texts = append(texts, f)
+		if strings.HasPrefix(strings.ToUpper(base), "EIJIRO") {
+			eijiro = append(eijiro, f)
+		}
+	}
+	switch {
+	case len(eijiro) == 1:
+		return eijiro[0], nil
+	case len(eijiro) == 0 && len(texts) == 1:
+		return texts[0], nil
+	case len(texts) == 0:
+		return nil, errorf("英辞郎のテキストファイル(.TXT)が含まれていません")
+	}
+	names := make([]string, len(texts))
+	for i, f := range texts {
+		names[i] = f.Name
+	}
+	return nil, errorf("変換するファイルを決められません。展開してから目的のファイルを指定してください (候補: %s)", strings.Join(names, ", "))
+}
+
+// multiCloser は展開用のリーダーと、その元になったファイルをまとめて閉じる
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() error {
+	var first error
+	for _, c := range m.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
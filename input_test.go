@@ -0,0 +1,99 @@
+package main
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// writeZipTestFile は指定した名前と内容 (Shift_JISに変換する) のファイルを収めたZIPファイルを作成します。
+func writeZipTestFile(t *testing.T, name string, members map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(file)
+	for member, content := range members {
+		encoded, err := japanese.ShiftJIS.NewEncoder().String(content)
+		if err != nil {
+			t.Fatalf("Shift_JISへの変換に失敗しました: %v", err)
+		}
+		w, err := zw.Create(member)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(encoded))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	return path
+}
+
+// TestParseCompressedInput は .gz と .zip の英辞郎ファイルを展開せずに読み込めることを検証します。
+func TestParseCompressedInput(t *testing.T) {
+	encoded, _ := japanese.ShiftJIS.NewEncoder().String("■door {名} : 扉\n")
+	gzPath := filepath.Join(t.TempDir(), "EIJIRO-1448.TXT.gz")
+	file, err := os.Create(gzPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	gz.Write([]byte(encoded))
+	gz.Close()
+	file.Close()
+
+	zipPath := writeZipTestFile(t, "EIJIRO144.zip", map[string]string{
+		"EIJIRO144/EIJIRO-1448.TXT": "■door {名} : 扉\n",
+		"EIJIRO144/RYAKU-1448.TXT":  "■UN : 国連\n",
+		"EIJIRO144/README.htm":      "",
+	})
+
+	for _, path := range []string{gzPath, zipPath} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			entries, err := parseEijiro(path, ParseOptions{})
+			if err != nil {
+				t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+			}
+			if len(entries) != 1 || entries[0].Headword != "door" || entries[0].Definition != "{名} 扉" {
+				t.Errorf("エントリが不正です: %+v", entries)
+			}
+			// バージョンは展開して読み込んだファイルの名前から決める
+			if version := extractVersionFromFilename(inputName(path)); version != "144.8" {
+				t.Errorf("バージョンが不正です: %s", version)
+			}
+		})
+	}
+}
+
+// TestSelectZipMember はZIPファイルの中から変換するファイルを選べない場合にエラーになることを検証します。
+func TestSelectZipMember(t *testing.T) {
+	testCases := []struct {
+		name    string
+		members map[string]string
+		wantErr bool
+	}{
+		{"テキストファイルが一つ", map[string]string{"dict.txt": ""}, false},
+		{"テキストファイルなし", map[string]string{"README.htm": ""}, true},
+		{"候補が複数", map[string]string{"RYAKU-1448.TXT": "", "REIJI-1448.TXT": ""}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zr, err := zip.OpenReader(writeZipTestFile(t, "test.zip", tc.members))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer zr.Close()
+			if _, err := selectZipMember(zr.File); (err != nil) != tc.wantErr {
+				t.Errorf("エラーの有無が違います: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// 辞書のインストール先の範囲 (ConvertConfig.Install に指定する値)
+const (
+	InstallUser   = "user"   // 実行したユーザーのみが使う辞書ディレクトリ
+	InstallSystem = "system" // すべてのユーザーが使う辞書ディレクトリ (管理者権限が必要)
+)
+
+// installBackupDirName は辞書ディレクトリと同じ階層に置く、以前の版のバックアップのディレクトリ名
+// StarDict や GoldenDict は辞書ディレクトリの下を再帰的に探すため、バックアップを辞書ディレクトリの中に置くと同じ辞書が重複して読み込まれる
+const installBackupDirName = "dic-backup"
+
+// validateInstallScope はインストール先の範囲の指定が正しいかを確認する
+func validateInstallScope(scope string) error {
+	switch scope {
+	case "", InstallUser, InstallSystem:
+		return nil
+	}
+	return errorf("未対応のインストール先です: %s (%s または %s を指定してください)", scope, InstallUser, InstallSystem)
+}
+
+// dictionaryDir はOSとインストール先の範囲に応じた、StarDict 形式の辞書を置くディレクトリを返す
+// - Linux など: ~/.stardict/dic (user), /usr/share/stardict/dic (system)
+// - macOS: ~/.stardict/dic (user), /Library/Application Support/StarDict/dic (system)
+// - Windows: %APPDATA%\GoldenDict\dic (user), %ProgramData%\GoldenDict\dic (system)
+func dictionaryDir(scope, goos string, getenv func(string) string) (string, error) {
+	switch goos {
+	case "windows":
+		key := "APPDATA"
+		if scope == InstallSystem {
+			key = "ProgramData"
+		}
+		base := getenv(key)
+		if base == "" {
+			return "", errorf("環境変数 %s が設定されていないため、インストール先を決められません", key)
+		}
+		return filepath.Join(base, "GoldenDict", "dic"), nil
+	case "darwin":
+		if scope == InstallSystem {
+			return "/Library/Application Support/StarDict/dic", nil
+		}
+	default:
+		if scope == InstallSystem {
+			return "/usr/share/stardict/dic", nil
+		}
+	}
+	home := getenv("HOME")
+	if home == "" {
+		return "", errorf("環境変数 %s が設定されていないため、インストール先を決められません", "HOME")
+	}
+	return filepath.Join(home, ".stardict", "dic"), nil
+}
+
+// installDictionaries は dir 以下に書き出した辞書のファイルを、辞書ディレクトリの <辞書名>/ にインストールし、インストール先を返す
+// 既にインストールされている版は、辞書ディレクトリと同じ階層の dic-backup/<辞書名>-<日時>/ に移してから置き換える
+// 新しい版はいったん一時ディレクトリにコピーしてから名前を変えるため、コピーの途中で失敗しても以前の版は壊れない
+func installDictionaries(dir, scope, bookName string) (string, error) {
+	dicDir, err := dictionaryDir(scope, runtime.GOOS, os.Getenv)
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dicDir, bookName)
+	if err := os.MkdirAll(dicDir, 0755); err != nil {
+		return "", installPermissionError(dicDir, scope, err)
+	}
+	// 辞書ディレクトリの中に作ると書き出し中の辞書が読み込まれかねないため、一時ディレクトリはバックアップのディレクトリに作る
+	backupDir := filepath.Join(filepath.Dir(dicDir), installBackupDirName)
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return "", installPermissionError(backupDir, scope, err)
+	}
+	tmpDir, err := os.MkdirTemp(backupDir, ".installing-*")
+	if err != nil {
+		return "", installPermissionError(backupDir, scope, err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// すべてのユーザーが読めるよう、権限を明示して複製する (system の場合、umask によっては読めなくなるため)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(tmpDir, rel)
+		if d.IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			return os.Chmod(target, 0755)
+		}
+		if err := copyFile(path, target); err != nil {
+			return err
+		}
+		return os.Chmod(target, 0644)
+	})
+	if err != nil {
+		return "", errorf("辞書のファイルのコピーに失敗しました: %w", err)
+	}
+
+	if _, err := os.Stat(dest); err == nil {
+		backup := uniqueBackupPath(filepath.Join(backupDir, bookName+"-"+time.Now().Format("20060102-150405")))
+		if err := os.Rename(dest, backup); err != nil {
+			return "", installPermissionError(dest, scope, err)
+		}
+		logger.Info(sprintf("以前の版を %s に退避しました。", backup), "event", "install_backup", "path", backup)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, dest); err != nil {
+		return "", installPermissionError(dest, scope, err)
+	}
+	return dest, nil
+}
+
+// uniqueBackupPath は同じ名前のバックアップが既にある場合に、末尾に番号を付けた名前を返す
+func uniqueBackupPath(path string) string {
+	candidate := path
+	for i := 2; ; i++ {
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+		candidate = path + "-" + strconv.Itoa(i)
+	}
+}
+
+// installPermissionError は権限の不足による失敗の場合に、対処の方法を添えたエラーを返す
+func installPermissionError(path, scope string, err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		if scope == InstallSystem {
+			return errorf("%s に書き込む権限がありません。管理者権限で実行するか、-install %s を指定してください: %w", path, InstallUser, err)
+		}
+		return errorf("%s に書き込む権限がありません: %w", path, err)
+	}
+	return errorf("%s へのインストールに失敗しました: %w", path, err)
+}
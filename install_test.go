@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestDictionaryDir はOSとインストール先の範囲に応じた辞書ディレクトリを検証します。
+func TestDictionaryDir(t *testing.T) {
+	env := map[string]string{"HOME": "/home/user", "APPDATA": `C:\Users\user\AppData\Roaming`, "ProgramData": `C:\ProgramData`}
+	getenv := func(key string) string { return env[key] }
+	testCases := []struct {
+		name     string
+		scope    string
+		goos     string
+		expected string
+	}{
+		{"Linux user", InstallUser, "linux", filepath.Join("/home/user", ".stardict", "dic")},
+		{"Linux system", InstallSystem, "linux", "/usr/share/stardict/dic"},
+		{"macOS user", InstallUser, "darwin", filepath.Join("/home/user", ".stardict", "dic")},
+		{"macOS system", InstallSystem, "darwin", "/Library/Application Support/StarDict/dic"},
+		{"Windows user", InstallUser, "windows", filepath.Join(`C:\Users\user\AppData\Roaming`, "GoldenDict", "dic")},
+		{"Windows system", InstallSystem, "windows", filepath.Join(`C:\ProgramData`, "GoldenDict", "dic")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := dictionaryDir(tc.scope, tc.goos, getenv)
+			if err != nil {
+				t.Fatalf("dictionaryDirでエラーが発生しました: %v", err)
+			}
+			if dir != tc.expected {
+				t.Errorf("期待値: %s, 実際: %s", tc.expected, dir)
+			}
+		})
+	}
+
+	if _, err := dictionaryDir(InstallUser, "linux", func(string) string { return "" }); err == nil {
+		t.Errorf("HOME が設定されていない場合にエラーになりませんでした")
+	}
+}
+
+// TestRunConversionWithInstall は辞書が <辞書名>/ にインストールされ、再度インストールすると以前の版が退避されることを検証します。
+func TestRunConversionWithInstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("HOME を書き換えられないため、Windowsではスキップします")
+	}
+	installFakeDictzip(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n")
+
+	for i := 0; i < 2; i++ {
+		_, err := runConversion(context.Background(), ConvertConfig{
+			InputFile:     path,
+			OutputDir:     filepath.Join(t.TempDir(), "out"),
+			BookName:      "Test",
+			MergeStrategy: MergeConcat,
+			Install:       InstallUser,
+		})
+		if err != nil {
+			t.Fatalf("runConversionでエラーが発生しました: %v", err)
+		}
+	}
+
+	dest := filepath.Join(home, ".stardict", "dic", "Test")
+	for _, name := range []string{"Test.ifo", "Test.idx", "Test.dict.dz"} {
+		info, err := os.Stat(filepath.Join(dest, name))
+		if err != nil {
+			t.Fatalf("%s がインストールされていません: %v", name, err)
+		}
+		if info.Mode().Perm() != 0644 {
+			t.Errorf("%s の権限が違います: %v", name, info.Mode().Perm())
+		}
+	}
+	// 辞書ディレクトリには新しい版のみがあり、以前の版は dic-backup に退避されている
+	if entries, _ := os.ReadDir(filepath.Join(home, ".stardict", "dic")); len(entries) != 1 {
+		t.Errorf("辞書ディレクトリに余分なファイルがあります: %v", entries)
+	}
+	backups, _ := filepath.Glob(filepath.Join(home, ".stardict", installBackupDirName, "Test-*", "Test.ifo"))
+	if len(backups) != 1 {
+		t.Errorf("以前の版が退避されていません: %v", backups)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ParseStats は英辞郎ファイルの読み込み結果の内訳
+// 読み込んだ行がどこへ行ったかを説明できるよう、行は Headwords から IgnoredLines までのいずれか一つに数える
+type ParseStats struct {
+	Lines             int  // 読み込んだ行数
+	Headwords         int  // 見出し語のエントリを作った行数 (= 生成した見出し語のエントリ数。変化形のリンクを除く)
+	SenseLines        int  // 直前と同じ見出し語に定義を追記した行数
+	AttachedLines     int  // 見出し語にぶら下がる用例 (■・) と補足説明 (◆) の行数
+	BlankLines        int  // 空行の行数
+	LinkEntries       int  // 【変化】から生成した変化形のリンクのエントリ数 (規則変化や【略】の略語から生成したリンクを含む)
+	GeneratedLinks    int  // 規則変化から生成した変化形のリンクのエントリ数 (-generate-inflections)
+	AbbreviationLinks int  // 【略】の略語から生成したリンクのエントリ数 (-abbreviation-links)
+	AliasLinks        int  // 定義全体が別の見出し語の参照 (＝<→…>) のため、参照先へのリンクを加えた行数 (-resolve-aliases)
+	SkippedLines      int  // オプション (-single-word-only, -include-domain, -exclude-domain, -limit, -max-duration など) で除外した見出し語や定義行の行数
+	IgnoredLines      int  // どの見出し語にもぶら下がらないため無視した行数 (空行を除く)
+	Truncated         bool // 時間制限のため読み込みを打ち切った
+	Patched           bool // 差分を適用したキャッシュのため、行の内訳が行数と一致しない
+}
+
+// accountedLines は内訳に数えた行数の合計を返す (Lines と一致するはず)
+func (s ParseStats) accountedLines() int {
+	return s.Headwords + s.SenseLines + s.AttachedLines + s.BlankLines + s.SkippedLines + s.IgnoredLines
+}
+
+// PhaseCount は変換の各段階を終えた時点のエントリ数と、その段階で加えた・除いたエントリ数
+type PhaseCount struct {
+	Phase   string `json:"phase"`
+	Entries int    `json:"entries"`
+	Added   int    `json:"added,omitempty"`
+	Dropped int    `json:"dropped,omitempty"`
+}
+
+// entryLedger は変換の各段階のエントリ数を記録し、想定外の増減を検出する
+// 各段階はエントリを加えた・除いた時点でその数を add・drop で届け出て、段階の終わりに check で実際の件数と照らし合わせる
+// 届け出のない増減 (エントリが黙って消えるなど) は、想定との食い違いとして検出される
+// strict が true の場合は想定外の増減をエラーにし、false の場合は警告のみ表示する
+type entryLedger struct {
+	strict   bool
+	phases   []PhaseCount
+	expected int // 前の段階の件数に、届け出た増減を反映した件数
+	added    int
+	dropped  int
+}
+
+// add は現在の段階で加えたエントリの数を届け出る
+func (l *entryLedger) add(n int) {
+	l.added += n
+}
+
+// drop は現在の段階で除いたエントリの数を届け出る
+func (l *entryLedger) drop(n int) {
+	l.dropped += n
+}
+
+// check は段階を終えた時点のエントリ数を記録し、届け出た増減から求めた件数と一致するかを確認する
+// 一致しない場合も、以降の段階は実際の件数から数え直す
+func (l *entryLedger) check(phase string, got int) error {
+	want := l.expected + l.added - l.dropped
+	l.phases = append(l.phases, PhaseCount{Phase: phase, Entries: got, Added: l.added, Dropped: l.dropped})
+	l.expected, l.added, l.dropped = got, 0, 0
+	return l.expect(phase, got, want)
+}
+
+// expect は段階の前後のエントリ数が想定どおりかを確認する
+// 想定と異なる場合、strict ならエラーを返し、そうでなければ警告を表示して nil を返す
+func (l *entryLedger) expect(phase string, got, want int) error {
+	if got == want {
+		return nil
+	}
+	return l.mismatch(errorf("%sの後のエントリ数が想定と異なります (想定: %d件, 実際: %d件)", tr(phase), want, got),
+		"phase", phase, "want", want, "got", got)
+}
+
+// mismatch は想定との食い違いを、strict ならエラーとして返し、そうでなければ警告を表示して nil を返す
+func (l *entryLedger) mismatch(err error, args ...any) error {
+	if l.strict {
+		return err
+	}
+	logger.Warn(err.Error(), append([]any{"event", "entry_count_mismatch"}, args...)...)
+	return nil
+}
+
+// String は記録したエントリ数の推移を "読み込み 10 → 参照の解決 8 (-2)" の形式で返す
+// 括弧内はその段階で届け出た増減
+func (l *entryLedger) String() string {
+	parts := make([]string, len(l.phases))
+	for i, p := range l.phases {
+		parts[i] = fmt.Sprintf("%s %d", tr(p.Phase), p.Entries)
+		if p.Added > 0 {
+			parts[i] += fmt.Sprintf(" (+%d)", p.Added)
+		}
+		if p.Dropped > 0 {
+			parts[i] += fmt.Sprintf(" (-%d)", p.Dropped)
+		}
+	}
+	return strings.Join(parts, " → ")
+}
+
+// checkParseStats は読み込み結果の内訳を表示し、読み込んだ行数と行の内訳、内訳から求まるエントリ数と実際のエントリ数が一致するかを確認する
+func (l *entryLedger) checkParseStats(stats ParseStats, entries []DictionaryEntry) error {
+	logger.Info(sprintf("%d行を読み込み、見出し語%d件と変化形のリンク%d件を生成しました。", stats.Lines, stats.Headwords, stats.LinkEntries),
+		"event", "parse_stats", "lines", stats.Lines, "headwords", stats.Headwords, "links", stats.LinkEntries,
+		"generated_links", stats.GeneratedLinks, "abbreviation_links", stats.AbbreviationLinks, "skipped_lines", stats.SkippedLines, "ignored_lines", stats.IgnoredLines)
+	if stats.GeneratedLinks > 0 {
+		logger.Info(sprintf("変化形のリンクのうち%d件は、規則変化から生成しました。", stats.GeneratedLinks))
+	}
+	if stats.AbbreviationLinks > 0 {
+		logger.Info(sprintf("変化形のリンクのうち%d件は、【略】の略語から生成しました。", stats.AbbreviationLinks))
+	}
+	if stats.AliasLinks > 0 {
+		logger.Info(sprintf("定義が別の見出し語の参照(＝<→…>)である%d行に、参照先へのリンクを加えました。", stats.AliasLinks), "event", "alias_links", "count", stats.AliasLinks)
+	}
+	if stats.SkippedLines > 0 {
+		logger.Info(sprintf("オプションの指定により%d行を除外しました。", stats.SkippedLines), "event", "skipped_lines", "count", stats.SkippedLines)
+	}
+	if stats.IgnoredLines > 0 {
+		logger.Warn(sprintf("どの見出し語にも属さない%d行を無視しました。", stats.IgnoredLines), "event", "ignored_lines", "count", stats.IgnoredLines)
+	}
+	if accounted := stats.accountedLines(); !stats.Patched && accounted != stats.Lines {
+		err := errorf("読み込んだ行数と行の内訳が一致しません (行数: %d行, 内訳の合計: %d行)", stats.Lines, accounted)
+		if err := l.mismatch(err, "phase", "読み込み", "lines", stats.Lines, "accounted", accounted); err != nil {
+			return err
+		}
+	}
+	l.expected = stats.Headwords + stats.LinkEntries
+	return l.check("読み込み", len(entries))
+}
+
+// countMergeKeys はマージ後に残るはずのエントリ数 (小文字に統一した見出し語の種類数) と、
+// 既にある見出し語と大文字・小文字のみが異なるため定義が使われない見出し語の数を返す
+// (resolveAndMergeEntries は、同じキーの2件目以降の定義をリンク以外は取り込まない)
+func countMergeKeys(entries []DictionaryEntry) (keys, discarded int) {
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Headword)
+		if seen[key] {
+			if !strings.Contains(entry.Definition, "@@@LINK=") {
+				discarded++
+			}
+			continue
+		}
+		seen[key] = true
+	}
+	return len(seen), discarded
+}
+
+// countDistinctHeadwords は見出し語の種類数を返す
+func countDistinctHeadwords(entries []DictionaryEntry) int {
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		seen[entry.Headword] = true
+	}
+	return len(seen)
+}
+
+// writtenEntryCount は書き出した辞書の見出し語の数を返す
+// StarDict形式の場合は、書き出した .idx の項目を数える
+// EPUB は見出し語の索引を持たないため、書き出したエントリ数を返す
+func writtenEntryCount(format, dir, bookName string, entries []DictionaryEntry) (int, error) {
+	if format == FormatEPUB {
+		return len(entries), nil
+	}
+	n, err := countIdxRecords(filepath.Join(dir, bookName))
+	if err != nil {
+		return 0, errorf("書き出した辞書の見出し語を数えられません: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseEijiroWithStats は読み込んだ行がいずれか一つの内訳に数えられ、内訳の合計が行数と一致することを検証します。
+func TestParseEijiroWithStats(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		opts     ParseOptions
+		expected ParseStats
+	}{
+		{
+			name:     "除外・無視・空行",
+			content:  "説明の行\n■door {名} : 扉\n■front door {名} : 玄関\n◆補足\n\n■know {動} : 知っている【変化】《動》knows | knew\n",
+			opts:     ParseOptions{SingleWordOnly: true},
+			expected: ParseStats{Lines: 6, Headwords: 2, LinkEntries: 2, BlankLines: 1, SkippedLines: 2, IgnoredLines: 1},
+		},
+		{
+			name:     "同じ見出し語の定義行と用例",
+			content:  "■door {名} : 扉■・open the door : 扉を開ける\n■door {動} : 閉める\n◆補足\n",
+			expected: ParseStats{Lines: 3, Headwords: 1, SenseLines: 1, AttachedLines: 1},
+		},
+		{
+			name:     "件数の上限で打ち切る",
+			content:  "■door {名} : 扉\n■know {動} : 知っている\n■zebra {名} : シマウマ\n",
+			opts:     ParseOptions{Limit: 1},
+			expected: ParseStats{Lines: 2, Headwords: 1, SkippedLines: 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, stats, err := parseEijiroWithStats(writeEijiroTestFile(t, tc.content), tc.opts)
+			if err != nil {
+				t.Fatalf("parseEijiroWithStatsでエラーが発生しました: %v", err)
+			}
+			if stats != tc.expected {
+				t.Errorf("期待値: %+v, 実際: %+v", tc.expected, stats)
+			}
+			if stats.accountedLines() != stats.Lines {
+				t.Errorf("内訳の合計 (%d行) が行数 (%d行) と一致しません", stats.accountedLines(), stats.Lines)
+			}
+			if len(entries) != stats.Headwords+stats.LinkEntries {
+				t.Errorf("エントリ数が内訳と一致しません: %d件", len(entries))
+			}
+		})
+	}
+}
+
+// TestEntryLedger は届け出のないエントリ数の増減が、strict の場合のみエラーになることを検証します。
+func TestEntryLedger(t *testing.T) {
+	lenient := &entryLedger{}
+	lenient.add(10)
+	if err := lenient.check("読み込み", 9); err != nil {
+		t.Errorf("strict でない場合はエラーにならないはずです: %v", err)
+	}
+
+	strict := &entryLedger{strict: true}
+	strict.add(10)
+	if err := strict.check("読み込み", 10); err != nil {
+		t.Errorf("想定どおりの件数でエラーになりました: %v", err)
+	}
+	strict.drop(2)
+	if err := strict.check("参照の解決", 8); err != nil {
+		t.Errorf("届け出た件数だけ減った場合にエラーになりました: %v", err)
+	}
+	if err := strict.check("書き出し", 7); err == nil {
+		t.Errorf("届け出のない減少でエラーになりませんでした")
+	}
+	if got := strict.String(); got != "読み込み 10 (+10) → 参照の解決 8 (-2) → 書き出し 7" {
+		t.Errorf("推移の表示が不正です: %q", got)
+	}
+}
+
+// TestCountMergeKeys はマージ後のエントリ数と、使われない定義の数を検証します。
+func TestCountMergeKeys(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "Door", Definition: "{名} ドア"},
+		{Headword: "know", Definition: "{動} 知っている"},
+		{Headword: "knew", Definition: "@@@LINK=know"},
+		{Headword: "door", Definition: "@@@LINK=doors"},
+	}
+	keys, discarded := countMergeKeys(entries)
+	if keys != 3 || discarded != 1 {
+		t.Errorf("期待値: 3件と1件, 実際: %d件と%d件", keys, discarded)
+	}
+}
+
+// TestRunConversionPhases は変換の各段階のエントリ数と届け出た増減が記録され、書き出した .idx の見出し語の数と一致することを検証します。
+func TestRunConversionPhases(t *testing.T) {
+	installFakeDictzip(t)
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n■door {動} : 閉める\n■Door {名} : ドア\n■know {動} : 知っている【変化】《動》knows | knew\n")
+	testCases := []struct {
+		name     string
+		strategy string
+		expected []PhaseCount
+	}{
+		{"連結", MergeConcat, []PhaseCount{
+			{Phase: "読み込み", Entries: 5},
+			{Phase: "参照の解決", Entries: 4, Dropped: 1},
+			{Phase: "定義のまとめ", Entries: 4},
+			{Phase: "書き出し", Entries: 4},
+		}},
+		{"語義ごとのエントリ", MergeSeparate, []PhaseCount{
+			{Phase: "読み込み", Entries: 5},
+			{Phase: "参照の解決", Entries: 4, Dropped: 1},
+			{Phase: "定義のまとめ", Entries: 5, Added: 1},
+			{Phase: "書き出し", Entries: 5},
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := runConversion(context.Background(), ConvertConfig{
+				InputFile:     path,
+				OutputDir:     filepath.Join(t.TempDir(), "out"),
+				BookName:      "Test",
+				MergeStrategy: tc.strategy,
+				StrictCounts:  true,
+			})
+			if err != nil {
+				t.Fatalf("runConversionでエラーが発生しました: %v", err)
+			}
+			if !reflect.DeepEqual(summary.Phases, tc.expected) {
+				t.Errorf("期待値: %+v, 実際: %+v", tc.expected, summary.Phases)
+			}
+		})
+	}
+}
+
+// TestCountIdxRecords は書き出した .idx の見出し語の数を数え、途中で終わっている .idx がエラーになることを検証します。
+func TestCountIdxRecords(t *testing.T) {
+	ifoPath := writeTestBook(t, []DictionaryEntry{{Headword: "door", Definition: "扉"}, {Headword: "know", Definition: "知っている"}})
+	base := strings.TrimSuffix(ifoPath, ".ifo")
+	if n, err := countIdxRecords(base); err != nil || n != 2 {
+		t.Errorf("期待値: 2件, 実際: %d件 (%v)", n, err)
+	}
+	data, err := os.ReadFile(base + ".idx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(base+".idx", data[:len(data)-1], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := countIdxRecords(base); err == nil {
+		t.Error("途中で終わっている .idx でエラーになりませんでした")
+	}
+}
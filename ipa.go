@@ -0,0 +1,60 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// rePronunciationContent は【発音】ラベルとその発音記号部分を取り出す正規表現
+var rePronunciationContent = regexp.MustCompile(`【発音([!！]?)】([^【】、]*)`)
+
+// eijiroPhoneticReplacer は英辞郎の発音表記で使われる代替文字をIPAの文字に置き換える
+// Shift_JISで表現できない発音記号は、英辞郎では形の似た全角文字やASCII文字で表記されている
+var eijiroPhoneticReplacer = strings.NewReplacer(
+	"ae", "æ",
+	"α", "ɑ",
+	"Λ", "ʌ",
+	"э", "ə",
+	"∫", "ʃ",
+	"з", "ʒ",
+	"δ", "ð",
+	"η", "ŋ",
+	":", "ː",
+	"：", "ː",
+)
+
+// convertPronunciationsToIPA は定義中の【発音】ラベルの内容をIPAに変換する
+// 例: "【発音！】no'u" -> "【発音！】/nóu/"
+func convertPronunciationsToIPA(def string) string {
+	return rePronunciationContent.ReplaceAllStringFunc(def, func(match string) string {
+		parts := rePronunciationContent.FindStringSubmatch(match)
+		pron := strings.TrimSpace(parts[2])
+		if pron == "" {
+			return match
+		}
+		return "【発音" + parts[1] + "】/" + eijiroToIPA(pron) + "/"
+	})
+}
+
+// eijiroToIPA は英辞郎のASCII発音表記をIPAのUnicode文字列に変換する
+// 英辞郎ではアクセントのある母音の直後に ' (第1アクセント) や ` (第2アクセント) を置くため、
+// これを直前の母音に結合するアクセント記号へ変換する
+func eijiroToIPA(pron string) string {
+	pron = eijiroPhoneticReplacer.Replace(pron)
+
+	var b strings.Builder
+	for _, r := range pron {
+		switch r {
+		case '\'', '’':
+			b.WriteRune('\u0301') // 結合アキュートアクセント
+		case '`':
+			b.WriteRune('\u0300') // 結合グレーブアクセント
+		default:
+			b.WriteRune(r)
+		}
+	}
+	// 結合文字を可能な限り合成済みの文字にまとめる
+	return norm.NFC.String(b.String())
+}
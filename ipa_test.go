@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+// TestConvertPronunciationsToIPA は英辞郎の発音表記がIPAに変換されることを検証します。
+func TestConvertPronunciationsToIPA(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "第1アクセントが直前の母音に結合される",
+			input:    "{動} 知っている、【発音！】no'u、【＠】ノウ",
+			expected: "{動} 知っている、【発音！】/nóu/、【＠】ノウ",
+		},
+		{
+			name:     "代替文字がIPAの文字に置き換わる",
+			input:    "【発音】tae'ktik∫эl",
+			expected: "【発音】/tǽktikʃəl/",
+		},
+		{
+			name:     "長音と第2アクセント",
+			input:    "【発音】i`ntэrvju:",
+			expected: "【発音】/ìntərvjuː/",
+		},
+		{
+			name:     "発音ラベルがない場合は変更しない",
+			input:    "{名} 扉",
+			expected: "{名} 扉",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := convertPronunciationsToIPA(tc.input); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxJMdictMatches は逆引きの見出し語一つに添える JMdict のエントリの最大数
+const maxJMdictMatches = 3
+
+// maxJMdictSenses は JMdict のエントリ一つから添える語義の最大数
+const maxJMdictSenses = 3
+
+// jmdictEntry は JMdict のエントリのうち、相互参照に使う情報
+type jmdictEntry struct {
+	Seq      string     // エントリの通し番号 (ent_seq)
+	Kanji    []string   // 漢字表記 (keb)
+	Readings []string   // 読み (reb)
+	Senses   [][]string // 語義ごとの英語の訳語 (gloss)
+}
+
+// jmdictXMLEntry は JMdict のXMLの <entry> 要素
+type jmdictXMLEntry struct {
+	Seq      string   `xml:"ent_seq"`
+	Kanji    []string `xml:"k_ele>keb"`
+	Readings []string `xml:"r_ele>reb"`
+	Senses   []struct {
+		Glosses []struct {
+			Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"gloss"`
+	} `xml:"sense"`
+}
+
+// jmdictIndex は漢字表記と読みから JMdict のエントリを引くための索引
+type jmdictIndex map[string][]*jmdictEntry
+
+// loadJMdict は JMdict のXMLファイル (JMdict または JMdict_e。拡張子が .gz の場合はgzip圧縮を展開する) を読み込み、
+// 漢字表記と読みの索引を作成する
+// 語義は英語の訳語のみを取り出し、英語の訳語がない語義は除く
+func loadJMdict(path string) (jmdictIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, errorf("gzipの展開に失敗: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return parseJMdict(r)
+}
+
+// parseJMdict は JMdict のXMLを読み込み、漢字表記と読みの索引を作成する
+// JMdict は品詞などをDTDで定義した実体参照 (&n; など) で表すが、相互参照には使わないため、厳密には解釈しない
+func parseJMdict(r io.Reader) (jmdictIndex, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	index := make(jmdictIndex)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errorf("JMdictのXMLの解析に失敗: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "entry" {
+			continue
+		}
+		var raw jmdictXMLEntry
+		if err := decoder.DecodeElement(&raw, &start); err != nil {
+			return nil, errorf("JMdictのエントリの解析に失敗: %w", err)
+		}
+
+		entry := &jmdictEntry{Seq: strings.TrimSpace(raw.Seq), Kanji: raw.Kanji, Readings: raw.Readings}
+		for _, sense := range raw.Senses {
+			var glosses []string
+			for _, gloss := range sense.Glosses {
+				if text := strings.TrimSpace(gloss.Text); text != "" && (gloss.Lang == "" || gloss.Lang == "eng") {
+					glosses = append(glosses, text)
+				}
+			}
+			if len(glosses) > 0 {
+				entry.Senses = append(entry.Senses, glosses)
+			}
+		}
+		for _, key := range appendUnique(appendUnique(nil, entry.Kanji...), entry.Readings...) {
+			index[key] = append(index[key], entry)
+		}
+	}
+	return index, nil
+}
+
+// attachJMdict は逆引きのエントリのうち、見出し語 (日本語の訳語) が JMdict の漢字表記または読みと一致するものに、
+// JMdict のエントリの通し番号・読み・英語の訳語を「【JMdict】」の行として追記する
+// 一致したエントリの読みは検索用キーワードにも加え、読みからも逆引きできるようにする
+func attachJMdict(entries []DictionaryEntry, index jmdictIndex) (matched int) {
+	for i := range entries {
+		candidates := index[entries[i].Headword]
+		if len(candidates) == 0 {
+			continue
+		}
+		if len(candidates) > maxJMdictMatches {
+			candidates = candidates[:maxJMdictMatches]
+		}
+		var lines []string
+		for _, jm := range candidates {
+			lines = append(lines, formatJMdictLine(jm))
+			for _, reading := range jm.Readings {
+				if reading != entries[i].Headword {
+					entries[i].Keywords = appendUnique(entries[i].Keywords, reading)
+				}
+			}
+		}
+		entries[i].Definition += "\n" + strings.Join(lines, "\n")
+		matched++
+	}
+	return matched
+}
+
+// formatJMdictLine は JMdict のエントリを1行の相互参照にする
+// 例: "【JMdict】1433530 とびら: door; gate / hinged door"
+func formatJMdictLine(jm *jmdictEntry) string {
+	var b strings.Builder
+	b.WriteString("【JMdict】")
+	b.WriteString(jm.Seq)
+	if len(jm.Readings) > 0 {
+		b.WriteString(" ")
+		b.WriteString(strings.Join(jm.Readings, "、"))
+	}
+	senses := jm.Senses
+	if len(senses) > maxJMdictSenses {
+		senses = senses[:maxJMdictSenses]
+	}
+	for i, glosses := range senses {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(" / ")
+		}
+		b.WriteString(strings.Join(glosses, "; "))
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testJMdictXML はDTDと実体参照を含む、JMdict の形式のXML
+const testJMdictXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMdict [
+<!ELEMENT JMdict (entry*)>
+<!ENTITY n "noun (common) (futsuumeishi)">
+]>
+<JMdict>
+<entry>
+<ent_seq>1433530</ent_seq>
+<k_ele><keb>扉</keb></k_ele>
+<r_ele><reb>とびら</reb></r_ele>
+<sense><pos>&n;</pos><gloss>door</gloss><gloss>gate</gloss><gloss xml:lang="ger">Tür</gloss></sense>
+<sense><gloss>title page</gloss></sense>
+</entry>
+<entry>
+<ent_seq>1080270</ent_seq>
+<r_ele><reb>ドア</reb></r_ele>
+<sense><pos>&n;</pos><gloss>door</gloss></sense>
+</entry>
+</JMdict>
+`
+
+// TestLoadJMdict は JMdict の漢字表記と読みから、英語の訳語のみを持つエントリを引けることを検証します。
+func TestLoadJMdict(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		name := "XML"
+		if compressed {
+			name = "gzip圧縮"
+		}
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "JMdict_e")
+			if compressed {
+				path += ".gz"
+			}
+			file, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if compressed {
+				gz := gzip.NewWriter(file)
+				gz.Write([]byte(testJMdictXML))
+				gz.Close()
+			} else {
+				file.WriteString(testJMdictXML)
+			}
+			file.Close()
+
+			index, err := loadJMdict(path)
+			if err != nil {
+				t.Fatalf("loadJMdictでエラーが発生しました: %v", err)
+			}
+			expected := &jmdictEntry{Seq: "1433530", Kanji: []string{"扉"}, Readings: []string{"とびら"}, Senses: [][]string{{"door", "gate"}, {"title page"}}}
+			for _, key := range []string{"扉", "とびら"} {
+				if got := index[key]; len(got) != 1 || !reflect.DeepEqual(got[0], expected) {
+					t.Errorf("%s: 期待値: %+v, 実際: %+v", key, expected, got)
+				}
+			}
+		})
+	}
+}
+
+// TestAttachJMdict は逆引きの見出し語に JMdict の行と、読みの検索用キーワードが添えられることを検証します。
+func TestAttachJMdict(t *testing.T) {
+	index, err := parseJMdict(strings.NewReader(testJMdictXML))
+	if err != nil {
+		t.Fatalf("parseJMdictでエラーが発生しました: %v", err)
+	}
+	entries := []DictionaryEntry{
+		{Headword: "ドア", Definition: "door : {名} 扉、ドア"},
+		{Headword: "扉", Definition: "door : {名} 扉、ドア"},
+		{Headword: "開く", Definition: "open : {動} 開く"},
+	}
+	if matched := attachJMdict(entries, index); matched != 2 {
+		t.Errorf("一致した見出し語の数が違います: %d", matched)
+	}
+
+	expected := []string{
+		"door : {名} 扉、ドア\n【JMdict】1080270 ドア: door",
+		"door : {名} 扉、ドア\n【JMdict】1433530 とびら: door; gate / title page",
+		"open : {動} 開く",
+	}
+	for i, entry := range entries {
+		if entry.Definition != expected[i] {
+			t.Errorf("%s: 期待値: %q, 実際: %q", entry.Headword, expected[i], entry.Definition)
+		}
+	}
+	if !reflect.DeepEqual(entries[1].Keywords, []string{"とびら"}) || entries[0].Keywords != nil {
+		t.Errorf("読みの検索用キーワードが違います: %q, %q", entries[0].Keywords, entries[1].Keywords)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// entrySchemaJSON はJSONL出力の各行が従うJSON Schema
+// 下流のパイプラインが参照できるよう schema/entry.schema.json として公開している
+//
+//go:embed schema/entry.schema.json
+var entrySchemaJSON []byte
+
+// jsonlRecord はJSONL出力の1行分のレコード
+// フィールドを変更する場合は schema/entry.schema.json も合わせて更新すること
+type jsonlRecord struct {
+	Headword        string   `json:"headword"`
+	Definition      string   `json:"definition"`
+	Keywords        []string `json:"keywords,omitempty"`
+	Senses          []Sense  `json:"senses,omitempty"`
+	Pronunciation   string   `json:"pronunciation,omitempty"`
+	Katakana        string   `json:"katakana,omitempty"`
+	Level           string   `json:"level,omitempty"`
+	Syllabification string   `json:"syllabification,omitempty"`
+	Hyphenation     []string `json:"hyphenation,omitempty"`
+	CrossRefs       []string `json:"cross_refs,omitempty"`
+	FrequencyRank   int      `json:"frequency_rank,omitempty"`
+}
+
+// newJSONLRecord はエントリからJSONL出力のレコードを生成する
+func newJSONLRecord(entry DictionaryEntry) jsonlRecord {
+	return jsonlRecord{
+		Headword:        entry.Headword,
+		Definition:      entry.Definition,
+		Keywords:        entry.Keywords,
+		Senses:          entry.Senses,
+		Pronunciation:   entry.Pronunciation,
+		Katakana:        entry.Katakana,
+		Level:           entry.Level,
+		Syllabification: entry.Syllabification,
+		Hyphenation:     entry.Hyphenation,
+		CrossRefs:       entry.CrossRefs,
+		FrequencyRank:   entry.FrequencyRank,
+	}
+}
+
+// entry はレコードからエントリを復元する
+func (r jsonlRecord) entry() DictionaryEntry {
+	return DictionaryEntry{
+		Headword:        r.Headword,
+		Definition:      r.Definition,
+		Keywords:        r.Keywords,
+		Senses:          r.Senses,
+		Pronunciation:   r.Pronunciation,
+		Katakana:        r.Katakana,
+		Level:           r.Level,
+		Syllabification: r.Syllabification,
+		Hyphenation:     r.Hyphenation,
+		CrossRefs:       r.CrossRefs,
+		FrequencyRank:   r.FrequencyRank,
+	}
+}
+
+// jsonSchema はエントリのスキーマ検証に必要な範囲のJSON Schemaを表す構造体
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinLength            *int                   `json:"minLength"`
+}
+
+// loadEntrySchema は埋め込まれたエントリのスキーマを読み込む
+func loadEntrySchema() (*jsonSchema, error) {
+	var schema jsonSchema
+	if err := json.Unmarshal(entrySchemaJSON, &schema); err != nil {
+		return nil, errorf("スキーマの読み込みに失敗: %w", err)
+	}
+	return &schema, nil
+}
+
+// writeJSONLFile はエントリをJSONL形式で書き出す
+// validateがtrueの場合、各レコードをスキーマで検証し、違反があれば書き出しを中止する
+func writeJSONLFile(path string, entries []DictionaryEntry, validate bool) error {
+	var schema *jsonSchema
+	if validate {
+		var err error
+		if schema, err = loadEntrySchema(); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, entry := range sortEntriesForStarDict(entries) {
+		line, err := json.Marshal(newJSONLRecord(entry))
+		if err != nil {
+			return errorf("'%s' のJSON変換に失敗: %w", entry.Headword, err)
+		}
+		if schema != nil {
+			if err := validateJSON(schema, line); err != nil {
+				return errorf("'%s' がスキーマに適合しません: %w", entry.Headword, err)
+			}
+		}
+		writer.Write(line)
+		writer.WriteByte('\n')
+	}
+	return writer.Flush()
+}
+
+// validateJSON はJSONデータがスキーマに適合するかを検証する
+func validateJSON(schema *jsonSchema, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return validateValue(schema, value, "$")
+}
+
+// validateValue は値を再帰的にスキーマで検証する
+// pathはエラーメッセージに表示する位置 (例: "$.headword")
+func validateValue(schema *jsonSchema, value any, path string) error {
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return errorf("%s: objectである必要があります", path)
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				return errorf("%s: 必須フィールド '%s' がありません", path, name)
+			}
+		}
+		// エラーメッセージを安定させるため、フィールド名順に検証する
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propSchema, ok := schema.Properties[name]
+			if !ok {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					return errorf("%s: 未定義のフィールド '%s' があります", path, name)
+				}
+				continue
+			}
+			if err := validateValue(propSchema, obj[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return errorf("%s: arrayである必要があります", path)
+		}
+		if schema.Items != nil {
+			for i, item := range arr {
+				if err := validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return errorf("%s: stringである必要があります", path)
+		}
+		if schema.MinLength != nil && utf8.RuneCountInString(str) < *schema.MinLength {
+			return errorf("%s: %d文字以上である必要があります", path, *schema.MinLength)
+		}
+		// 不正なバイト列はJSON変換時に置換文字(U+FFFD)になるため、それを検出する
+		if strings.ContainsRune(str, utf8.RuneError) {
+			return errorf("%s: 不正なUTF-8文字列が含まれています", path)
+		}
+	case "integer":
+		num, ok := value.(float64)
+		if !ok || num != float64(int64(num)) {
+			return errorf("%s: integerである必要があります", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return errorf("%s: numberである必要があります", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return errorf("%s: booleanである必要があります", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestValidateJSON はエントリのスキーマ検証が違反を検出することを検証します。
+func TestValidateJSON(t *testing.T) {
+	schema, err := loadEntrySchema()
+	if err != nil {
+		t.Fatalf("スキーマの読み込みに失敗しました: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "正しいレコード", data: `{"headword":"door","definition":"{名} 扉"}`},
+		{name: "必須フィールドの欠落", data: `{"headword":"door"}`, wantErr: true},
+		{name: "型の不一致", data: `{"headword":"door","definition":1}`, wantErr: true},
+		{name: "空の見出し語", data: `{"headword":"","definition":"扉"}`, wantErr: true},
+		{name: "未定義のフィールド", data: `{"headword":"door","definition":"扉","extra":true}`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJSON(schema, []byte(tc.data))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("期待するエラーの有無: %v, 実際のエラー: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestWriteJSONLFile はJSONL出力の各行がスキーマの形式で書き出されることを検証します。
+func TestWriteJSONLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.jsonl")
+	entries := []DictionaryEntry{
+		{Headword: "know", Definition: "{動} 知っている"},
+		{Headword: "door", Definition: "{名} 扉"},
+	}
+	if err := writeJSONLFile(path, entries, true); err != nil {
+		t.Fatalf("writeJSONLFileでエラーが発生しました: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var headwords []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record jsonlRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("JSONとして読み込めない行があります: %v", err)
+		}
+		headwords = append(headwords, record.Headword)
+	}
+	if len(headwords) != 2 || headwords[0] != "door" || headwords[1] != "know" {
+		t.Errorf("見出し語順に書き出されていません: %v", headwords)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// katakanaBookSuffix はカタカナ語辞書のファイル名に付ける接尾辞
+const katakanaBookSuffix = "-katakana"
+
+// isKatakanaGloss は訳語がカタカナのみからなる (外来語の音訳とみなせる) かどうかを判断する
+// 長音符や中黒、二重ハイフン (ジョン・スミス, ニューヨーク＝ヤンキース) は語の一部として扱う
+// 1文字の訳語 (ト など) は音訳とはみなさない
+func isKatakanaGloss(gloss string) bool {
+	if utf8.RuneCountInString(gloss) < 2 {
+		return false
+	}
+	for _, r := range gloss {
+		if !unicode.Is(unicode.Katakana, r) && !strings.ContainsRune("ー・＝゠ ", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildKatakanaEntries は訳語が主にカタカナ語である見出し語を集め、カタカナの訳語をキーとするエントリを生成する
+// 見出し語の訳語の半数以上がカタカナのみからなる場合に、外来語の見出し語とみなす
+// 各エントリの定義は、逆引き辞書と同じく「英語の見出し語 : 訳語を含む定義行」の一覧になる
+func buildKatakanaEntries(entries []DictionaryEntry) []DictionaryEntry {
+	type katakanaRef struct {
+		headword string
+		line     string
+	}
+	index := make(map[string][]katakanaRef)
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		// リンクでマージされた原形の定義("---"以降)は、その見出し語自身の訳語ではないので対象外とする
+		ownDef, _, _ := strings.Cut(entry.Definition, "\n"+mergeSeparator+"\n")
+		var refs []katakanaRef
+		var keys []string
+		glosses := 0
+		for _, line := range strings.Split(ownDef, "\n") {
+			// 用例(■)と補足説明(◆)の行は訳語ではないので対象外とする
+			if line == "" || strings.HasPrefix(line, "■") || strings.HasPrefix(line, "◆") || strings.HasPrefix(line, "@@@LINK=") {
+				continue
+			}
+			for _, gloss := range extractJapaneseGlosses(line) {
+				glosses++
+				if isKatakanaGloss(gloss) {
+					keys = append(keys, gloss)
+					refs = append(refs, katakanaRef{headword: entry.Headword, line: line})
+				}
+			}
+		}
+		if len(keys) == 0 || len(keys)*2 < glosses {
+			continue
+		}
+		for i, key := range keys {
+			pair := key + "\x00" + entry.Headword
+			if seen[pair] {
+				continue
+			}
+			seen[pair] = true
+			index[key] = append(index[key], refs[i])
+		}
+	}
+
+	katakanaEntries := make([]DictionaryEntry, 0, len(index))
+	for key, refs := range index {
+		lines := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			lines = append(lines, ref.headword+" : "+ref.line)
+		}
+		katakanaEntries = append(katakanaEntries, DictionaryEntry{Headword: key, Definition: strings.Join(lines, "\n")})
+	}
+	// マップの走査順に依存しないよう、見出し語順に並べておく
+	sort.Slice(katakanaEntries, func(i, j int) bool {
+		return katakanaEntries[i].Headword < katakanaEntries[j].Headword
+	})
+	return katakanaEntries
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestIsKatakanaGloss はカタカナのみからなる訳語が外来語の音訳とみなされることを検証します。
+func TestIsKatakanaGloss(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"カタカナ", "コンピューター", true},
+		{"中黒を含む", "ホット・ドッグ", true},
+		{"漢字を含む", "ドア枠", false},
+		{"ひらがな", "とびら", false},
+		{"1文字", "ト", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isKatakanaGloss(tc.input); got != tc.expected {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestBuildKatakanaEntries は訳語が主にカタカナ語である見出し語のみが、カタカナの訳語から引けることを検証します。
+func TestBuildKatakanaEntries(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "computer", Definition: "{名} コンピューター、電子計算機\n◆パソコン"},
+		{Headword: "door", Definition: "{名} 扉、戸、ドア"},
+		{Headword: "hot dog", Definition: "{名} ホットドッグ【レベル】5\n---\n{名} ホット・ドッグ"},
+		{Headword: "computers", Definition: "@@@LINK=computer"},
+	}
+	got := buildKatakanaEntries(entries)
+	expected := []DictionaryEntry{
+		{Headword: "コンピューター", Definition: "computer : {名} コンピューター、電子計算機"},
+		{Headword: "ホットドッグ", Definition: "hot dog : {名} ホットドッグ【レベル】5"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %+v\n実際: %+v", expected, got)
+	}
+}
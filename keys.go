@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// lookupKey は辞書の検索キー（見出し語または別名）と、その参照先の見出し語の組
+type lookupKey struct {
+	Key      string
+	Headword string
+}
+
+// collectLookupKeys はエントリから検索キー（見出し語と別名）を重複なく集め、collation の並べ方でキー順に並べて返す
+func collectLookupKeys(entries []DictionaryEntry, collation string) []lookupKey {
+	seen := make(map[lookupKey]bool)
+	var keys []lookupKey
+	add := func(key, headword string) {
+		k := lookupKey{Key: key, Headword: headword}
+		if key != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	for _, entry := range entries {
+		add(entry.Headword, entry.Headword)
+		for _, keyword := range entry.Keywords {
+			add(keyword, entry.Headword)
+		}
+	}
+	less := collationLess(collation)
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Key != keys[j].Key {
+			return less(keys[i].Key, keys[j].Key)
+		}
+		return keys[i].Headword < keys[j].Headword
+	})
+	return keys
+}
+
+// writeKeysFile は検索キーを入力メソッドや補完エンジン向けの形式で書き出す
+// format は "plain" (1行1キーの一覧) または "mozc" (Mozcのユーザー辞書形式)、collation はキーの並べ方
+func writeKeysFile(path, format, collation string, entries []DictionaryEntry) error {
+	keys := collectLookupKeys(entries, collation)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	switch format {
+	case "plain":
+		var prev string
+		for _, k := range keys {
+			// 同じキーが複数の見出し語を指す場合も一度だけ出力する
+			if k.Key != prev {
+				fmt.Fprintln(writer, k.Key)
+				prev = k.Key
+			}
+		}
+	case "mozc":
+		// Mozcのユーザー辞書: 読み<TAB>単語<TAB>品詞<TAB>コメント
+		// カタカナの別名はひらがなの読みにして、英語の見出し語へ変換できるようにする
+		for _, k := range keys {
+			reading := strings.ToLower(k.Key)
+			if isKatakanaWord(k.Key) {
+				reading = katakanaToHiragana(k.Key)
+			}
+			fmt.Fprintf(writer, "%s\t%s\t名詞\t英辞郎\n", reading, k.Headword)
+		}
+	default:
+		return errorf("未対応のキー出力形式です: %s", format)
+	}
+	return writer.Flush()
+}
+
+// isKatakanaWord は文字列がカタカナ（長音記号・中黒を含む）のみからなるかを判定する
+func isKatakanaWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.In(r, unicode.Katakana) && r != 'ー' && r != '・' {
+			return false
+		}
+	}
+	return true
+}
+
+// katakanaToHiragana はカタカナをひらがなに変換する (例: "タクティカル" -> "たくてぃかる")
+func katakanaToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'ァ' <= r && r <= 'ヶ' {
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
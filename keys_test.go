@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteKeysFile は検索キーが各形式で書き出されることを検証します。
+func TestWriteKeysFile(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "tactical", Keywords: []string{"タクティカル"}},
+		{Headword: "door", Keywords: []string{"door"}},
+	}
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{format: "plain", expected: "door\ntactical\nタクティカル\n"},
+		{format: "mozc", expected: "door\tdoor\t名詞\t英辞郎\ntactical\ttactical\t名詞\t英辞郎\nたくてぃかる\ttactical\t名詞\t英辞郎\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.txt")
+			if err := writeKeysFile(path, tc.format, CollationByte, entries); err != nil {
+				t.Fatalf("writeKeysFileでエラーが発生しました: %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+
+	if err := writeKeysFile(filepath.Join(t.TempDir(), "keys.txt"), "unknown", CollationByte, entries); err == nil {
+		t.Errorf("未対応の形式でエラーになりませんでした")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 削除対象のラベルから内容を取り出すための正規表現
+var (
+	reKatakanaContent        = regexp.MustCompile(`【＠】([^【】]*)`)
+	reSyllabificationContent = regexp.MustCompile(`【分節】([^【】]*)`)
+	reKeywordSplit           = regexp.MustCompile(`[、,]`)
+)
+
+// extractStrippedKeywords は、オプションにより削除されるラベルの内容を検索用キーワードとして取り出す
+// 例: "知っている、【＠】ノウ、【分節】know" -> ["ノウ", "know"]
+func extractStrippedKeywords(def string, opts ParseOptions) []string {
+	var patterns []*regexp.Regexp
+	if opts.StripKatakana {
+		patterns = append(patterns, reKatakanaContent)
+	}
+	if opts.StripSyllabification {
+		patterns = append(patterns, reSyllabificationContent)
+	}
+
+	var keywords []string
+	for _, re := range patterns {
+		for _, match := range re.FindAllStringSubmatch(def, -1) {
+			for _, part := range reKeywordSplit.Split(match[1], -1) {
+				if keyword := strings.TrimSpace(part); keyword != "" {
+					keywords = appendUnique(keywords, keyword)
+				}
+			}
+		}
+	}
+	return keywords
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExtractStrippedKeywords は削除されるラベルの内容のみがキーワードになることを検証します。
+func TestExtractStrippedKeywords(t *testing.T) {
+	def := "{形} 戦術的な、【＠】タクティカル、【分節】tac・ti・cal"
+
+	testCases := []struct {
+		name     string
+		opts     ParseOptions
+		expected []string
+	}{
+		{name: "両方を削除", opts: ParseOptions{StripKatakana: true, StripSyllabification: true}, expected: []string{"タクティカル", "tac・ti・cal"}},
+		{name: "カタカナ発音のみ削除", opts: ParseOptions{StripKatakana: true}, expected: []string{"タクティカル"}},
+		{name: "削除しない", opts: ParseOptions{}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := extractStrippedKeywords(def, tc.opts)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestKeywordsSurviveMerge はキーワードがマージ後のエントリに引き継がれ、.syn ファイルに書き出せることを検証します。
+func TestKeywordsSurviveMerge(t *testing.T) {
+	path := writeEijiroTestFile(t, "■tactical {形} : 戦術的な、【＠】タクティカル、【分節】tac・ti・cal\n■know {動} : 知っている")
+	opts := ParseOptions{StripKatakana: true, StripSyllabification: true, KeepStrippedKeywords: true}
+	entries, err := parseEijiro(path, opts)
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+
+	var tactical *DictionaryEntry
+	for _, entry := range resolveAndMergeEntries(entries) {
+		if entry.Headword == "tactical" {
+			tactical = &entry
+		}
+	}
+	if tactical == nil {
+		t.Fatalf("'tactical' が見つかりませんでした。")
+	}
+	if strings.Contains(tactical.Definition, "タクティカル") {
+		t.Errorf("定義からラベルが削除されていません: %q", tactical.Definition)
+	}
+	if !reflect.DeepEqual(tactical.Keywords, []string{"タクティカル", "tac・ti・cal"}) {
+		t.Errorf("キーワードが引き継がれていません: %v", tactical.Keywords)
+	}
+
+	syn := encodeSyn([]synonymEntry{{Word: "タクティカル", Index: 1}, {Word: "Tac", Index: 0}})
+	expected := append([]byte("Tac\x00\x00\x00\x00\x00"), []byte("タクティカル\x00\x00\x00\x00\x01")...)
+	if !bytes.Equal(syn, expected) {
+		t.Errorf(".syn ファイルの内容が不正です: %q", syn)
+	}
+}
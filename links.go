@@ -0,0 +1,122 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxLinkDepth はリンクをたどる最大の段数
+// 変化形 → 原形 → さらにその参照先、のような連鎖を想定し、これを超える参照はたどらない
+const maxLinkDepth = 5
+
+// reLinkLine は定義中のリンク情報（例: "@@@LINK=drive"）の行
+var reLinkLine = regexp.MustCompile(`(?m)^@@@LINK=(.*)$`)
+
+// reAliasDefinition は定義全体が別の見出し語の参照である定義 (例: "＝<→color>")
+var reAliasDefinition = regexp.MustCompile(`^\s*[＝=]\s*<→([^<>]+)>[\s。]*$`)
+
+// aliasTarget は定義全体が "＝<→color>" のような別の見出し語の参照である場合に、参照先の見出し語を返す
+// 参照のほかに訳語などを含む定義は、それ自体で意味をなすため対象にせず、空文字列を返す
+func aliasTarget(definition string) string {
+	if m := reAliasDefinition.FindStringSubmatch(definition); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// unresolvedLink はリンク先の見出し語が見つからなかった参照
+type unresolvedLink struct {
+	From string // リンク元の見出し語
+	To   string // 見つからなかったリンク先
+}
+
+// linkNode はリンクを取り除いたエントリ自身の定義と、そのリンク先の一覧
+type linkNode struct {
+	own     string
+	targets []string
+}
+
+// splitLinks は定義からリンク情報を取り除き、エントリ自身の定義とリンク先の一覧に分ける
+func splitLinks(def string) linkNode {
+	var node linkNode
+	for _, match := range reLinkLine.FindAllStringSubmatch(def, -1) {
+		// マージ後の見出し語は小文字に統一されているため、リンク先も小文字にしてから探す (例: "@@@LINK=United Nations")
+		if target := strings.ToLower(strings.TrimSpace(match[1])); target != "" {
+			node.targets = appendUnique(node.targets, target)
+		}
+	}
+	node.own = strings.Trim(reLinkLine.ReplaceAllString(def, ""), "\n")
+	return node
+}
+
+// resolveLinks はマージ済みのエントリのリンクを解決し、リンク先の定義を "---" で区切って連結する
+// リンク先がさらにリンクを持つ場合は maxLinkDepth 段までたどり、循環する参照や同じ定義の重複は一度だけ含める
+// リンク先が見つからなかった参照は、リンク情報を取り除いた上で一覧として返す
+func resolveLinks(mergedEntries map[string]*DictionaryEntry) []unresolvedLink {
+	// 連結した結果ではなく、元の定義をたどるため、先にすべてのエントリを分解しておく
+	nodes := make(map[string]linkNode, len(mergedEntries))
+	for key, entry := range mergedEntries {
+		nodes[key] = splitLinks(entry.Definition)
+	}
+
+	var unresolved []unresolvedLink
+	for key, entry := range mergedEntries {
+		node := nodes[key]
+		if len(node.targets) == 0 {
+			continue
+		}
+
+		var parts []string
+		if node.own != "" {
+			parts = append(parts, node.own)
+		}
+		included := map[string]bool{key: true}
+		var collect func(from string, depth int)
+		collect = func(from string, depth int) {
+			for _, target := range nodes[from].targets {
+				if included[target] {
+					continue // 循環参照、または既に含めた定義
+				}
+				targetNode, ok := nodes[target]
+				if !ok {
+					if from == key {
+						unresolved = append(unresolved, unresolvedLink{From: key, To: target})
+					}
+					continue
+				}
+				included[target] = true
+				if targetNode.own != "" {
+					parts = append(parts, targetNode.own)
+				}
+				if depth < maxLinkDepth {
+					collect(target, depth+1)
+				}
+			}
+		}
+		collect(key, 1)
+
+		entry.Definition = strings.Join(parts, "\n"+mergeSeparator+"\n")
+	}
+
+	sort.Slice(unresolved, func(i, j int) bool {
+		if unresolved[i].From != unresolved[j].From {
+			return unresolved[i].From < unresolved[j].From
+		}
+		return unresolved[i].To < unresolved[j].To
+	})
+	return unresolved
+}
+
+// formatUnresolvedLinks はログ表示用に、見つからなかった参照を最大 limit 件まで "元 → 先" の形式で並べる
+func formatUnresolvedLinks(unresolved []unresolvedLink, limit int) string {
+	var items []string
+	for i, link := range unresolved {
+		if i >= limit {
+			items = append(items, "...")
+			break
+		}
+		items = append(items, link.From+" → "+link.To)
+	}
+	return strings.Join(items, ", ")
+}
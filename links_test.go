@@ -0,0 +1,114 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestResolveLinks は多段のリンクと循環参照の解決を検証します。
+func TestResolveLinks(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "drove", Definition: "{動} driveの過去形\n@@@LINK=drive"},
+		{Headword: "drive", Definition: "{動} 運転する"},
+		{Headword: "driven", Definition: "@@@LINK=drove"},
+		{Headword: "alpha", Definition: "{名} アルファ\n@@@LINK=beta"},
+		{Headword: "beta", Definition: "{名} ベータ\n@@@LINK=alpha"},
+		{Headword: "ghost", Definition: "{名} 幽霊\n@@@LINK=nowhere"},
+	}
+
+	merged := make(map[string]*DictionaryEntry)
+	for i := range entries {
+		merged[entries[i].Headword] = &entries[i]
+	}
+	unresolved := resolveLinks(merged)
+
+	expected := map[string]string{
+		"drove":  "{動} driveの過去形\n---\n{動} 運転する",
+		"driven": "{動} driveの過去形\n---\n{動} 運転する",
+		"alpha":  "{名} アルファ\n---\n{名} ベータ",
+		"beta":   "{名} ベータ\n---\n{名} アルファ",
+		"ghost":  "{名} 幽霊",
+	}
+	for headword, def := range expected {
+		if merged[headword].Definition != def {
+			t.Errorf("'%s' の定義が不正です。\n期待値: %q\n実際: %q", headword, def, merged[headword].Definition)
+		}
+	}
+
+	if !reflect.DeepEqual(unresolved, []unresolvedLink{{From: "ghost", To: "nowhere"}}) {
+		t.Errorf("見つからなかった参照が不正です: %+v", unresolved)
+	}
+}
+
+// TestResolveLinksDepthLimit はリンクを maxLinkDepth 段までしかたどらないことを検証します。
+func TestResolveLinksDepthLimit(t *testing.T) {
+	merged := make(map[string]*DictionaryEntry)
+	words := []string{"w0", "w1", "w2", "w3", "w4", "w5", "w6", "w7"}
+	for i, word := range words {
+		def := word
+		if i+1 < len(words) {
+			def += "\n@@@LINK=" + words[i+1]
+		}
+		merged[word] = &DictionaryEntry{Headword: word, Definition: def}
+	}
+	resolveLinks(merged)
+
+	expected := "w0\n---\nw1\n---\nw2\n---\nw3\n---\nw4\n---\nw5"
+	if merged["w0"].Definition != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, merged["w0"].Definition)
+	}
+}
+
+// TestAliasTarget は定義全体が別の見出し語の参照である場合のみ、参照先が取り出されることを検証します。
+func TestAliasTarget(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"全角の等号", "＝<→color>", "color"},
+		{"半角の等号と空白", " = <→color> ", "color"},
+		{"句点", "＝<→color>。", "color"},
+		{"訳語を含む", "色＝<→color>", ""},
+		{"参照の後に訳語が続く", "＝<→color>の英国つづり", ""},
+		{"参照なし", "色", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := aliasTarget(tc.input); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithResolveAliases は -resolve-aliases 指定時に、参照だけの見出し語で参照先の定義を引けることを検証します。
+func TestParseWithResolveAliases(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■colour {名} : ＝<→color>",
+		"■color {名} : 色",
+	}, "\n"))
+
+	entries, stats, err := parseEijiroWithStats(path, ParseOptions{ResolveAliases: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if stats.AliasLinks != 1 {
+		t.Errorf("内訳が不正です: %+v", stats)
+	}
+	final := resolveAndMergeEntries(entries)
+	expected := "{名} ＝<→color>\n" + mergeSeparator + "\n{名} 色"
+	if final[0].Headword != "colour" || final[0].Definition != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, final[0].Definition)
+	}
+
+	// オプションを指定しない場合は参照のまま
+	entries, err = parseEijiro(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if final := resolveAndMergeEntries(entries); strings.Contains(final[0].Definition, "色") {
+		t.Errorf("参照先の定義が加えられています: %q", final[0].Definition)
+	}
+}
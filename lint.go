@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// mojibakeMarkers はUTF-8のテキストをShift_JISとして読み込んだ際に頻出する文字
+// (UTF-8のひらがな・カタカナの先頭バイトが、これらの漢字の一部として解釈される)
+var mojibakeMarkers = []string{"縺", "繧", "繝"}
+
+// lintIssue は英辞郎ファイルの問題のある行
+type lintIssue struct {
+	Line    int
+	Message string
+}
+
+// lintEijiro は英辞郎ファイルを検査し、読み込み時に黙って無視・欠落する内容を行番号付きで返す
+// 検査するのは、既知のどの形式にも当てはまらない行、文字化けや制御文字を含む行、
+// 加工後に定義が空になる見出し語、リンク先の見出し語が見つからないリンクの4種類
+func lintEijiro(path string, opts ParseOptions) ([]lintIssue, error) {
+	issues, err := lintLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parseEijiro(path, opts)
+	if err != nil {
+		return nil, errorf("英辞郎ファイルのパースに失敗しました: %w", err)
+	}
+	keys := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		keys[strings.ToLower(entry.Headword)] = true
+	}
+	for _, entry := range entries {
+		node := splitLinks(entry.Definition)
+		if len(node.targets) == 0 && strings.TrimSpace(node.own) == "" && len(entry.Examples) == 0 {
+			issues = append(issues, lintIssue{Line: entry.SourceLine, Message: sprintf("'%s' の定義が加工後に空になります", entry.Headword)})
+		}
+		for _, target := range node.targets {
+			// 参照の解決と同じく、リンク先は小文字に統一した見出し語とそのまま照合する
+			if !keys[target] {
+				issues = append(issues, lintIssue{Line: entry.SourceLine, Message: sprintf("'%s' のリンク先 '%s' が見つかりません", entry.Headword, target)})
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// lintLines は英辞郎ファイルを1行ずつ検査し、形式や文字の問題がある行を返す
+func lintLines(path string) ([]lintIssue, error) {
+	file, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var issues []lintIssue
+	scanner := bufio.NewScanner(transform.NewReader(file, japanese.ShiftJIS.NewDecoder()))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "■・") && !strings.HasPrefix(line, "◆") && !entryRegex.MatchString(line) {
+			issues = append(issues, lintIssue{Line: lineNo, Message: tr("どの形式にも当てはまらないため無視されます: ") + truncateRunes(line, 40)})
+		}
+		if problem := encodingProblem(line); problem != "" {
+			issues = append(issues, lintIssue{Line: lineNo, Message: problem})
+		}
+	}
+	return issues, scanner.Err()
+}
+
+// encodingProblem は文字化けや制御文字など、文字コードの問題が疑われる場合にその説明を返す
+func encodingProblem(line string) string {
+	if strings.ContainsRune(line, unicode.ReplacementChar) {
+		return tr("Shift_JISとして解釈できないバイト列を含みます")
+	}
+	for _, r := range line {
+		if unicode.IsControl(r) && r != '\t' {
+			return sprintf("制御文字 (U+%04X) を含みます", r)
+		}
+	}
+	for _, marker := range mojibakeMarkers {
+		if strings.Contains(line, marker+marker) || strings.Count(line, marker) >= 3 {
+			return tr("文字化け (UTF-8のテキストをShift_JISとして解釈したもの) の可能性があります")
+		}
+	}
+	return ""
+}
+
+// truncateRunes は文字列を最大 n 文字に切り詰める (切り詰めた場合は末尾に "…" を付ける)
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
+
+// writeLintIssues は問題を "ファイル名:行番号: 内容" の形式で書き出す
+func writeLintIssues(w io.Writer, path string, issues []lintIssue) {
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s:%d: %s\n", path, issue.Line, issue.Message)
+	}
+}
+
+// runLintCommand は lint サブコマンドを実行する
+// 問題が見つかった場合は一覧を出力し、エラーを返す
+func runLintCommand(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter lint [オプション]"))
+		fs.PrintDefaults()
+	}
+	inputFile := fs.String("i", "EIJIRO-1448.TXT", "検査する英辞郎ファイル名")
+	parseOptions := registerParseFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	issues, err := lintEijiro(*inputFile, parseOptions())
+	if err != nil {
+		return err
+	}
+	writeLintIssues(os.Stdout, *inputFile, issues)
+	if len(issues) > 0 {
+		return errorf("%d件の問題が見つかりました", len(issues))
+	}
+	return nil
+}
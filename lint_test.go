@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestLintEijiro は無視される行・空の定義・未解決のリンク・文字化けが行番号付きで報告されることを検証します。
+func TestLintEijiro(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■door {名} : 扉",
+		"door : 見出し語の記号がない",
+		"■went {動} : goの過去形",
+		"■level : 【レベル】1",
+		"■mojibake : 縺縺",
+		"◆補足",
+	}, "\n"))
+	// Shift_JISとして解釈できないバイト列を含む行を追加する
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte("\n\x81\x7f\n"))
+	file.Close()
+
+	issues, err := lintEijiro(path, ParseOptions{StripLevel: true})
+	if err != nil {
+		t.Fatalf("lintEijiroでエラーが発生しました: %v", err)
+	}
+
+	expected := []struct {
+		line    int
+		message string
+	}{
+		{2, "どの形式にも当てはまらない"},
+		{3, "リンク先 'go' が見つかりません"},
+		{4, "'level' の定義が加工後に空になります"},
+		{5, "文字化け"},
+		{7, "どの形式にも当てはまらない"},
+		{7, "Shift_JISとして解釈できない"},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("問題の件数が不正です: got %d, want %d (%+v)", len(issues), len(expected), issues)
+	}
+	for i, want := range expected {
+		if issues[i].Line != want.line || !strings.Contains(issues[i].Message, want.message) {
+			t.Errorf("%d件目の問題が不正です: got %+v, want %d行目の '%s'", i+1, issues[i], want.line, want.message)
+		}
+	}
+}
+
+// TestWriteLintIssues は問題が "ファイル名:行番号: 内容" の形式で出力されることを検証します。
+func TestWriteLintIssues(t *testing.T) {
+	var buf bytes.Buffer
+	writeLintIssues(&buf, "EIJIRO.TXT", []lintIssue{{Line: 3, Message: "問題"}})
+	if got := buf.String(); got != "EIJIRO.TXT:3: 問題\n" {
+		t.Errorf("出力が不正です: %q", got)
+	}
+}
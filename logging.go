@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// levelTrace は -vv で表示する、一行ごとの詳細なログのレベル
+const levelTrace = slog.LevelDebug - 4
+
+// logLevel は表示するログの最も低いレベル (既定では情報のログ以上を表示する)
+var logLevel = new(slog.LevelVar)
+
+// logger は変換の進捗と警告を出力するロガー
+// 既定では log パッケージを通して従来どおりの文章で表示し、-log-json の指定時はJSONの行で出力する
+// 警告などに添える属性 (event, count など) は、JSONの出力でのみ使われる
+var logger = slog.New(textLogHandler{})
+
+// configureLogging はコマンドラインの指定に応じて、ログのレベルと形式を設定する
+// verbosity は -v の場合に1, -vv の場合に2 を指定する
+func configureLogging(verbosity int, quiet, jsonMode bool) error {
+	if quiet && verbosity > 0 {
+		return errors.New(tr("-quiet と -v (-vv) は同時に指定できません"))
+	}
+	switch {
+	case quiet:
+		logLevel.Set(slog.LevelWarn)
+	case verbosity >= 2:
+		logLevel.Set(levelTrace)
+	case verbosity == 1:
+		logLevel.Set(slog.LevelDebug)
+	default:
+		logLevel.Set(slog.LevelInfo)
+	}
+	if jsonMode {
+		logger = slog.New(slog.NewJSONHandler(logWriter{}, &slog.HandlerOptions{
+			Level:       logLevel,
+			ReplaceAttr: replaceLevelName,
+		}))
+	} else {
+		logger = slog.New(textLogHandler{})
+	}
+	return nil
+}
+
+// exitWithError はエラーをログに出力し、終了コード1で終了する
+func exitWithError(err error) {
+	logger.Error(err.Error(), "event", "failed")
+	os.Exit(1)
+}
+
+// replaceLevelName はJSONの出力で、levelTrace を "TRACE" と表記する
+func replaceLevelName(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok && level <= levelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+	}
+	return a
+}
+
+// logWriter は書き込みの時点の log パッケージの出力先に書き込む
+// browse サブコマンドのように、log.SetOutput で一時的に出力を止めている間は slog の出力も止まる
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	return log.Writer().Write(p)
+}
+
+// textLogHandler はログを log パッケージで従来どおりの文章として表示する slog.Handler
+// 警告とエラーには "警告: " "エラー: " を前に付け、属性は表示しない
+type textLogHandler struct{}
+
+func (textLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= logLevel.Level()
+}
+
+func (textLogHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		b.WriteString(tr("エラー: "))
+	case r.Level >= slog.LevelWarn:
+		b.WriteString(tr("警告: "))
+	}
+	b.WriteString(r.Message)
+	log.Print(b.String())
+	return nil
+}
+
+func (h textLogHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h textLogHandler) WithGroup(string) slog.Handler { return h }
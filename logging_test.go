@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureLogs はログの設定を変更して fn を実行し、出力された内容を返します。
+// 終了時にログの設定を既定に戻します。
+func captureLogs(t *testing.T, verbosity int, quiet, jsonMode bool, fn func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	flags := log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(flags)
+		configureLogging(0, false, false)
+	}()
+	if err := configureLogging(verbosity, quiet, jsonMode); err != nil {
+		t.Fatalf("configureLoggingでエラーが発生しました: %v", err)
+	}
+	fn()
+	return buf.String()
+}
+
+// TestConfigureLoggingLevels は -v, -vv, -quiet の指定に応じて表示するログが変わることを検証します。
+func TestConfigureLoggingLevels(t *testing.T) {
+	emit := func() {
+		logger.Log(t.Context(), levelTrace, "行")
+		logger.Debug("詳細")
+		logger.Info("進捗")
+		logger.Warn("注意")
+	}
+	testCases := []struct {
+		name      string
+		verbosity int
+		quiet     bool
+		expected  string
+	}{
+		{"既定", 0, false, "進捗\n警告: 注意\n"},
+		{"-quiet", 0, true, "警告: 注意\n"},
+		{"-v", 1, false, "詳細\n進捗\n警告: 注意\n"},
+		{"-vv", 2, false, "行\n詳細\n進捗\n警告: 注意\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := captureLogs(t, tc.verbosity, tc.quiet, false, emit); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+
+	if err := configureLogging(1, true, false); err == nil {
+		t.Errorf("-quiet と -v の同時指定でエラーになりませんでした")
+	}
+	configureLogging(0, false, false)
+}
+
+// TestLogJSON は -log-json の指定時に、警告が属性付きのJSONの行で出力されることを検証します。
+func TestLogJSON(t *testing.T) {
+	got := captureLogs(t, 2, false, true, func() {
+		logger.Warn("リンク先が見つからない参照が1件ありました。", "event", "unresolved_links", "count", 1)
+		logger.Log(t.Context(), levelTrace, "除外しました", "event", "skipped_line", "line", 3)
+	})
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("出力された行数が違います: %q", got)
+	}
+
+	expected := []map[string]any{
+		{"level": "WARN", "msg": "リンク先が見つからない参照が1件ありました。", "event": "unresolved_links", "count": float64(1)},
+		{"level": "TRACE", "msg": "除外しました", "event": "skipped_line", "line": float64(3)},
+	}
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("JSONの解析に失敗しました: %v (%s)", err, line)
+		}
+		for key, want := range expected[i] {
+			if record[key] != want {
+				t.Errorf("%d行目の %s: 期待値: %v, 実際: %v", i+1, key, want, record[key])
+			}
+		}
+		if _, ok := record[slog.TimeKey]; !ok {
+			t.Errorf("%d行目に時刻がありません: %s", i+1, line)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// defaultLookupLimit は前方一致検索で表示する最大件数の既定値
+const defaultLookupLimit = 20
+
+// lookupResult は検索で見つかった見出し語と、出力形式に変換済みの定義
+type lookupResult struct {
+	Headword   string
+	Definition string
+}
+
+// lookupInEijiro は英辞郎ファイルを変換と同じオプションで処理し、見出し語を検索する
+// prefix が true の場合は前方一致で最大 limit 件を返す
+func lookupInEijiro(path, word string, prefix bool, limit int, opts ParseOptions, wopts WriteOptions, mergeStrategy string) ([]lookupResult, error) {
+	renderer, err := newRenderer(wopts)
+	if err != nil {
+		return nil, err
+	}
+	dict, err := loadEijiroDictionary(path, opts, mergeStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []DictionaryEntry
+	if prefix {
+		found = dict.Prefix(word, limit)
+	} else if entry, ok := dict.Lookup(word); ok {
+		found = append(found, entry)
+	}
+	results := make([]lookupResult, 0, len(found))
+	for _, entry := range found {
+		results = append(results, lookupResult{Headword: entry.Headword, Definition: renderer.Render(entry)})
+	}
+	return results, nil
+}
+
+// loadEijiroDictionary は英辞郎ファイルを変換と同じ手順で読み込み、参照の解決と定義のまとめを行った辞書を返す
+func loadEijiroDictionary(path string, opts ParseOptions, mergeStrategy string) (*Dictionary, error) {
+	if err := validateMergeStrategy(mergeStrategy); err != nil {
+		return nil, err
+	}
+	entries, err := parseEijiro(path, opts)
+	if err != nil {
+		return nil, errorf("英辞郎ファイルのパースに失敗しました: %w", err)
+	}
+	return NewDictionary(applyMergeStrategy(resolveAndMergeEntries(entries), mergeStrategy)), nil
+}
+
+// lookupInStarDict は書き出し済みの辞書から見出し語を検索する
+// 完全一致の検索では .syn の同義語も対象にする
+func lookupInStarDict(ifoPath, word string, prefix bool, limit int) ([]lookupResult, error) {
+	book, err := openStarDict(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+	var results []lookupResult
+	for _, i := range book.find(word, prefix, limit) {
+		fields, err := book.DefinitionFields(i)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, lookupResult{Headword: book.Words[i].Word, Definition: formatDefinitionFields(fields)})
+	}
+	return results, nil
+}
+
+// find は見出し語が word に一致する (prefix が true の場合は word で始まる) 索引上の位置を返す
+// 大文字と小文字は区別しない。limit が0以下の場合は件数を制限しない
+func (b *StarDictBook) find(word string, prefix bool, limit int) []int {
+	var indexes []int
+	seen := make(map[int]bool)
+	add := func(i int) {
+		if !seen[i] && (limit <= 0 || len(indexes) < limit) {
+			seen[i] = true
+			indexes = append(indexes, i)
+		}
+	}
+	match := func(s string) bool {
+		if prefix {
+			return len(s) >= len(word) && strings.EqualFold(s[:len(word)], word)
+		}
+		return strings.EqualFold(s, word)
+	}
+	for i, w := range b.Words {
+		if match(w.Word) {
+			add(i)
+		}
+	}
+	if !prefix {
+		for _, syn := range b.Synonyms {
+			if int(syn.Index) < len(b.Words) && match(syn.Word) {
+				add(int(syn.Index))
+			}
+		}
+	}
+	return indexes
+}
+
+// printLookupResults は検索結果を「見出し語」「定義」の順に、空行で区切って出力する
+func printLookupResults(w io.Writer, results []lookupResult) {
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "■%s\n%s\n", result.Headword, result.Definition)
+	}
+}
+
+// runLookupCommand は lookup サブコマンドを実行する
+// 英辞郎ファイル(-i)を変換と同じオプションで処理するか、書き出し済みの辞書(-dict)を読み込んで見出し語を検索する
+func runLookupCommand(args []string) error {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter lookup [オプション] <見出し語>"))
+		fs.PrintDefaults()
+	}
+	inputFile := fs.String("i", "EIJIRO-1448.TXT", "検索する英辞郎ファイル名")
+	dictFile := fs.String("dict", "", "検索する書き出し済みの辞書(.ifo) (指定した場合は -i より優先する)")
+	prefix := fs.Bool("prefix", false, "見出し語を前方一致で検索する")
+	limit := fs.Int("limit", defaultLookupLimit, "前方一致検索で表示する最大件数 (0の場合は無制限)")
+	mergeStrategy := fs.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)")
+	parseOptions := registerParseFlags(fs)
+	writeOptions := registerWriteFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errorf("検索する見出し語を一つ指定してください")
+	}
+	word := fs.Arg(0)
+
+	var results []lookupResult
+	var err error
+	if *dictFile != "" {
+		results, err = lookupInStarDict(*dictFile, word, *prefix, *limit)
+	} else {
+		results, err = lookupInEijiro(*inputFile, word, *prefix, *limit, parseOptions(), writeOptions(), *mergeStrategy)
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return errorf("'%s' は見つかりませんでした", word)
+	}
+	printLookupResults(os.Stdout, results)
+	return nil
+}
+
+// formatDefinitionFields は項目に分けた定義データを表示用の文字列にする
+// 発音記号の項目 ('t') は「【発音】」を付けて先頭に置き、空の項目は省く
+func formatDefinitionFields(fields []definitionField) string {
+	var parts []string
+	for _, field := range fields {
+		switch {
+		case len(field.Data) == 0:
+		case field.Type == 't':
+			parts = append(parts, "【発音】"+string(field.Data))
+		default:
+			parts = append(parts, string(field.Data))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
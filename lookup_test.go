@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLookupInEijiro は英辞郎ファイルの検索結果に変換と同じオプションが反映されることを検証します。
+func TestLookupInEijiro(t *testing.T) {
+	path := writeEijiroTestFile(t, "■door {名} : 扉■・Close the door. : 扉を閉めて。\n■doors {名} : doorの複数形\n■know {動} : 知っている\n")
+
+	testCases := []struct {
+		name     string
+		word     string
+		prefix   bool
+		opts     ParseOptions
+		expected []lookupResult
+	}{
+		{
+			name:     "完全一致",
+			word:     "Door",
+			expected: []lookupResult{{Headword: "door", Definition: "{名} 扉\n■Close the door. : 扉を閉めて。"}},
+		},
+		{
+			name:     "オプションの反映",
+			word:     "door",
+			opts:     ParseOptions{StripExamples: true},
+			expected: []lookupResult{{Headword: "door", Definition: "{名} 扉"}},
+		},
+		{
+			name:     "前方一致",
+			word:     "doo",
+			prefix:   true,
+			opts:     ParseOptions{StripExamples: true},
+			expected: []lookupResult{{Headword: "door", Definition: "{名} 扉"}, {Headword: "doors", Definition: "{名} doorの複数形"}},
+		},
+		{
+			name:     "見つからない見出し語",
+			word:     "gate",
+			expected: []lookupResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := lookupInEijiro(path, tc.word, tc.prefix, 0, tc.opts, WriteOptions{}, MergeConcat)
+			if err != nil {
+				t.Fatalf("lookupInEijiroでエラーが発生しました: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %+v, 実際: %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestLookupInStarDict は書き出し済みの辞書から見出し語と同義語を検索できることを検証します。
+func TestLookupInStarDict(t *testing.T) {
+	ifoPath := writeTestBook(t, []DictionaryEntry{
+		{Headword: "know", Definition: "{動} 知っている", Keywords: []string{"knew"}},
+		{Headword: "knock", Definition: "{動} ノックする"},
+		{Headword: "door", Definition: "{名} 扉"},
+	})
+
+	results, err := lookupInStarDict(ifoPath, "KNEW", false, 0)
+	if err != nil {
+		t.Fatalf("lookupInStarDictでエラーが発生しました: %v", err)
+	}
+	if len(results) != 1 || results[0].Headword != "know" || results[0].Definition != "{動} 知っている" {
+		t.Errorf("同義語から見出し語を引けていません: %+v", results)
+	}
+
+	results, err = lookupInStarDict(ifoPath, "kn", true, 1)
+	if err != nil {
+		t.Fatalf("lookupInStarDictでエラーが発生しました: %v", err)
+	}
+	if len(results) != 1 || results[0].Headword != "knock" {
+		t.Errorf("前方一致の結果が不正です: %+v", results)
+	}
+
+	var buf bytes.Buffer
+	printLookupResults(&buf, []lookupResult{{Headword: "a", Definition: "x"}, {Headword: "b", Definition: "y"}})
+	if buf.String() != "■a\nx\n\n■b\ny\n" {
+		t.Errorf("出力の形式が不正です: %q", buf.String())
+	}
+}
+
+// TestPhoneticField は発音記号を別の項目として書き出した辞書を、項目に分けて読み込めることを検証します。
+func TestPhoneticField(t *testing.T) {
+	installFakeDictzip(t)
+	dir := t.TempDir()
+	entries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉、【発音】dɔ́ːr、【＠】ドア", Pronunciation: "dɔ́ːr"},
+		{Headword: "know", Definition: "{動} 知っている"},
+	}
+	if err := writeStarDictFiles(dir, "Test", "1.0", entries, WriteOptions{PhoneticField: true}); err != nil {
+		t.Fatalf("writeStarDictFilesでエラーが発生しました: %v", err)
+	}
+	ifo, err := os.ReadFile(filepath.Join(dir, "Test.ifo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ifo), "sametypesequence=tg\n") {
+		t.Errorf(".ifo の sametypesequence が違います:\n%s", ifo)
+	}
+
+	book, err := openStarDict(filepath.Join(dir, "Test.ifo"))
+	if err != nil {
+		t.Fatalf("openStarDictでエラーが発生しました: %v", err)
+	}
+	if problems := validateStarDictBook(book); len(problems) != 0 {
+		t.Errorf("問題が報告されました: %v", problems)
+	}
+	fields, err := book.DefinitionFields(0)
+	if err != nil {
+		t.Fatalf("DefinitionFieldsでエラーが発生しました: %v", err)
+	}
+	expected := []definitionField{{Type: 't', Data: []byte("dɔ́ːr")}, {Type: 'g', Data: []byte("{名} 扉【＠】ドア")}}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("期待値: %q, 実際: %q", expected, fields)
+	}
+
+	// 発音記号のないエントリは空の項目を持ち、検索結果では省かれる
+	results, err := lookupInStarDict(filepath.Join(dir, "Test.ifo"), "know", false, 0)
+	if err != nil {
+		t.Fatalf("lookupInStarDictでエラーが発生しました: %v", err)
+	}
+	if len(results) != 1 || results[0].Definition != "{動} 知っている" {
+		t.Errorf("検索結果が違います: %+v", results)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// arenaChunkSize は stringArena が一度に確保する領域の大きさ
+const arenaChunkSize = 1 << 20
+
+// stringArena は多数の短い文字列を、大きな領域にまとめて保持する
+// 英辞郎全体では見出し語や訳語などの文字列が数千万個になり、個別に確保すると
+// 割り当ての端数やGCの管理情報だけで大きなメモリを使うため、パースした文字列はここに集める
+// 一度書き込んだ領域は変更しないため、返した文字列は領域を使い切った後も有効なまま残る
+type stringArena struct {
+	chunk strings.Builder
+}
+
+// store は s の内容を領域に複写し、領域内を指す文字列を返す
+// 元の文字列 (読み込んだ行全体など) を参照し続けないため、不要になった行は解放される
+func (a *stringArena) store(s string) string {
+	if s == "" {
+		return ""
+	}
+	// 大きな文字列は領域の無駄が大きいため、個別に複写する
+	if len(s) > arenaChunkSize/4 {
+		return strings.Clone(s)
+	}
+	a.reserve(len(s))
+	start := a.chunk.Len()
+	a.chunk.WriteString(s)
+	// 容量を超えて書き込まないため、String() が返す文字列の領域は再確保されない
+	return a.chunk.String()[start:]
+}
+
+// storeBytes は b の内容を領域に複写し、領域内を指す文字列を返す
+// 書き込み中のバッファの内容を、途中の文字列を作らずに保持するために使う
+func (a *stringArena) storeBytes(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if len(b) > arenaChunkSize/4 {
+		return string(b)
+	}
+	a.reserve(len(b))
+	start := a.chunk.Len()
+	a.chunk.Write(b)
+	return a.chunk.String()[start:]
+}
+
+// reserve は n バイトを書き込める空きがなければ、新しい領域を確保する
+func (a *stringArena) reserve(n int) {
+	if a.chunk.Cap()-a.chunk.Len() < n {
+		a.chunk = strings.Builder{}
+		a.chunk.Grow(arenaChunkSize)
+	}
+}
+
+// storeAll はスライスの各要素を領域内の文字列に置き換える
+func (a *stringArena) storeAll(values []string) {
+	for i, s := range values {
+		values[i] = a.store(s)
+	}
+}
+
+// stringInterner は同じ内容の文字列を一つにまとめる
+// 品詞やラベル、単語レベルなど、種類が少なく何度も現れる文字列に使う
+type stringInterner map[string]string
+
+// intern は s と同じ内容の文字列を返す (初めて現れた内容は複写して登録する)
+func (in stringInterner) intern(s string) string {
+	if interned, ok := in[s]; ok {
+		return interned
+	}
+	s = strings.Clone(s)
+	in[s] = s
+	return s
+}
+
+// internAll はスライスの各要素を、まとめた文字列に置き換える
+func (in stringInterner) internAll(values []string) {
+	for i, s := range values {
+		values[i] = in.intern(s)
+	}
+}
+
+// compactParsedEntries はパースしたエントリの文字列を entryCompactor で置き換えるかどうか
+// 文字列を読み込んだ行の一部のまま保持する以前の読み込み方と、テストでメモリの使用量を比べる場合にのみ false にする
+var compactParsedEntries = true
+
+// entryCompactor はパースしたエントリの文字列を、少ないメモリで保持できる形に置き換える
+type entryCompactor struct {
+	arena    stringArena
+	labels   stringInterner
+	disabled bool // 置き換えを行わない (compactParsedEntries が false の場合)
+}
+
+func newEntryCompactor() *entryCompactor {
+	return &entryCompactor{labels: make(stringInterner), disabled: !compactParsedEntries}
+}
+
+// store は s を領域に複写した文字列を返す
+func (c *entryCompactor) store(s string) string {
+	if c.disabled {
+		return s
+	}
+	return c.arena.store(s)
+}
+
+// storeBytes は b の内容を領域に複写した文字列を返す
+func (c *entryCompactor) storeBytes(b []byte) string {
+	if c.disabled {
+		return string(b)
+	}
+	return c.arena.storeBytes(b)
+}
+
+// compact はエントリの文字列を置き換える
+// 文字列の内容は変わらないため、呼び出し側から見た結果は置き換える前と同じになる
+func (c *entryCompactor) compact(entry *DictionaryEntry) {
+	if c.disabled {
+		return
+	}
+	entry.Headword = c.arena.store(entry.Headword)
+	entry.Definition = c.arena.store(entry.Definition)
+	entry.Audio = c.arena.store(entry.Audio)
+	entry.Pronunciation = c.arena.store(entry.Pronunciation)
+	entry.Katakana = c.arena.store(entry.Katakana)
+	entry.Syllabification = c.arena.store(entry.Syllabification)
+	entry.Level = c.labels.intern(entry.Level)
+	c.arena.storeAll(entry.Examples)
+	c.arena.storeAll(entry.Keywords)
+	c.arena.storeAll(entry.CrossRefs)
+	c.arena.storeAll(entry.Hyphenation)
+	// 追記のために余分に確保された容量を手放す
+	entry.Senses = shrink(entry.Senses)
+	for i := range entry.Senses {
+		sense := &entry.Senses[i]
+		sense.POS = c.labels.intern(sense.POS)
+		sense.Labels = shrink(sense.Labels)
+		c.labels.internAll(sense.Labels)
+		sense.Domains = shrink(sense.Domains)
+		c.labels.internAll(sense.Domains)
+		sense.Gloss = c.arena.store(sense.Gloss)
+		c.arena.storeAll(sense.Alternatives)
+		c.arena.storeAll(sense.Examples)
+		c.arena.storeAll(sense.Supplements)
+	}
+}
+
+// shrink は容量に余りがあるスライスを、要素数ちょうどの大きさに複写する
+func shrink[S ~[]E, E any](s S) S {
+	if cap(s) == len(s) {
+		return s
+	}
+	return slices.Clone(s)
+}
+
+// clipEntry はスライスを複製せずに、追記しても元のエントリに影響しないエントリの複製を返す
+// 容量を要素数に切り詰めるため、追記すると新しい領域が確保される
+// スライスの要素は元のエントリと共有するため、要素を書き換える処理は先にスライスを複製すること
+// 参照の解決後の処理のうち、要素を書き換えるのは語義を並べ替える rankSenses のみで、語義を複製してから並べ替える
+// その他の処理 (applyMergeStrategy, addFuzzyKeys など) は、新しいスライスを作るか、追記やフィールドの置き換えのみを行う
+func clipEntry(entry DictionaryEntry) DictionaryEntry {
+	entry.Examples = slices.Clip(entry.Examples)
+	entry.Keywords = slices.Clip(entry.Keywords)
+	entry.Senses = slices.Clip(entry.Senses)
+	entry.CrossRefs = slices.Clip(entry.CrossRefs)
+	return entry
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// TestStringArena は領域を使い切って新しい領域に移った後も、以前に返した文字列が変わらないことを検証します。
+func TestStringArena(t *testing.T) {
+	var arena stringArena
+	word := strings.Repeat("あ", 1000)
+	var stored []string
+	// 1つの領域に収まらない量を書き込む
+	for i := 0; i < 2*arenaChunkSize/len(word); i++ {
+		stored = append(stored, arena.store(word))
+	}
+	stored = append(stored, arena.storeBytes([]byte("door")))
+	large := strings.Repeat("x", arenaChunkSize)
+	stored = append(stored, arena.store(large))
+
+	for i, s := range stored[:len(stored)-2] {
+		if s != word {
+			t.Fatalf("%d件目の文字列が変わっています", i+1)
+		}
+	}
+	if stored[len(stored)-2] != "door" || stored[len(stored)-1] != large {
+		t.Error("storeBytes または大きな文字列の保持に失敗しています")
+	}
+	if arena.store("") != "" || arena.storeBytes(nil) != "" {
+		t.Error("空文字列が空のまま返されていません")
+	}
+}
+
+// TestStringInterner は同じ内容の文字列が一つにまとめられることを検証します。
+func TestStringInterner(t *testing.T) {
+	interner := make(stringInterner)
+	labels := []string{"名", "動", "名", "名"}
+	interner.internAll(labels)
+	if !reflect.DeepEqual(labels, []string{"名", "動", "名", "名"}) {
+		t.Errorf("文字列の内容が変わっています: %v", labels)
+	}
+	if len(interner) != 2 {
+		t.Errorf("登録された文字列の数 期待値: 2, 実際: %d", len(interner))
+	}
+}
+
+// TestEntryCompactor はエントリの文字列を置き換えても内容が変わらないことを検証します。
+func TestEntryCompactor(t *testing.T) {
+	entry := DictionaryEntry{
+		Headword:      "door",
+		Definition:    "{名} 扉",
+		Keywords:      []string{"doors"},
+		Pronunciation: "dɔ́ːr",
+		Level:         "1",
+		Senses:        append(make([]Sense, 0, 4), Sense{POS: "名", Gloss: "扉", Labels: []string{"大学入試"}, Examples: []string{"Close the door."}}),
+		CrossRefs:     []string{"gate"},
+	}
+	expected := cloneEntry(entry)
+
+	newEntryCompactor().compact(&entry)
+	if !reflect.DeepEqual(entry, expected) {
+		t.Errorf("期待値: %+v, 実際: %+v", expected, entry)
+	}
+	if cap(entry.Senses) != len(entry.Senses) {
+		t.Errorf("語義のスライスの余分な容量が残っています: cap=%d", cap(entry.Senses))
+	}
+}
+
+// TestResolveAndMergeEntriesSharesSlices は参照の解決後のエントリへの追記や並べ替えが、元のエントリに影響しないことを検証します。
+func TestResolveAndMergeEntriesSharesSlices(t *testing.T) {
+	entries := []DictionaryEntry{{
+		Headword:   "Door",
+		Definition: "{名} 扉",
+		Keywords:   append(make([]string, 0, 4), "doors"),
+		Senses:     []Sense{{Gloss: "《建》戸口"}, {Gloss: "扉"}},
+	}}
+	original := cloneEntry(entries[0])
+
+	final := resolveAndMergeEntries(entries)
+	preserveHeadwordCase(final, entries)
+	rankSenses(final)
+	if final[0].Senses[0].Gloss != "扉" {
+		t.Errorf("語義が並べ替えられていません: %+v", final[0].Senses)
+	}
+	if !reflect.DeepEqual(entries[0], original) {
+		t.Errorf("元のエントリが書き換えられています: %+v", entries[0])
+	}
+	if extended := entries[0].Keywords[:cap(entries[0].Keywords)]; extended[1] != "" {
+		t.Errorf("元のエントリのスライスの領域に追記されています: %q", extended)
+	}
+}
+
+// TestPostMergeMutatorsKeepSource は参照の解決後にエントリを書き換える各処理が、
+// スライスの要素を共有する元のエントリを書き換えないことを検証します。
+func TestPostMergeMutatorsKeepSource(t *testing.T) {
+	input := writeEijiroTestFile(t, `■door {名-1} : 《建》戸口、扉【レベル】1、【変化】《複》doors■・open the door : 扉を開ける
+■door {名-2} : 〔比喩的に〕門戸◆【用法】to の前で
+■door {動-1} : 閉める
+■doors : 《複》door
+■doorway {名} : 戸口
+`)
+	index := newTatoebaIndex([]tatoebaPair{{"Open the door.", "ドアを開けて。"}})
+	jmdict := jmdictIndex{"扉": {{Kanji: []string{"扉"}, Readings: []string{"とびら"}}}}
+	testCases := []struct {
+		name   string
+		mutate func([]DictionaryEntry) []DictionaryEntry
+	}{
+		{"大文字小文字の復元", func(final []DictionaryEntry) []DictionaryEntry {
+			applyHeadwordCase(final, map[string]string{"door": "Door"})
+			return final
+		}},
+		{"Tatoebaの用例", func(final []DictionaryEntry) []DictionaryEntry {
+			attachTatoebaExamples(final, index, 1)
+			return final
+		}},
+		{"頻度", func(final []DictionaryEntry) []DictionaryEntry {
+			annotateFrequency(final, frequencyList{"door": 1}, true)
+			return final
+		}},
+		{"語義の並べ替え", func(final []DictionaryEntry) []DictionaryEntry {
+			rankSenses(final)
+			return final
+		}},
+		{"関連語", func(final []DictionaryEntry) []DictionaryEntry {
+			addRelatedWords(final)
+			return final
+		}},
+		{"あいまい検索", func(final []DictionaryEntry) []DictionaryEntry {
+			addFuzzyKeys(final)
+			return final
+		}},
+		{"ふりがな", func(final []DictionaryEntry) []DictionaryEntry {
+			addFuriganaToEntries(final, newFuriganaDict(jmdict))
+			return final
+		}},
+		{"語義ごとのエントリ", func(final []DictionaryEntry) []DictionaryEntry {
+			return applyMergeStrategy(final, MergeSeparate)
+		}},
+		{"品詞ごとのエントリ", func(final []DictionaryEntry) []DictionaryEntry {
+			return applyMergeStrategy(final, MergeSplitPOS)
+		}},
+		{"品詞ごとの番号", func(final []DictionaryEntry) []DictionaryEntry {
+			return applyMergeStrategy(final, MergePOS)
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := parseEijiro(input, ParseOptions{})
+			if err != nil {
+				t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+			}
+			original := make([]DictionaryEntry, len(entries))
+			for i, entry := range entries {
+				original[i] = cloneEntry(entry)
+			}
+			final := tc.mutate(resolveAndMergeEntries(entries))
+			if len(final) == 0 {
+				t.Fatal("エントリがありません")
+			}
+			if !reflect.DeepEqual(entries, original) {
+				t.Errorf("元のエントリが書き換えられています: %+v", entries)
+			}
+		})
+	}
+}
+
+// TestMergeEntriesInPlace は引数の領域を再利用しても、resolveAndMergeEntries と同じ結果になり、
+// 詰めた後に残った領域が文字列を参照し続けないことを検証します。
+func TestMergeEntriesInPlace(t *testing.T) {
+	entries, err := ParseFrom(bytes.NewReader(memoryTestInput(t, 50)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseFromでエラーが発生しました: %v", err)
+	}
+	expected := resolveAndMergeEntries(entries)
+	n := len(entries)
+
+	final, merged := mergeEntriesInPlace(entries)
+	if !reflect.DeepEqual(final, expected) {
+		t.Errorf("resolveAndMergeEntries と結果が違います")
+	}
+	if len(final)+merged.Links+merged.Discarded != n {
+		t.Errorf("まとめたエントリの数が合いません: 結果 %d件, リンク %d件, 重複 %d件, 元 %d件", len(final), merged.Links, merged.Discarded, n)
+	}
+	if &final[0] != &entries[0] {
+		t.Error("引数の領域が再利用されていません")
+	}
+	if cap(final) != len(final) {
+		t.Errorf("結果の容量が切り詰められていません: len=%d, cap=%d", len(final), cap(final))
+	}
+	for _, entry := range entries[len(final):n] {
+		if !reflect.DeepEqual(entry, DictionaryEntry{}) {
+			t.Fatalf("詰めた後の領域が空になっていません: %+v", entry)
+		}
+	}
+}
+
+// memoryTestInput は保持するメモリを測るための、英辞郎形式 (Shift_JIS) の n 件の見出し語のデータを返す
+// 見出し語ごとに、ラベルと補足の付いた語義、用例、品詞の違う語義、変化形へのリンクを持つ
+func memoryTestInput(tb testing.TB, n int) []byte {
+	tb.Helper()
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "■word%d {他動-1} : 〔計画・方針などを〕断念する、放棄する、〔人・場所などを〕見捨てる、置き去りにする◆【類】give up【レベル】3、【発音】abaendn、【＠】アバンダン、【変化】《動》word%ds | word%ded | word%ding、【分節】a・ban・don■・They had to word%d the plan. : 彼らはその計画を断念しなければならなかった。\n", i, i, i, i, i)
+		fmt.Fprintf(&b, "■word%d {名-1} : 〔感情の〕奔放さ、気まま、自暴自棄◆【用法】with word%dの形で用いられることが多い。\n", i, i)
+	}
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(b.String())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return []byte(encoded)
+}
+
+// legacyResolveAndMergeEntries は以前の参照の解決の仕方を再現する
+// 見出し語ごとにエントリを複製してマップに集め、リンクを解決した後、マップから新しいスライスを作り直す
+func legacyResolveAndMergeEntries(entries []DictionaryEntry) []DictionaryEntry {
+	mergedEntries := make(map[string]*DictionaryEntry)
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Headword)
+		isLinkEntry := strings.Contains(entry.Definition, "@@@LINK=")
+		if existing, exists := mergedEntries[key]; exists {
+			if isLinkEntry && !strings.Contains(existing.Definition, "@@@LINK=") {
+				existing.Definition += "\n" + entry.Definition
+			}
+			existing.Keywords = appendUnique(existing.Keywords, entry.Keywords...)
+		} else {
+			newEntry := cloneEntry(entry)
+			newEntry.Headword = key
+			mergedEntries[key] = &newEntry
+		}
+	}
+	resolveLinks(mergedEntries)
+	finalEntries := make([]DictionaryEntry, 0, len(mergedEntries))
+	for _, entry := range mergedEntries {
+		finalEntries = append(finalEntries, *entry)
+	}
+	return finalEntries
+}
+
+// peakHeap は run を実行している間のヒープの使用量 (HeapInuse) の最大値を、実行前からの増分で返す
+// 使用量は別のgoroutineから1ミリ秒ごとに読み取るため、読み取りの間に生じた短い山は含まれないことがある
+func peakHeap(run func()) uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	base := stats.HeapInuse
+
+	var peak uint64
+	sample := func() {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		peak = max(peak, stats.HeapInuse)
+	}
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				sample()
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+	run()
+	close(done)
+	<-sampled
+	if peak < base {
+		return 0
+	}
+	return peak - base
+}
+
+// measureConversionPeak は読み込みから .idx と .dict の組み立てまでのヒープの使用量の最大値を、
+// 現在の変換の仕方 (current) と、以前の変換の仕方 (legacy) について測る
+// 現在は文字列を領域にまとめて保持し、パースしたエントリの領域を再利用して参照を解決し、並べた順序のみを求めて書き出す
+// 以前は文字列を読み込んだ行の一部のまま保持し、マップを使って参照を解決してパース結果も変換の終わりまで保持し、
+// 書き出す際にエントリ全体を並べ替えたコピーを作っていた
+func measureConversionPeak(tb testing.TB, input []byte) (current, legacy uint64) {
+	parse := func() []DictionaryEntry {
+		entries, err := ParseFrom(bytes.NewReader(input), ParseOptions{})
+		if err != nil {
+			tb.Fatal(err)
+		}
+		return entries
+	}
+	build := func(entries []DictionaryEntry) starDictData {
+		data, err := buildStarDictData(entries, plainRenderer{})
+		if err != nil {
+			tb.Fatal(err)
+		}
+		return data
+	}
+
+	current = peakHeap(func() {
+		final, _ := mergeEntriesInPlace(parse())
+		runtime.KeepAlive(build(final))
+	})
+
+	compactParsedEntries = false
+	defer func() { compactParsedEntries = true }()
+	legacy = peakHeap(func() {
+		entries := parse()
+		final := legacyResolveAndMergeEntries(entries)
+		runtime.KeepAlive(build(sortEntriesForStarDict(final)))
+		runtime.KeepAlive(entries)
+	})
+	return current, legacy
+}
+
+// TestConversionPeakMemory は変換中のヒープの使用量の最大値が、以前の変換の仕方の8割以下になることを検証します。
+// 手元の計測では6割台のため、計測のばらつきを見込んで基準を緩めている
+func TestConversionPeakMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("メモリの計測は -short では省略します")
+	}
+	current, legacy := measureConversionPeak(t, memoryTestInput(t, 20000))
+	t.Logf("ヒープの使用量の最大値: 現在 %d バイト, 以前 %d バイト (%.0f%%)", current, legacy, float64(current)*100/float64(legacy))
+	if current*5 > legacy*4 {
+		t.Errorf("ヒープの使用量の最大値が以前の8割以下になっていません: 現在 %d バイト, 以前 %d バイト", current, legacy)
+	}
+}
+
+// BenchmarkConversionPeak は変換中のヒープの使用量の最大値を、以前の変換の仕方と比較します。
+// go test -run '^$' -bench ConversionPeak で、見出し語1件あたりのバイト数を出力する
+func BenchmarkConversionPeak(b *testing.B) {
+	const n = 20000
+	input := memoryTestInput(b, n)
+	var current, legacy uint64
+	for b.Loop() {
+		current, legacy = measureConversionPeak(b, input)
+	}
+	b.ReportMetric(float64(current)/n, "current-B/headword")
+	b.ReportMetric(float64(legacy)/n, "legacy-B/headword")
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 同じ見出し語の定義をまとめる方法
+const (
+	MergeConcat   = "concat"    // 定義を改行でつなげる (従来の動作)
+	MergeNumbered = "numbered"  // 語義ごとに番号を付ける
+	MergePOS      = "pos"       // 品詞ごとにまとめ、品詞内で番号を付ける
+	MergeSeparate = "separate"  // 語義ごとに別のエントリとして出力する
+	MergeSplitPOS = "split-pos" // 品詞ごとに別のエントリとして出力する
+)
+
+// rePOSPrefix は定義行の先頭の品詞情報 (例: "{名} 扉" の "{名}")
+var rePOSPrefix = regexp.MustCompile(`^(\{.*?\})\s*`)
+
+// senseBlock は語義一つ分の行のまとまり (定義行と、それに続く用例・補足説明の行)
+type senseBlock struct {
+	lines []string
+}
+
+// validateMergeStrategy はまとめ方の指定が正しいかを確認する
+func validateMergeStrategy(strategy string) error {
+	switch strategy {
+	case "", MergeConcat, MergeNumbered, MergePOS, MergeSeparate, MergeSplitPOS:
+		return nil
+	}
+	return errorf("未対応のまとめ方です: %s (concat, numbered, pos, separate, split-pos のいずれかを指定してください)", strategy)
+}
+
+// mergeChangesEntryCount はまとめ方によってエントリ数が変わるかどうかを返す
+func mergeChangesEntryCount(strategy string) bool {
+	return strategy == MergeSeparate || strategy == MergeSplitPOS
+}
+
+// applyMergeStrategy はマージ済みのエントリの定義を、指定されたまとめ方に組み替える
+// リンク先(原形)の定義は "---" で区切られた部分ごとに組み替える
+func applyMergeStrategy(entries []DictionaryEntry, strategy string) []DictionaryEntry {
+	switch strategy {
+	case "", MergeConcat:
+		return entries
+	case MergeSeparate:
+		return separateSenses(entries)
+	case MergeSplitPOS:
+		return splitByPOS(entries)
+	}
+
+	for i := range entries {
+		segments := strings.Split(entries[i].Definition, "\n"+mergeSeparator+"\n")
+		for j, segment := range segments {
+			blocks := splitSenseBlocks(segment)
+			if strategy == MergePOS {
+				segments[j] = formatByPOS(blocks)
+			} else {
+				segments[j] = formatNumbered(blocks)
+			}
+		}
+		entries[i].Definition = strings.Join(segments, "\n"+mergeSeparator+"\n")
+	}
+	return entries
+}
+
+// splitSenseBlocks は定義を語義ごとのまとまりに分割する
+// 用例(■)と補足説明(◆)の行は、直前の定義行と同じまとまりに含める
+func splitSenseBlocks(def string) []senseBlock {
+	var blocks []senseBlock
+	for _, line := range splitParagraphs(def) {
+		isAttached := strings.HasPrefix(line, "■") || strings.HasPrefix(line, "◆")
+		if isAttached && len(blocks) > 0 {
+			blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+			continue
+		}
+		blocks = append(blocks, senseBlock{lines: []string{line}})
+	}
+	return blocks
+}
+
+// formatNumbered は語義に "1. " から始まる番号を付ける (語義が一つの場合は付けない)
+func formatNumbered(blocks []senseBlock) string {
+	var lines []string
+	for i, block := range blocks {
+		for j, line := range block.lines {
+			if j == 0 && len(blocks) > 1 {
+				line = strconv.Itoa(i+1) + ". " + line
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatByPOS は語義を品詞ごとにまとめ、品詞の見出し行の下に番号付きで並べる
+// 品詞は最初に現れた順に並べ、品詞情報のない語義は最後にまとめる
+func formatByPOS(blocks []senseBlock) string {
+	order, groups := groupByPOS(blocks)
+
+	var lines []string
+	for _, pos := range order {
+		if pos != "" {
+			lines = append(lines, pos)
+		}
+		lines = append(lines, formatNumbered(groups[pos]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// groupByPOS は語義を品詞情報 (例: "{名}") ごとに分け、定義行の先頭の品詞情報を取り除く
+// 品詞は最初に現れた順に並べ、品詞情報のない語義は最後 (キーは空文字列) にまとめる
+func groupByPOS(blocks []senseBlock) (order []string, groups map[string][]senseBlock) {
+	groups = make(map[string][]senseBlock)
+	for _, block := range blocks {
+		pos := ""
+		if m := rePOSPrefix.FindStringSubmatch(block.lines[0]); m != nil {
+			pos = m[1]
+			block.lines = append([]string{strings.TrimPrefix(block.lines[0], m[0])}, block.lines[1:]...)
+		}
+		if _, exists := groups[pos]; !exists && pos != "" {
+			order = append(order, pos)
+		}
+		groups[pos] = append(groups[pos], block)
+	}
+	if _, exists := groups[""]; exists {
+		order = append(order, "")
+	}
+	return order, groups
+}
+
+// separateSenses は見出し語自身の語義と、リンク先(原形)の定義を、それぞれ同じ見出し語の別エントリに分ける
+// キーワードや音声、構造化した情報は最初のエントリにのみ残す
+func separateSenses(entries []DictionaryEntry) []DictionaryEntry {
+	separated := make([]DictionaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		segments := strings.Split(entry.Definition, "\n"+mergeSeparator+"\n")
+		var defs []string
+		for _, block := range splitSenseBlocks(segments[0]) {
+			defs = append(defs, strings.Join(block.lines, "\n"))
+		}
+		defs = append(defs, segments[1:]...)
+
+		for i, def := range defs {
+			newEntry := DictionaryEntry{Headword: entry.Headword, Definition: def}
+			if i == 0 {
+				// 最初のエントリには、キーワードや構造化した情報をそのまま引き継ぐ
+				newEntry = entry
+				newEntry.Definition = def
+			}
+			separated = append(separated, newEntry)
+		}
+		if len(defs) == 0 {
+			separated = append(separated, entry)
+		}
+	}
+	return separated
+}
+
+// splitByPOS は見出し語自身の語義を品詞ごとに別のエントリに分け、リンク先(原形)の定義もそれぞれ別のエントリにする
+// 各エントリの定義は品詞の見出し行と番号付きの語義からなり、構造化した語義もその品詞のもののみを残す
+// キーワードや音声などの見出し語全体の情報は最初のエントリにのみ残す
+func splitByPOS(entries []DictionaryEntry) []DictionaryEntry {
+	split := make([]DictionaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		segments := strings.Split(entry.Definition, "\n"+mergeSeparator+"\n")
+		order, groups := groupByPOS(splitSenseBlocks(segments[0]))
+
+		first := len(split)
+		for _, pos := range order {
+			def := formatNumbered(groups[pos])
+			newEntry := DictionaryEntry{Headword: entry.Headword, Definition: def}
+			if pos != "" {
+				newEntry.Definition = pos + "\n" + def
+			}
+			label := strings.TrimSuffix(strings.TrimPrefix(pos, "{"), "}")
+			for _, sense := range entry.Senses {
+				if sense.POS == label {
+					newEntry.Senses = append(newEntry.Senses, sense)
+				}
+			}
+			split = append(split, newEntry)
+		}
+		for _, linked := range segments[1:] {
+			split = append(split, DictionaryEntry{Headword: entry.Headword, Definition: linked})
+		}
+
+		if len(split) == first {
+			split = append(split, entry)
+			continue
+		}
+		// 最初のエントリには、見出し語全体の情報をそのまま引き継ぐ
+		head := entry
+		head.Definition = split[first].Definition
+		head.Senses = split[first].Senses
+		split[first] = head
+	}
+	return split
+}
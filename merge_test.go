@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestApplyMergeStrategy は同じ見出し語の定義のまとめ方を検証します。
+func TestApplyMergeStrategy(t *testing.T) {
+	def := "{名} 群れ\n■a drove of cattle\n{動} driveの過去形\n{名} 人の群れ\n◆複数形で\n---\n{動} 運転する"
+
+	testCases := []struct {
+		strategy string
+		expected []string
+	}{
+		{strategy: MergeConcat, expected: []string{def}},
+		{
+			strategy: MergeNumbered,
+			expected: []string{"1. {名} 群れ\n■a drove of cattle\n2. {動} driveの過去形\n3. {名} 人の群れ\n◆複数形で\n---\n{動} 運転する"},
+		},
+		{
+			strategy: MergePOS,
+			expected: []string{"{名}\n1. 群れ\n■a drove of cattle\n2. 人の群れ\n◆複数形で\n{動}\ndriveの過去形\n---\n{動}\n運転する"},
+		},
+		{
+			strategy: MergeSeparate,
+			expected: []string{"{名} 群れ\n■a drove of cattle", "{動} driveの過去形", "{名} 人の群れ\n◆複数形で", "{動} 運転する"},
+		},
+		{
+			strategy: MergeSplitPOS,
+			expected: []string{"{名}\n1. 群れ\n■a drove of cattle\n2. 人の群れ\n◆複数形で", "{動}\ndriveの過去形", "{動} 運転する"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.strategy, func(t *testing.T) {
+			entries := applyMergeStrategy([]DictionaryEntry{{Headword: "drove", Definition: def}}, tc.strategy)
+			var got []string
+			for _, entry := range entries {
+				if entry.Headword != "drove" {
+					t.Errorf("見出し語が変わっています: %s", entry.Headword)
+				}
+				got = append(got, entry.Definition)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q\n実際: %q", tc.expected, got)
+			}
+		})
+	}
+
+	if validateMergeStrategy("shuffle") == nil {
+		t.Errorf("未対応のまとめ方でエラーになりませんでした")
+	}
+}
+
+// TestSplitByPOSKeepsEntryInfo は品詞ごとに分けたエントリに見出し語全体の情報と品詞ごとの語義が残ることを検証します。
+func TestSplitByPOSKeepsEntryInfo(t *testing.T) {
+	entry := DictionaryEntry{
+		Headword:   "drive",
+		Definition: "{動} 運転する\n{名} ドライブ",
+		Keywords:   []string{"drives"},
+		Senses:     []Sense{{POS: "動", Gloss: "運転する"}, {POS: "名", Gloss: "ドライブ"}},
+	}
+
+	entries := splitByPOS([]DictionaryEntry{entry})
+	if len(entries) != 2 {
+		t.Fatalf("エントリ数が違います: %d", len(entries))
+	}
+	if !reflect.DeepEqual(entries[0].Keywords, []string{"drives"}) || entries[1].Keywords != nil {
+		t.Errorf("キーワードは最初のエントリにのみ残すはずです: %v, %v", entries[0].Keywords, entries[1].Keywords)
+	}
+	if len(entries[0].Senses) != 1 || entries[0].Senses[0].POS != "動" {
+		t.Errorf("最初のエントリの語義が違います: %+v", entries[0].Senses)
+	}
+	if len(entries[1].Senses) != 1 || entries[1].Senses[0].POS != "名" {
+		t.Errorf("2番目のエントリの語義が違います: %+v", entries[1].Senses)
+	}
+}
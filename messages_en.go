@@ -0,0 +1,475 @@
+package main
+
+// englishMessages は日本語の文言 (書式文字列) と、英語の訳の対応
+// 書式指定子 (%s, %d, %w など) は日本語の文言と同じ順に並べること (TestEnglishMessagesVerbs で確認する)
+var englishMessages = map[string]string{
+	// --- 変換のフラグ ---
+	"入力する英辞郎ファイル名 (例: EIJIRO-1448.TXT。.zip や .gz の圧縮ファイルも可)": "Eijiro file to convert (e.g. EIJIRO-1448.TXT; .zip and .gz archives are also accepted)",
+	"出力先ディレクトリ": "Output directory",
+	"辞書の名前":     "Dictionary name",
+	"エントリをJSONL形式で書き出すファイル名 (空の場合は出力しない)":                                                                                     "File to write entries to as JSONL (not written if empty)",
+	"JSONL出力の各レコードをスキーマ(schema/entry.schema.json)で検証する":                                                                       "Validate each JSONL record against the schema (schema/entry.schema.json)",
+	"発音音声ファイル(見出し語.mp3など)を格納したディレクトリ (-html 指定時に定義へ埋め込む)":                                                                     "Directory of pronunciation audio files (e.g. headword.mp3), embedded into definitions with -html",
+	"見出し語・カタカナ発音・IPAの対応表(TSV)を書き出すファイル名 (読み上げソフト向け)":                                                                          "File to write a headword / katakana pronunciation / IPA table (TSV) to, for text-to-speech software",
+	"見出し語と別名の一覧を書き出すファイル名 (入力メソッドや補完エンジン向け)":                                                                                  "File to write the list of headwords and aliases to, for input methods and completion engines",
+	"見出し語一覧の形式 (plain: 1行1語, mozc: Mozcのユーザー辞書形式)":                                                                            "Format of the headword list (plain: one word per line, mozc: Mozc user dictionary)",
+	"見出し語一覧(-export-keys, -derived-only)の並べ方 (byte: バイト列の順, japanese: 仮名の種類や大文字・小文字を区別しない五十音順)":                               "Ordering of headword lists (-export-keys, -derived-only) (byte: byte order, japanese: gojūon order ignoring kana type and letter case)",
+	"日本語の訳語から英語の見出し語を引く逆引き(和英)辞書を追加で生成する":                                                                                     "Also generate a reverse (Japanese-English) dictionary that looks up English headwords from Japanese translations",
+	"訳語が主にカタカナ語である見出し語を集め、カタカナの訳語から英語の見出し語を引くカタカナ語辞書(<辞書名>-katakana)を追加で生成する":                                                 "Also generate a katakana loanword dictionary (<book name>-katakana) that looks up English headwords whose glosses are mainly katakana by the katakana gloss",
+	"同じ見出し語の定義のまとめ方 (concat: 改行でつなぐ, numbered: 語義に番号を付ける, pos: 品詞ごとにまとめる, separate: 語義ごとに別エントリにする, split-pos: 品詞ごとに別エントリにする)": "How to merge definitions of the same headword (concat: join with newlines, numbered: number the senses, pos: group by part of speech, separate: one entry per sense, split-pos: one entry per part of speech)",
+	"短く一般的な訳語を前に、専門分野(《医》など)の語義を後ろに並べ替える":                                                                                     "Put short, common translations first and specialist senses (e.g. 《医》) last",
+	"語幹を共有する見出し語(happy, happiness, unhappilyなど)を関連語として各エントリに追記する":                                                             "Append headwords sharing a stem (happy, happiness, unhappily, ...) to each entry as related words",
+	"見出し語の大文字・小文字を元の表記のまま残す (小文字での検索は.synで引き続き可能)":                                                                            "Keep the original letter case of headwords (lowercase lookups still work through .syn)",
+	"書き出す前にエントリ(JSONL形式)を標準入力に渡して加工させる外部コマンド (標準出力の内容で置き換える)":                                                                 "External command that receives entries as JSONL on stdin before writing; entries are replaced by its stdout",
+	"変換の制限時間 (例: 30s)。時間内に読み込めた分だけで辞書を書き出して正常終了する (0の場合は無制限)":                                                                 "Time limit for the conversion (e.g. 30s); writes a dictionary from what was read in time and exits successfully (0 means no limit)",
+	"変換の各段階でエントリ数が想定外に増減した場合に、警告ではなくエラーにする":                                                                                   "Fail instead of warning when the entry count changes unexpectedly during a conversion phase",
+	"パース結果のキャッシュを書き出すファイル名 (-from-cache で再利用できる)":                                                                             "File to write the parse cache to (reusable with -from-cache)",
+	"英辞郎ファイルをパースせず、-cache で書き出したキャッシュから読み込む (パースオプションはキャッシュ作成時のものが使われる)":                                                      "Read entries from a cache written with -cache instead of parsing the Eijiro file (the parse options used when creating the cache apply)",
+	"パースと定義のまとめまでを行い、統計情報と書き出した場合のファイルの大きさを出力する (ファイルは一切書き出さない)":                                                              "Parse and merge only, then print statistics and the file sizes that would be written (writes no files)",
+	"索引ファイルをgzipで圧縮し、.idx.gz として書き出す (大きな辞書で容量を節約できる)":                                                                        "Compress the index with gzip and write it as .idx.gz (saves space for large dictionaries)",
+	"定義ファイルを圧縮せず、非圧縮の.dictとして書き出す (dictzipは不要になる)":                                                                            "Write definitions as an uncompressed .dict (dictzip is not required)",
+	"単語の頻度リスト(単語<TAB>順位)のファイル名。各エントリに順位を記録し、JSONLに出力する":                                                                       "Word frequency list (word<TAB>rank); records each entry's rank and writes it to JSONL",
+	"頻度リストの順位を定義の末尾に「【頻度】123位」として追記する (-frequency-list が必要)":                                                                  "Append the frequency rank to definitions as 「【頻度】123位」 (requires -frequency-list)",
+	"頻度リストの上位N位までの見出し語とその変化形のみを出力する (-frequency-list が必要。0の場合は絞り込まない)":                                                        "Only output the top N headwords of the frequency list and their inflections (requires -frequency-list; 0 disables)",
+	"Tatoebaの英日の対訳文(TSV)のファイル名。用例のないエントリに、見出し語を含む対訳文を「■〔Tatoeba〕」を付けて用例として加える":                                                "Tatoeba English-Japanese sentence pairs (TSV); adds pairs containing the headword to entries without examples, marked 「■〔Tatoeba〕」",
+	"一つのエントリに加えるTatoebaの対訳文の最大数":                                                                                              "Maximum number of Tatoeba sentence pairs added to one entry",
+	"JMdictのXMLファイル(JMdict_e、.gzも可)。-reverse-index の逆引き辞書で、一致する訳語にJMdictの通し番号・読み・英訳を添える (-furigana の読み仮名にも使う)":                "JMdict XML file (JMdict_e, .gz allowed); annotates matching headwords of the -reverse-index dictionary with the JMdict sequence number, readings and glosses (also used for -furigana readings)",
+	"定義文を含まない派生データ(見出し語一覧・変化形の参照関係・統計情報)のみを出力する":                                                                              "Only output derived data without definition text (headword list, inflection references, statistics)",
+	"詳しいログ(見つからないリンク先の一覧など)を表示する":                                                                                             "Show detailed logs (e.g. each unresolved link target)",
+	"-v に加えて、除外・無視した行を一行ずつ表示する":                                                                                               "In addition to -v, show each skipped or ignored line",
+	"警告とエラーのみを表示する": "Only show warnings and errors",
+	"ログを1行1件のJSON(event, count などの属性付き)で出力する (ビルドの自動化向け)": "Write logs as one JSON object per line with attributes such as event and count (for automated builds)",
+	"変換の終了時に実行結果(JSON)をPOSTするWebhookのURL":                 "Webhook URL to POST the result (JSON) to when the conversion finishes",
+	"変換の終了時に実行結果をメールで送るSMTPサーバー (host:port)":              "SMTP server (host:port) to email the result through when the conversion finishes",
+	"SMTP認証のユーザー名 (パスワードは環境変数 EIJIRO_SMTP_PASSWORD で指定)":  "SMTP username (set the password in the EIJIRO_SMTP_PASSWORD environment variable)",
+	"通知メールの送信元アドレス":                                       "Sender address of notification emails",
+	"通知メールの宛先アドレス (カンマ区切りで複数指定可)":                         "Recipient addresses of notification emails (comma-separated)",
+	"表示する言語 (ja: 日本語, en: 英語。空の場合は環境変数 LANG などから判断する)":    "Display language (ja: Japanese, en: English; detected from LANG etc. if empty)",
+
+	// --- パースオプション・出力オプションのフラグ ---
+	"用例(■・)を除外する": "Remove examples (■・)",
+	"用例(■・)を本体から分離し、別の辞書(<辞書名>-examples)として出力する": "Move examples (■・) into a separate dictionary (<name>-examples)",
+	"補足説明(◆)を除外する":                                  "Remove supplementary notes (◆)",
+	"読み仮名({…})を削除する":                                "Remove readings ({…})",
+	"PDICリンク(<→…>)を削除する":                            "Remove PDIC links (<→…>)",
+	"発音記号(【発音】…)を削除する":                              "Remove pronunciations (【発音】…)",
+	"発音記号(【発音】…)を英辞郎の表記からIPAに変換する":                  "Convert pronunciations (【発音】…) from Eijiro notation to IPA",
+	"カタカナ発音(【＠】…)を削除する":                             "Remove katakana pronunciations (【＠】…)",
+	"変化形(【変化】…)を削除する":                               "Remove inflections (【変化】…)",
+	"単語レベル(【レベル】…)を削除する":                            "Remove word levels (【レベル】…)",
+	"分節(【分節】…)を削除する":                                "Remove syllabification (【分節】…)",
+	"品詞({名})やその他のラベル({大学入試})を削除する":                  "Remove parts of speech ({名}) and other labels ({大学入試})",
+	"見出語が単一の単語からなるもののみを対象とする":                       "Only include single-word headwords",
+	"削除したカタカナ発音(【＠】)や分節(【分節】)の内容を検索用キーワードとして残す":     "Keep removed katakana pronunciations (【＠】) and syllabification (【分節】) as search keywords",
+	"訳語中の言い換え(追い払う[追い出す]など)を展開し、語義の訳語一覧や逆引きの索引に加える": "Expand alternatives in translations (e.g. 追い払う[追い出す]) into the sense translations and the reverse index",
+	"見出し語の括弧([…]: 直前の語の言い換え, 〔…〕・(…): 省略できる語句)を展開し、括弧を外した表示用の見出し語と、言い換えや省略をした検索用キーワードにする": "Expand brackets in headwords ([…]: alternative for the preceding word, 〔…〕/(…): optional words) into a display headword without brackets and search keywords for each variant",
+	"成句の見出し語(account for ~ など)から~を除いた形(account for)を検索用キーワードに加える":                         "Add phrase headwords without the ~ placeholder (account for ~ → account for) as search keywords",
+	"【変化】のない名詞・動詞・形容詞について、規則変化(-s/-es, -ed, -ing, -er/-est)の変化形から原形へのリンクを生成する":            "Generate links from regular inflections (-s/-es, -ed, -ing, -er/-est) to the base form for nouns, verbs and adjectives without 【変化】",
+	"定義中の【略】に続く略語(AIDSなど)から、元の見出し語へのリンクを生成する":                                             "Generate links from abbreviations after 【略】 in definitions (e.g. AIDS) to the full-form headword",
+	"一行に番号付きで並べた語義(1. 取る、2. 持って行く)を、構造化データ(JSONLの senses など)の別々の語義に分ける":                   "Split numbered senses on one line (1. 取る、2. 持って行く) into separate senses in the structured data (e.g. senses in JSONL)",
+	"定義全体が別の見出し語の参照(＝<→color>など)である見出し語に、参照先の定義を加える":                                      "Add the target definition to headwords whose whole definition is a reference to another headword (e.g. ＝<→color>)",
+	"指定した分野・用法のラベル(《医》《法》など)のいずれかを持つ定義行のみを対象とする (カンマ区切り。例: 医,薬)":                          "Only include definition lines with one of the given domain/register labels (e.g. 《医》《法》) (comma-separated, e.g. 医,薬)",
+	"指定した分野・用法のラベル(《俗》《卑》など)のいずれかを持つ定義行を除外する (カンマ区切り。例: 俗,卑)":                             "Exclude definition lines with one of the given domain/register labels (e.g. 《俗》《卑》) (comma-separated, e.g. 俗,卑)",
+	"見出し語・変化形・リンク先・定義の文字列をUnicode正規化する (nfc または nfkc。空の場合は正規化しない)":                        "Unicode-normalize headwords, inflections, link targets and definitions (nfc or nfkc; not normalized if empty)",
+	"見出し語・変化形・リンク先・定義の全角英数字・記号を半角に変換する":                                                   "Convert full-width ASCII letters, digits and symbols in headwords, inflections, link targets and definitions to half-width",
+	"すべての追加情報を除外し、最小限の定義のみを対象とする":                                                         "Remove all additional information and keep only minimal definitions",
+	"定義をHTML形式で書き出す":                                                       "Write definitions as HTML",
+	"HTML形式で添えるスタイルシートのテーマ (light または dark)":                               "Theme of the stylesheet shipped with HTML output (light or dark)",
+	"スタイルシートのアクセントカラー (#rrggbb の形式。空の場合はテーマの既定値)":                          "Accent color of the stylesheet (#rrggbb; the theme default if empty)",
+	"HTML形式での段落の区切り方 (br: <br>で区切る, p: <p>で囲む)":                            "How to separate paragraphs in HTML (br: separate with <br>, p: wrap in <p>)",
+	"HTML形式で、見出し語の分節(【分節】)を tac·ti·cal の形で定義の前に表示する":                       "In HTML, show the headword's syllabification (【分節】) as tac·ti·cal before the definition",
+	"HTML形式で、発音情報をスクリーンリーダーが読み上げられる要素として定義の前に置く":                           "In HTML, put pronunciations before the definition as elements screen readers can read",
+	"発音記号を定義本体とは別の項目(sametypesequenceの't')として書き出し、辞書アプリが発音記号を別に装飾できるようにする": "Write pronunciations as a separate field (sametypesequence 't') so dictionary apps can style them separately",
+
+	// --- サブコマンドのフラグと使い方 ---
+	"使い方: eijiro-converter bench [オプション]":                         "Usage: eijiro-converter bench [options]",
+	"使い方: eijiro-converter browse [オプション]":                        "Usage: eijiro-converter browse [options]",
+	"使い方: eijiro-converter lint [オプション]":                          "Usage: eijiro-converter lint [options]",
+	"使い方: eijiro-converter lookup [オプション] <見出し語>":                 "Usage: eijiro-converter lookup [options] <headword>",
+	"使い方: eijiro-converter serve [-dict] [-http] [オプション]":         "Usage: eijiro-converter serve [-dict] [-http] [options]",
+	"使い方: eijiro-converter stats [オプション]":                         "Usage: eijiro-converter stats [options]",
+	"使い方: eijiro-converter validate <辞書名.ifo>...":                 "Usage: eijiro-converter validate <name.ifo>...",
+	"CPUプロファイルを書き出すファイル名 (go tool pprof で解析できる)":                  "File to write a CPU profile to (analyze with go tool pprof)",
+	"終了時のメモリプロファイルを書き出すファイル名 (go tool pprof で解析できる)":              "File to write a memory profile to on exit (analyze with go tool pprof)",
+	"計測に使う英辞郎ファイル名":                                               "Eijiro file to benchmark with",
+	"同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)": "How to merge definitions of the same headword (concat, numbered, pos, separate, split-pos)",
+	"計測の回数 (段階ごとに最も短い所要時間を出力する)":                                  "Number of runs (the shortest time of each phase is reported)",
+	"出力形式 (table: 表, json: JSON)":                                 "Output format (table or json)",
+	"閲覧する英辞郎ファイル名":                                                "Eijiro file to browse",
+	"検査する英辞郎ファイル名":                                                "Eijiro file to lint",
+	"検索する英辞郎ファイル名":                                                "Eijiro file to search",
+	"検索する書き出し済みの辞書(.ifo) (指定した場合は -i より優先する)":                     "Written dictionary (.ifo) to search (takes precedence over -i)",
+	"見出し語を前方一致で検索する":                                              "Search headwords by prefix",
+	"前方一致検索で表示する最大件数 (0の場合は無制限)":                                  "Maximum number of prefix matches to show (0 means no limit)",
+	"提供する英辞郎ファイル名":                                                "Eijiro file to serve",
+	"DICTプロトコル (RFC 2229) のサーバーを起動する":                             "Start a DICT protocol (RFC 2229) server",
+	"DICTプロトコルのサーバーが待ち受けるアドレス":                                    "Listen address of the DICT server",
+	"JSONでエントリを返すHTTPサーバーを起動する":                                   "Start an HTTP server that returns entries as JSON",
+	"HTTPサーバーが待ち受けるアドレス":                                          "Listen address of the HTTP server",
+	"集計する英辞郎ファイル名":                                                "Eijiro file to summarize",
+
+	// --- 進捗と警告 ---
+	"エラー: ":         "Error: ",
+	"警告: ":          "Warning: ",
+	"変換処理を開始します...": "Starting conversion...",
+	"Tatoebaの対訳文を%d件読み込みました。":    "Loaded %d Tatoeba sentence pairs.",
+	"JMdictから%d件の表記・読みを読み込みました。": "Loaded %d spellings and readings from JMdict.",
+	"パースオプションの指定はキャッシュの作成時と異なりますが、キャッシュ作成時のオプションでパースした結果を使います。":       "The parse options differ from those used to create the cache; the cached result parsed with the cache's options is used.",
+	"パース結果をキャッシュから読み込みました: %s (入力: %s)":                               "Loaded parse results from cache: %s (input: %s)",
+	"読み込みを途中で打ち切ったため、パース結果のキャッシュは書き出しません。":                            "Reading was cut short, so the parse cache is not written.",
+	"パース結果のキャッシュを書き出しました: %s":                                         "Wrote parse cache: %s",
+	"%d件のエントリを読み込みました。":                                               "Read %d entries.",
+	"頻度リストの上位%d位までの見出し語に絞り込み、%d件のエントリが残りました。":                         "Narrowed down to the top %d headwords of the frequency list; %d entries remain.",
+	"辞書バージョンを '%s' に設定します。":                                           "Setting the dictionary version to '%s'.",
+	"派生データのみを書き出しました。出力先: %s":                                         "Wrote derived data only. Output: %s",
+	"%d件の見出し語から用例を分離しました。":                                            "Moved examples out of %d headwords.",
+	"既出の見出し語と重複する(大文字・小文字のみ異なるものを含む)%d件の定義は、最初の見出し語の定義のみが使われます。":      "%d definitions duplicate an earlier headword (including ones differing only in case); only the first headword's definition is used.",
+	"用例のない%d件の見出し語に、Tatoebaの対訳文を用例として加えました。":                          "Added Tatoeba sentence pairs as examples to %d headwords without examples.",
+	"%d件の見出し語に頻度リストの順位を記録しました。":                                       "Recorded frequency ranks for %d headwords.",
+	"加工後のエントリは%d件です。":                                                 "%d entries after transformation.",
+	"発音音声へのリンクはStarDict形式で -html 指定時のみ定義に埋め込まれるため、-audio-dir を無視します。": "Audio links are only embedded in StarDict output with -html; ignoring -audio-dir.",
+	"%d件の見出し語に発音音声を対応付けました。":                                          "Matched pronunciation audio to %d headwords.",
+	"エントリ数の推移: %s":                                               "Entry counts: %s",
+	"JSONLファイルを書き出しました: %s":                                      "Wrote JSONL file: %s",
+	"見出し語一覧を書き出しました: %s":                                         "Wrote headword list: %s",
+	"発音の対応表を書き出しました: %s":                                         "Wrote pronunciation table: %s",
+	"逆引き辞書のエントリを%d件生成しました。":                                      "Generated %d reverse dictionary entries.",
+	"カタカナ語辞書のエントリを%d件生成しました。":                                    "Generated %d katakana dictionary entries.",
+	"逆引き辞書の%d件の見出し語にJMdictの情報を添えました。":                            "Added JMdict information to %d reverse dictionary headwords.",
+	"処理が完了しました。出力先: %s":                                          "Done. Output: %s",
+	"変化形の参照を解決しています...":                                          "Resolving inflection references...",
+	"リンク先が見つからない参照が%d件ありました。(例: %s)":                             "%d references point to missing link targets. (e.g. %s)",
+	"リンク先が見つかりません: %s → %s":                                      "Link target not found: %s → %s",
+	"時間制限に達したため、'%s' の手前で読み込みを打ち切りました。":                          "Time limit reached; stopped reading before '%s'.",
+	"%d行目: 複数の単語からなる見出し語 '%s' を除外しました。":                          "Line %d: skipped multi-word headword '%s'.",
+	"%d行目: 分野・用法のラベル (%s) または品詞 (%s) により '%s' の定義行を除外しました。":      "line %d: excluded a definition line of '%[4]s' by domain/register labels (%[2]s) or part of speech (%[3]s).",
+	"%d行目: どの見出し語にも属さない行を無視しました: %s":                             "Line %d: ignored a line that belongs to no headword: %s",
+	"%d行を読み込み、見出し語%d件と変化形のリンク%d件を生成しました。":                        "Read %d lines and generated %d headwords and %d inflection links.",
+	"変化形のリンクのうち%d件は、規則変化から生成しました。":                               "%d of the inflection links were generated from regular inflections.",
+	"定義が別の見出し語の参照(＝<→…>)である%d行に、参照先へのリンクを加えました。":                 "Added links to the target to %d lines whose definition is a reference to another headword (＝<→…>).",
+	"変化形のリンクのうち%d件は、【略】の略語から生成しました。":                             "%d of the inflection links were generated from 【略】 abbreviations.",
+	"オプションの指定により%d行を除外しました。":                                     "Skipped %d lines as requested by options.",
+	"どの見出し語にも属さない%d行を無視しました。":                                    "Ignored %d lines that belong to no headword.",
+	"dictzipが見つからないため、gzipで圧縮します。辞書アプリによっては定義の読み込みが遅くなる場合があります。": "dictzip not found; compressing with gzip. Some dictionary apps may load definitions more slowly.",
+	"試行のため、ファイルは書き出していません。":                                      "Dry run; no files were written.",
+	"完了通知の送信に失敗しました: %v":                                         "Failed to send the completion notification: %v",
+	"%s を読み込んでいます...":                                            "Reading %s...",
+	"DICTサーバーを %s で起動しました。":                                      "DICT server started on %s.",
+	"HTTPサーバーを %s で起動しました。":                                      "HTTP server started on %s.",
+	"%s: 問題は見つかりませんでした (%d語)":                                    "%s: no problems found (%d words)",
+	"%s: ほか%d件の問題があります":                                          "%s: %d more problems",
+
+	// --- 変換の段階と表の見出し ---
+	"読み込み":         "read",
+	"頻度による絞り込み":    "frequency filter",
+	"参照の解決":        "link resolution",
+	"定義のまとめ":       "merge",
+	"加工":           "transform",
+	"書き出し":         "write",
+	"文字コード変換":      "decode",
+	"パース":          "parse",
+	"参照の解決と定義のまとめ": "resolve and merge",
+	"描画":           "render",
+	"圧縮":           "compress",
+	"%d件":          "%d entries",
+	"合計":           "total",
+	"辞書\t.idx\t.dict\t.dict.dz (見積もり)\t.syn\t": "Dictionary\t.idx\t.dict\t.dict.dz (estimate)\t.syn\t",
+	"入力ファイル":        "Input file",
+	"読み込んだ行数":       "Lines read",
+	"エントリ数 (パース直後)": "Entries (parsed)",
+	"見出し語":          "Headwords",
+	"除外した行":         "Skipped lines",
+	"無視した行":         "Ignored lines",
+	"重複した見出し語":      "Duplicate headwords",
+	"エントリ数 (最終)":    "Entries (final)",
+	"語義":            "Senses",
+	"用例":            "Examples",
+	"補足説明":          "Supplementary notes",
+	"定義の平均文字数":      "Average definition length",
+	"変化形のリンク":       "Inflection links",
+	"リンクの参照":        "Link references",
+	"未解決のリンク":       "Unresolved links",
+	"品詞":            "POS",
+	"レベル":           "Level",
+
+	// --- 変換のエラー ---
+	"-dry-run と -derived-only は同時に指定できません":                                       "-dry-run and -derived-only cannot be used together",
+	"頻度リストの読み込みに失敗しました: %w":                                                      "failed to read the frequency list: %w",
+	"-top-n と -show-frequency には -frequency-list の指定が必要です":                       "-top-n and -show-frequency require -frequency-list",
+	"-tatoeba-max には1以上の値を指定してください: %d":                                          "-tatoeba-max must be at least 1: %d",
+	"Tatoebaの対訳文の読み込みに失敗しました: %w":                                                "failed to read Tatoeba sentence pairs: %w",
+	"-jmdict は -reverse-index または -furigana と同時に指定してください":                        "-jmdict must be used with -reverse-index or -furigana",
+	"JMdictの読み込みに失敗しました: %w":                                                     "failed to read JMdict: %w",
+	"出力ディレクトリの作成に失敗しました: %w":                                                     "failed to create the output directory: %w",
+	"パース結果のキャッシュの読み込みに失敗しました: %w":                                                "failed to read the parse cache: %w",
+	"英辞郎ファイルのパースに失敗しました: %w":                                                     "failed to parse the Eijiro file: %w",
+	"パース結果のキャッシュの書き込みに失敗しました: %w":                                                "failed to write the parse cache: %w",
+	"派生データの書き込みに失敗しました: %w":                                                      "failed to write derived data: %w",
+	"エントリの加工に失敗しました: %w":                                                         "failed to transform entries: %w",
+	"発音音声ファイルの配置に失敗しました: %w":                                                     "failed to place pronunciation audio files: %w",
+	"StarDictファイルの書き込みに失敗しました: %w":                                               "failed to write StarDict files: %w",
+	"用例辞書の書き込みに失敗しました: %w":                                                       "failed to write the examples dictionary: %w",
+	"JSONLファイルの書き込みに失敗しました: %w":                                                  "failed to write the JSONL file: %w",
+	"見出し語一覧の書き込みに失敗しました: %w":                                                     "failed to write the headword list: %w",
+	"発音の対応表の書き込みに失敗しました: %w":                                                     "failed to write the pronunciation table: %w",
+	"逆引き辞書の書き込みに失敗しました: %w":                                                      "failed to write the reverse dictionary: %w",
+	"カタカナ語辞書の書き込みに失敗しました: %w":                                                    "failed to write the katakana dictionary: %w",
+	"%sの後のエントリ数が想定と異なります (想定: %d件, 実際: %d件)":                                     "unexpected entry count after %s (expected: %d, actual: %d)",
+	"読み込んだ行数と行の内訳が一致しません (行数: %d行, 内訳の合計: %d行)":                                  "the number of lines read does not match the line breakdown (lines: %d, breakdown total: %d)",
+	"書き出した辞書の見出し語を数えられません: %w":                                                   "cannot count the headwords of the written dictionary: %w",
+	"変換を中断しました: %w":                                                              "conversion interrupted: %w",
+	"-quiet と -v (-vv) は同時に指定できません":                                              "-quiet and -v (-vv) cannot be used together",
+	"未対応の言語です: %s (%s または %s を指定してください)":                                         "unsupported language: %s (use %s or %s)",
+	"未対応の出力形式です: %s (table または json を指定してください)":                                  "unsupported output format: %s (use table or json)",
+	"未対応の並べ方です: %s (%s または %s を指定してください)":                                        "unsupported collation: %s (use %s or %s)",
+	"未対応のまとめ方です: %s (concat, numbered, pos, separate, split-pos のいずれかを指定してください)": "unsupported merge strategy: %s (use concat, numbered, pos, separate or split-pos)",
+	"未対応の正規化形式です: %s (%s または %s を指定してください)":                                      "unsupported normalization form: %s (use %s or %s)",
+	"未対応の段落の形式です: %s":                                                            "unsupported paragraph style: %s",
+	"未対応のテーマです: %s (light または dark を指定してください)":                                   "unsupported theme: %s (use light or dark)",
+	"アクセントカラーの指定が不正です: %s (#rrggbb の形式で指定してください)":                                "invalid accent color: %s (use the #rrggbb format)",
+	"未対応のキー出力形式です: %s":                                                           "unsupported keys format: %s",
+	"-count には1以上を指定してください: %d":                                                  "-count must be at least 1: %d",
+
+	// --- ファイルの読み書きのエラー ---
+	"音声ディレクトリの読み込みに失敗: %w":      "failed to read the audio directory: %w",
+	"'%s' のコピーに失敗: %w":          "failed to copy '%s': %w",
+	"CPUプロファイルの作成に失敗しました: %w":   "failed to create the CPU profile: %w",
+	"CPUプロファイルの取得を開始できません: %w":  "cannot start CPU profiling: %w",
+	"CPUプロファイルの書き込みに失敗しました: %w": "failed to write the CPU profile: %w",
+	"メモリプロファイルの作成に失敗しました: %w":   "failed to create the memory profile: %w",
+	"メモリプロファイルの書き込みに失敗しました: %w": "failed to write the memory profile: %w",
+	"キャッシュの形式が不正です: %w":         "invalid cache format: %w",
+	"キャッシュの形式のバージョン (%d) が現在のバージョン (%d) と異なります。キャッシュを作り直してください": "cache format version (%d) differs from the current version (%d); recreate the cache",
+	".dict ファイルの書き込みに失敗: %w":       "failed to write the .dict file: %w",
+	".dict.dz ファイルの書き込みに失敗: %w":    "failed to write the .dict.dz file: %w",
+	"dictzipの実行に失敗: %w\n%s":        "dictzip failed: %w\n%s",
+	"以前の %s ファイルの削除に失敗: %w":        "failed to remove the previous %s file: %w",
+	".idx ファイルの書き込みに失敗: %w":        "failed to write the .idx file: %w",
+	"見出し語一覧の書き込みに失敗: %w":           "failed to write the headword list: %w",
+	"変化形の参照関係の書き込みに失敗: %w":         "failed to write inflection references: %w",
+	"統計情報の書き込みに失敗: %w":             "failed to write statistics: %w",
+	"辞書 '%s' の大きさを求められませんでした: %w":  "could not compute the size of dictionary '%s': %w",
+	"スタイルシートの書き込みに失敗: %w":          "failed to write the stylesheet: %w",
+	".syn ファイルの書き込みに失敗: %w":        "failed to write the .syn file: %w",
+	"見出し語が空のエントリがあります (定義: %.40q)": "an entry has an empty headword (definition: %.40q)",
+	"'%s' の定義が大きすぎます (%d バイト)":     "the definition of '%s' is too large (%d bytes)",
+	"%s:%d: 頻度リストの行は \"単語<TAB>順位\" の形式で、順位は1以上の整数で指定してください: %q":                    "%s:%d: frequency list lines must be \"word<TAB>rank\" with a rank of at least 1: %q",
+	"%s:%d: 対訳文の行は \"英文ID<TAB>英文<TAB>和文ID<TAB>和文\" または \"英文<TAB>和文\" の形式で指定してください": "%s:%d: sentence pair lines must be \"eng_id<TAB>english<TAB>jpn_id<TAB>japanese\" or \"english<TAB>japanese\"",
+	"gzipの展開に失敗: %w":           "failed to decompress gzip: %w",
+	"JMdictのXMLの解析に失敗: %w":     "failed to parse JMdict XML: %w",
+	"JMdictのエントリの解析に失敗: %w":    "failed to parse a JMdict entry: %w",
+	"一時ディレクトリの作成に失敗しました: %w":   "failed to create a temporary directory: %w",
+	"%s の書き出しの確定に失敗しました: %w":   "failed to finalize %s: %w",
+	"出力先への書き出しの確定に失敗しました: %w":  "failed to finalize the output directory: %w",
+	"以前の %s の削除に失敗しました: %w":    "failed to remove the previous %s: %w",
+	"フィルターコマンドを起動できません: %w":    "cannot start the filter command: %w",
+	"フィルターコマンドが失敗しました: %w":     "the filter command failed: %w",
+	"フィルターコマンドの出力を読み込めません: %w": "cannot read the filter command's output: %w",
+	"%d件目: %w": "entry %d: %w",
+	"%d件目: 見出し語がありません": "entry %d: missing headword",
+
+	// --- JSONLのスキーマ検証 ---
+	"スキーマの読み込みに失敗: %w":         "failed to load the schema: %w",
+	"'%s' のJSON変換に失敗: %w":      "failed to convert '%s' to JSON: %w",
+	"'%s' がスキーマに適合しません: %w":    "'%s' does not match the schema: %w",
+	"%s: objectである必要があります":     "%s: must be an object",
+	"%s: 必須フィールド '%s' がありません":  "%s: missing required field '%s'",
+	"%s: 未定義のフィールド '%s' があります": "%s: unknown field '%s'",
+	"%s: arrayである必要があります":      "%s: must be an array",
+	"%s: stringである必要があります":     "%s: must be a string",
+	"%s: %d文字以上である必要があります":     "%s: must be at least %d characters",
+	"%s: 不正なUTF-8文字列が含まれています":  "%s: contains invalid UTF-8",
+	"%s: integerである必要があります":    "%s: must be an integer",
+	"%s: numberである必要があります":     "%s: must be a number",
+	"%s: booleanである必要があります":    "%s: must be a boolean",
+
+	// --- サブコマンドのエラーと検査結果 ---
+	"端末の設定を取得できません (stty が利用できる端末で実行してください): %w":     "cannot read terminal settings (run in a terminal where stty is available): %w",
+	"端末の設定を変更できません: %w":                              "cannot change terminal settings: %w",
+	"'%s' の定義が加工後に空になります":                            "the definition of '%s' becomes empty after processing",
+	"'%s' のリンク先 '%s' が見つかりません":                       "link target '%[2]s' of '%[1]s' not found",
+	"どの形式にも当てはまらないため無視されます: ":                        "ignored because it matches no known format: ",
+	"Shift_JISとして解釈できないバイト列を含みます":                    "contains bytes that are not valid Shift_JIS",
+	"制御文字 (U+%04X) を含みます":                            "contains a control character (U+%04X)",
+	"文字化け (UTF-8のテキストをShift_JISとして解釈したもの) の可能性があります": "possible mojibake (UTF-8 text decoded as Shift_JIS)",
+	"%d件の問題が見つかりました":                                 "found %d problems",
+	"検索する見出し語を一つ指定してください":                            "specify exactly one headword to look up",
+	"'%s' は見つかりませんでした":                               "'%s' not found",
+	"メール: %w":                                        "email: %w",
+	"予期しないステータス: %s":                                 "unexpected status: %s",
+	"送信元(-notify-from)と宛先(-notify-to)の指定が必要です":       "a sender (-notify-from) and recipients (-notify-to) are required",
+	"SMTPサーバーの指定が不正です: %w":                           "invalid SMTP server: %w",
+	"起動するサーバーを指定してください (-dict または -http)":            "specify a server to start (-dict or -http)",
+	"DICTサーバーを起動できません: %w":                           "cannot start the DICT server: %w",
+	"HTTPサーバーを起動できません: %w":                           "cannot start the HTTP server: %w",
+	"検証する .ifo ファイルを指定してください":                        "specify the .ifo files to validate",
+	"問題のある辞書があります: %s":                               "some dictionaries have problems: %s",
+
+	// --- StarDictの読み込みと検証 ---
+	".ifo ファイルの読み込みに失敗: %w":                                                   "failed to read the .ifo file: %w",
+	".idx ファイルの解析に失敗: %w":                                                     "failed to parse the .idx file: %w",
+	".syn ファイルの解析に失敗: %w":                                                     "failed to parse the .syn file: %w",
+	".syn ファイルの読み込みに失敗: %w":                                                   "failed to read the .syn file: %w",
+	"先頭行が '%s' ではありません":                                                       "the first line is not '%s'",
+	"%d件目のエントリが途中で終わっています":                                                    "entry %d is truncated",
+	".dict.dz または .dict ファイルの読み込みに失敗: %w":                                     "failed to read the .dict.dz or .dict file: %w",
+	".dict.dz ファイルの読み込みに失敗: %w":                                               "failed to read the .dict.dz file: %w",
+	".dict.dz ファイルの展開に失敗: %w":                                                 "failed to decompress the .dict.dz file: %w",
+	".idx ファイルの読み込みに失敗: %w":                                                   "failed to read the .idx file: %w",
+	".idx.gz ファイルの読み込みに失敗: %w":                                                "failed to read the .idx.gz file: %w",
+	".idx.gz ファイルの展開に失敗: %w":                                                  "failed to decompress the .idx.gz file: %w",
+	"%s の値 '%s' が数値ではありません":                                                   "the value '%[2]s' of %[1]s is not a number",
+	"'%s' の定義の位置 (%d+%d) が .dict の大きさ (%d) を超えています":                           "the definition of '%s' at %d+%d exceeds the .dict size (%d)",
+	"sametypesequence の項目 '%c' には対応していません":                                    "unsupported sametypesequence field '%c'",
+	"'%s' の定義に sametypesequence (%s) の %d 番目の項目の終端がありません":                     "the definition of '%s' lacks the end of field %[3]d of sametypesequence (%[2]s)",
+	".ifo に必須の項目 '%s' がありません":                                                 ".ifo lacks the required key '%s'",
+	".ifo の wordcount (%d) が .idx の見出し語の数 (%d) と一致しません":                       ".ifo wordcount (%d) does not match the number of .idx headwords (%d)",
+	".ifo の idxfilesize (%d) が .idx の大きさ (%d) と一致しません":                        ".ifo idxfilesize (%d) does not match the .idx size (%d)",
+	".ifo の idxoffsetbits (%d) は 32 または 64 である必要があります":                        ".ifo idxoffsetbits (%d) must be 32 or 64",
+	".dict の大きさ (%d) が32ビットの位置で表せる範囲を超えています (.ifo に idxoffsetbits=64 がありません)": "the .dict size (%d) exceeds what 32-bit offsets can address (.ifo lacks idxoffsetbits=64)",
+	".ifo の synwordcount (%d) が .syn の同義語の数 (%d) と一致しません":                     ".ifo synwordcount (%d) does not match the number of .syn synonyms (%d)",
+	".idx の%d件目: 見出し語が空です":                                                    ".idx entry %d: empty headword",
+	".idx の%d件目: 見出し語 '%s' が%dバイトを超えています":                                     ".idx entry %d: headword '%s' exceeds %d bytes",
+	".idx の%d件目: 見出し語 %q が正しいUTF-8ではありません":                                    ".idx entry %d: headword %q is not valid UTF-8",
+	".idx の%d件目: '%s' が直前の '%s' より前に並ぶべき位置にあります":                              ".idx entry %d: '%s' should sort before the preceding '%s'",
+	".idx の%d件目: %v": ".idx entry %d: %v",
+	".idx の%d件目: '%s' の定義が正しいUTF-8ではありません":            ".idx entry %d: the definition of '%s' is not valid UTF-8",
+	".syn の%d件目: '%s' の参照先 (%d) が見出し語の数 (%d) を超えています": ".syn entry %d: target (%[3]d) of '%[2]s' exceeds the number of headwords (%[4]d)",
+	".syn の%d件目: '%s' が直前の '%s' より前に並ぶべき位置にあります":      ".syn entry %d: '%s' should sort before the preceding '%s'",
+
+	// --- アーカイブ ---
+	"書き出した辞書を変換の設定を記したREADME.txtとともに一つのアーカイブ(stardict-<辞書名>-<バージョン>.zip など)にまとめる (zip または tar.bz2。空の場合はまとめない)": "Bundle the written dictionaries with a README.txt describing the build options into one archive (e.g. stardict-<name>-<version>.zip) (zip or tar.bz2; not bundled if empty)",
+	"未対応のアーカイブ形式です: %s (%s または %s を指定してください)":                                                                  "unsupported archive format: %s (use %s or %s)",
+	"-package は -dry-run や -derived-only と同時に指定できません":                                                          "-package cannot be used with -dry-run or -derived-only",
+	"アーカイブに収めるファイルの読み込みに失敗しました: %w":                                                                            "failed to read files for the archive: %w",
+	"アーカイブの書き込みに失敗しました: %w":                                                                                    "failed to write the archive: %w",
+	"bzip2が見つかりません。bzip2 をインストールするか、-package %s を指定してください":                                                     "bzip2 not found; install bzip2 or use -package %s",
+	"bzip2の実行に失敗: %w\n%s": "bzip2 failed: %w\n%s",
+	"辞書をアーカイブにまとめました: %s": "Bundled the dictionaries into an archive: %s",
+
+	// --- インストール ---
+	"書き出した辞書を辞書ディレクトリの<辞書名>/にインストールする (user: ~/.stardict/dic など, system: /usr/share/stardict/dic など。以前の版はバックアップする)": "Install the written dictionaries into <name>/ of the dictionary directory (user: ~/.stardict/dic etc., system: /usr/share/stardict/dic etc.; the previous version is backed up)",
+	"未対応のインストール先です: %s (%s または %s を指定してください)":                                                                       "unsupported install scope: %s (use %s or %s)",
+	"-install は -dry-run や -derived-only と同時に指定できません":                                                               "-install cannot be used with -dry-run or -derived-only",
+	"環境変数 %s が設定されていないため、インストール先を決められません":                                                                           "cannot determine the install directory because %s is not set",
+	"辞書のファイルのコピーに失敗しました: %w":                                                                                        "failed to copy the dictionary files: %w",
+	"以前の版を %s に退避しました。":                                                                                             "Moved the previous version to %s.",
+	"%s に書き込む権限がありません。管理者権限で実行するか、-install %s を指定してください: %w":                                                        "no permission to write to %s; run as an administrator or use -install %s: %w",
+	"%s に書き込む権限がありません: %w":                                                                                          "no permission to write to %s: %w",
+	"%s へのインストールに失敗しました: %w":                                                                                        "failed to install into %s: %w",
+	"辞書を %s にインストールしました。":                                                                                           "Installed the dictionaries into %s.",
+
+	// --- .ifo の辞書の情報 ---
+	"辞書の作者 (.ifo の author。空の場合は Converted with Go)":           "Dictionary author (.ifo author; Converted with Go if empty)",
+	"辞書の作者の連絡先のメールアドレス (.ifo の email)":                        "Contact email address of the author (.ifo email)",
+	"辞書のWebサイトのURL (.ifo の website)":                          "Website URL of the dictionary (.ifo website)",
+	"辞書の説明 (.ifo の description。改行は<br>として書き出す。空の場合は既定の英語の説明)": "Dictionary description (.ifo description; newlines are written as <br>; a default English description if empty)",
+
+	// --- 圧縮ファイルの入力 ---
+	"ZIPファイルを開けません: %w":                                "cannot open the ZIP file: %w",
+	"英辞郎のテキストファイル(.TXT)が含まれていません":                      "the archive contains no Eijiro text file (.TXT)",
+	"変換するファイルを決められません。展開してから目的のファイルを指定してください (候補: %s)": "cannot decide which file to convert; extract the archive and specify the file (candidates: %s)",
+
+	// --- 綴り誤りの検索キー ---
+	"英単語の見出し語に、よくある綴り誤りの形(recieve, accomodateなど)を検索キーとして加える (あいまい検索のない辞書アプリ向け)": "add common misspellings of English headwords (recieve, accomodate, etc.) as lookup keys (for dictionary apps without fuzzy search)",
+	"綴り誤りの検索キーを%d件加えました。": "Added %d misspelling lookup keys.",
+
+	// --- diff サブコマンド ---
+	"追加: %d件, 削除: %d件, 変更: %d件": "added: %d, removed: %d, changed: %d",
+	"%s のパースに失敗しました: %w":        "failed to parse %s: %w",
+	"使い方: eijiro-converter diff [オプション] 旧版の英辞郎ファイル 新版の英辞郎ファイル": "usage: eijiro-converter diff [options] OLD_EIJIRO_FILE NEW_EIJIRO_FILE",
+	"追加・変更された見出し語のみからなる更新分の辞書を書き出すディレクトリ (空の場合は書き出さない)":        "directory to write an updates-only dictionary of added and changed headwords (not written if empty)",
+	"更新分の辞書の名前":                      "name of the updates-only dictionary",
+	"比較する2つの英辞郎ファイルを指定してください":        "specify the two Eijiro files to compare",
+	"更新分の辞書の書き込みに失敗しました: %w":         "failed to write the updates-only dictionary: %w",
+	"%d件の見出し語からなる更新分の辞書を書き出しました: %s": "Wrote an updates-only dictionary of %d headwords: %s",
+
+	// --- 差分による再構築 ---
+	"-from-cache のキャッシュを作成した版から -i の版への英辞郎ファイルの差分(diff -u の形式)。変更された見出し語のみをパースし直す": "diff (diff -u format) of the Eijiro file from the release the -from-cache cache was built from to the -i release; only changed headwords are parsed again",
+	"-patch は -from-cache と同時に指定してください": "-patch requires -from-cache",
+	"差分の適用に失敗しました: %w":                  "failed to apply the diff: %w",
+	"差分で変更された%d件の見出し語を %s からパースし直しました。": "Parsed %d headwords changed by the diff again from %s.",
+	"差分の%d行目を解釈できません":                   "cannot interpret line %d of the diff",
+	"差分の%d行目の変更がどの見出し語に属するかを決められません。前後の行を多く含めて差分を作り直してください (例: diff -U 10)":                 "cannot determine which headword the change on line %d of the diff belongs to; recreate the diff with more context lines (e.g. diff -U 10)",
+	"差分には1つのファイルの変更のみを含めてください (%d個のファイルが含まれています)":                                           "the diff must contain changes to a single file (it contains %d files)",
+	"読み込みを途中で打ち切ったキャッシュには差分を適用できません":                                                        "cannot apply a diff to a cache whose parsing was cut short",
+	"-generate-inflections を指定して作成したキャッシュには差分を適用できません (生成する変化形が辞書全体に依存するため)":                "cannot apply a diff to a cache built with -generate-inflections (the generated forms depend on the whole dictionary)",
+	"-headword-range, -offset, -limit を指定して作成したキャッシュには差分を適用できません (対象の見出し語が辞書全体での位置に依存するため)": "a patch cannot be applied to a cache created with -headword-range, -offset or -limit (the selected headwords depend on their position in the whole dictionary)",
+
+	// --- gensample サブコマンド ---
+	"サンプルデータをShift_JISに変換できません: %w":           "cannot encode the sample data in Shift_JIS: %w",
+	"使い方: eijiro-converter gensample [オプション]": "usage: eijiro-converter gensample [options]",
+	"サンプルデータを書き出すファイル名 (- の場合は標準出力)":          "file to write the sample data to (- for standard output)",
+	"サンプルデータを書き出しました: %s":                     "Wrote the sample data: %s",
+
+	// --- 見出し語の範囲と件数 ---
+	"先頭の文字が範囲内にある見出し語のみを変換する (例: a-c, x)。テンプレートの調整や不具合の調査向け": "convert only headwords whose first letter is in the range (e.g. a-c, x); for tuning templates and debugging",
+	"先頭から指定した数の見出し語を読み飛ばす (-headword-range を指定した場合は範囲内で数える)": "skip the given number of headwords from the start (counted within the range when -headword-range is given)",
+	"指定した数の見出し語のみを変換し、残りは読み込まない (0の場合は無制限)":                  "convert only the given number of headwords and stop reading the rest (0 for no limit)",
+	"-offset と -limit には0以上の値を指定してください":                      "-offset and -limit must be 0 or greater",
+	"見出し語の範囲は a-c のように先頭の文字で指定してください: %s":                    "specify the headword range by first letters, such as a-c: %s",
+
+	// --- 読み仮名 ---
+	"定義の日本語の漢字に、JMdictで読みが一つに定まる語の読み仮名をルビとして振る (-html と -jmdict が必要)": "add furigana over kanji in the Japanese definitions for words whose reading JMdict determines uniquely (requires -html and -jmdict)",
+	"-furigana には -jmdict の指定が必要です":                   "-furigana requires -jmdict",
+	"読み仮名は -html 指定時のみルビとして表示されるため、-furigana を無視します。": "Furigana is shown as ruby only with -html; ignoring -furigana.",
+	"%d件の見出し語の定義に読み仮名を加えました。":                         "Added furigana to the definitions of %d headwords.",
+
+	// --- アクセント ---
+	"アクセントのデータ(表記<TAB>読み<TAB>アクセント核の位置のTSV。kanjiumのaccents.txtなど)。逆引き辞書とカタカナ語辞書の見出し語に「【アクセント】にほん［2］中高型」の行を添える": "pitch-accent data (TSV of surface<TAB>reading<TAB>accent position, such as kanjium's accents.txt); adds a line like \"【アクセント】にほん［2］中高型\" to headwords of the reverse and katakana dictionaries",
+	"-pitch-accent は -reverse-index または -katakana-dict と同時に指定してください":            "-pitch-accent must be used with -reverse-index or -katakana-dict",
+	"アクセントのデータの読み込みに失敗しました: %w":                                                 "failed to load the pitch-accent data: %w",
+	"%d件の表記・読みのアクセントを読み込みました。":                                                  "Loaded pitch accents for %d surfaces and readings.",
+	"逆引き辞書の%d件の見出し語にアクセントを添えました。":                                               "Added pitch accents to %d headwords of the reverse dictionary.",
+	"カタカナ語辞書の%d件の見出し語にアクセントを添えました。":                                             "Added pitch accents to %d headwords of the katakana dictionary.",
+	"%s:%d: アクセントの行は \"表記<TAB>読み<TAB>アクセント\" または \"読み<TAB>アクセント\" の形式で指定してください": "%s:%d: pitch-accent lines must be \"surface<TAB>reading<TAB>accent\" or \"reading<TAB>accent\"",
+	"%s:%d: アクセントは0以上の整数をカンマ区切りで指定してください: %q":                                   "%s:%d: accents must be comma-separated integers of 0 or greater: %q",
+
+	// --- epub ---
+	"辞書の出力形式 (stardict: StarDict形式, epub: 辞書の読み込みに対応していない電子書籍リーダー向けの、目次と相互参照のリンクを備えたEPUB)": "Dictionary output format (stardict: StarDict, epub: an EPUB with a table of contents and cross-reference links, for e-readers without dictionary support)",
+	"未対応の出力形式です: %s (%s または %s を指定してください)":                                                 "unsupported output format: %s (use %s or %s)",
+	"-format epub は -dry-run、-package、-install と同時に指定できません (これらはStarDict形式の辞書が対象です)":       "-format epub cannot be combined with -dry-run, -package or -install (they apply to StarDict dictionaries)",
+	"EPUBファイルの書き込みに失敗: %w": "failed to write EPUB file: %w",
+
+	// --- resource ---
+	"画像や音声などのファイルを格納したディレクトリ。ディレクトリの構成を保ったまま res/ にコピーし、-html の定義から相対パス(例: <img src=\"cat.png\">)で参照できるようにする":     "Directory of images, audio or other files to copy into res/ (keeping subdirectories) so -html definitions can reference them by relative path (e.g. <img src=\"cat.png\">)",
+	"res/ のファイル(-audio-dir や -resources で配置したもの)を、StarDict 3.0 のリソースデータベース(res.rifo, res.ridx, res.rdic.dz)にまとめる": "Pack the files in res/ (placed by -audio-dir or -resources) into a StarDict 3.0 resource database (res.rifo, res.ridx, res.rdic.dz)",
+	"リソースファイルはStarDict形式で -html 指定時のみ定義から参照できるため、-resources を無視します。":                                              "Resource files can only be referenced from StarDict definitions with -html; ignoring -resources.",
+	"リソースファイルの配置に失敗しました: %w":                     "failed to place resource files: %w",
+	"%d件のリソースファイルを配置しました。":                       "Placed %d resource files.",
+	"リソースデータベースの書き込みに失敗しました: %w":                 "failed to write resource database: %w",
+	"res/ に配置したファイルがないため、リソースデータベースを書き出しませんでした。": "No files were placed in res/; skipped writing the resource database.",
+	"%d件のリソースファイルをリソースデータベース(%s)にまとめました。":        "Packed %d resource files into the resource database (%s).",
+	"リソースファイルの読み込みに失敗: %w":                       "failed to read resource files: %w",
+	"%s の書き込みに失敗: %w":                            "failed to write %s: %w",
+
+	// --- reproducible ---
+	"辞書に記録する作成日時 (RFC 3339 の日時、2006-01-02 の形式の日付、または now)。空の場合は環境変数 SOURCE_DATE_EPOCH を使い、それもない場合は .ifo に変換した日を記録する。指定すると同じ入力から常に同じ内容の辞書を書き出す": "Creation time recorded in the dictionary (RFC 3339 time, a 2006-01-02 date, or now); if empty, SOURCE_DATE_EPOCH is used, and failing that the .ifo records the conversion date. When set, the same input always produces the same dictionary",
+	"書き出したファイルのSHA-256のチェックサムと、入力ファイルのチェックサム、変換の設定を manifest.json に記録する (同じ辞書が得られたかの確認向け)":                                                     "Record SHA-256 checksums of the written files and the input file, together with the conversion options, in manifest.json (to verify that two builds are identical)",
+	"環境変数 SOURCE_DATE_EPOCH はUNIX時間(秒)で指定してください: %q":                                        "SOURCE_DATE_EPOCH must be a Unix time in seconds: %q",
+	"-build-time は RFC 3339 の日時(2006-01-02T15:04:05Z)、日付(2006-01-02)、または now で指定してください: %q": "-build-time must be an RFC 3339 time (2006-01-02T15:04:05Z), a date (2006-01-02) or now: %q",
+	"%s のチェックサムの計算に失敗: %w":                                                                  "failed to compute the checksum of %s: %w",
+	"書き出したファイルのチェックサムの計算に失敗: %w":                                                            "failed to compute checksums of the written files: %w",
+	"%d件のファイルのチェックサムを %s に記録しました。":                                                          "Recorded checksums of %d files in %s.",
+
+	// --- pos ---
+	"指定した品詞のいずれかの定義行のみを対象とする (カンマ区切り。noun, verb, adj, adv などの英語の名前、または 名, 他動 などの英辞郎の品詞情報の表記。例: verb)": "Only include definition lines with one of these parts of speech (comma-separated English names such as noun, verb, adj, adv, or Eijiro tags such as 名, 他動; e.g. verb)",
+	"指定した品詞のいずれかの定義行を除外する (指定の形式は -pos と同じ)":                                                          "Exclude definition lines with any of these parts of speech (same format as -pos)",
+	"未対応の品詞です: %s (%s のいずれか、または英辞郎の品詞情報の表記(名、他動など)を指定してください)":                                         "unsupported part of speech: %s (use one of %s, or an Eijiro tag such as 名 or 他動)",
+
+	// --- watch ---
+	"変換の後も入力ファイル(英辞郎ファイル、頻度リスト、JMdictなど)を監視し、変更されるたびに変換し直す (英辞郎ファイルが変わっていない場合はパース結果のキャッシュを使う)": "After converting, keep watching the input files (Eijiro file, frequency list, JMdict, etc.) and convert again whenever they change (the parse cache is reused while the Eijiro file is unchanged)",
+	"-watch で入力ファイルの変更を確認する間隔":                  "How often -watch checks the input files for changes",
+	"-watch は -from-cache や -patch と同時に指定できません": "-watch cannot be combined with -from-cache or -patch",
+	"-watch-interval は正の時間で指定してください: %s":        "-watch-interval must be a positive duration: %s",
+	"パース結果のキャッシュの作成に失敗しました: %w":                 "failed to create the parse cache: %w",
+	"変換に失敗しました: %v":                             "conversion failed: %v",
+	"入力ファイルの変更を監視しています (Ctrl-C で終了): %s":        "Watching the input files for changes (press Ctrl-C to stop): %s",
+	"%s が変更されたため、変換し直します。":                      "%s changed; converting again.",
+
+	// --- idiom ---
+	"複数の単語からなる見出し語(句動詞、慣用句、連語など)を成句辞書(<辞書名>-idioms)に分け、構成する語(kick the bucket の bucket など)からも引けるようにする": "Move multi-word headwords (phrasal verbs, idioms, collocations) into a separate idiom dictionary (<name>-idioms), also indexed under their component words (e.g. \"bucket\" for \"kick the bucket\")",
+	"-idiom-dict は -single-word-only と同時に指定できません": "-idiom-dict cannot be combined with -single-word-only",
+	"%d件の成句の見出し語を成句辞書に分けました。":                     "Moved %d multi-word headwords into the idiom dictionary.",
+	"成句辞書の書き込みに失敗しました: %w":                        "failed to write the idiom dictionary: %w",
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 正規化の形式 (ParseOptions.Normalize に指定する値)
+const (
+	NormalizeNFC  = "nfc"
+	NormalizeNFKC = "nfkc"
+)
+
+// textNormalizer は見出し語や定義などの文字列を、指定された形式に揃える
+// 英辞郎には全角と半角、合成済みと分解された文字が混在しており、厳密に照合する辞書アプリでは検索できない場合がある
+type textNormalizer struct {
+	form           *norm.Form // Unicodeの正規化形式 (nil の場合は正規化しない)
+	halfwidthASCII bool       // 全角英数字・記号を半角に変換する
+}
+
+// newTextNormalizer はパースオプションから textNormalizer を作る
+// 正規化を行わない場合は nil を返す
+func newTextNormalizer(opts ParseOptions) (*textNormalizer, error) {
+	n := &textNormalizer{halfwidthASCII: opts.HalfwidthASCII}
+	switch opts.Normalize {
+	case "":
+	case NormalizeNFC:
+		form := norm.NFC
+		n.form = &form
+	case NormalizeNFKC:
+		form := norm.NFKC
+		n.form = &form
+	default:
+		return nil, errorf("未対応の正規化形式です: %s (%s または %s を指定してください)", opts.Normalize, NormalizeNFC, NormalizeNFKC)
+	}
+	if n.form == nil && !n.halfwidthASCII {
+		return nil, nil
+	}
+	return n, nil
+}
+
+// eijiroMarkup は英辞郎が記法として使う全角記号
+// 読み仮名 (漢字｛よみ｝)、見出し語の省略 (～)、別名のリンク (＝<→…>)、カタカナ発音 (【＠】) の目印であり、
+// 変換すると -html のルビやリンクとして認識できなくなるため、正規化の対象から除く
+const eijiroMarkup = "｛｝～＝＠"
+
+// String は s を正規化した文字列を返す (変更がない場合は s をそのまま返す)
+// 英辞郎の記法の記号 (eijiroMarkup) はそのまま残し、その間の文字列のみを正規化する
+func (n *textNormalizer) String(s string) string {
+	if !strings.ContainsAny(s, eijiroMarkup) {
+		return n.normalizeText(s)
+	}
+	var b strings.Builder
+	for s != "" {
+		i := strings.IndexAny(s, eijiroMarkup)
+		if i < 0 {
+			b.WriteString(n.normalizeText(s))
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(n.normalizeText(s[:i]))
+		b.WriteString(s[i : i+size])
+		s = s[i+size:]
+	}
+	return b.String()
+}
+
+// normalizeText は記法の記号を含まない文字列を正規化する
+func (n *textNormalizer) normalizeText(s string) string {
+	if n.form != nil {
+		s = n.form.String(s)
+	}
+	if n.halfwidthASCII && strings.ContainsFunc(s, isFullwidthASCII) {
+		s = strings.Map(func(r rune) rune {
+			if isFullwidthASCII(r) {
+				return r - 0xFEE0
+			}
+			return r
+		}, s)
+	}
+	return s
+}
+
+// isFullwidthASCII は r がASCIIの英数字・記号に対応する全角文字 (！から～まで) かどうかを返す
+// 英辞郎の記法の記号 (eijiroMarkup) は含まない
+func isFullwidthASCII(r rune) bool {
+	return r >= '！' && r <= '～' && !strings.ContainsRune(eijiroMarkup, r)
+}
+
+// normalizeAll はスライスの各要素を正規化する
+func (n *textNormalizer) normalizeAll(values []string) {
+	for i, s := range values {
+		values[i] = n.String(s)
+	}
+}
+
+// normalizeEntry はエントリのすべての文字列を正規化する
+// 見出し語と定義中のリンク先 (@@@LINK=) を同じ規則で揃えるため、参照の解決にも影響しない
+func (n *textNormalizer) normalizeEntry(entry *DictionaryEntry) {
+	entry.Headword = n.String(entry.Headword)
+	entry.Definition = n.String(entry.Definition)
+	entry.Pronunciation = n.String(entry.Pronunciation)
+	entry.Katakana = n.String(entry.Katakana)
+	entry.Level = n.String(entry.Level)
+	entry.Syllabification = n.String(entry.Syllabification)
+	n.normalizeAll(entry.Examples)
+	n.normalizeAll(entry.Keywords)
+	n.normalizeAll(entry.CrossRefs)
+	n.normalizeAll(entry.Hyphenation)
+	for i := range entry.Senses {
+		sense := &entry.Senses[i]
+		sense.POS = n.String(sense.POS)
+		sense.Gloss = n.String(sense.Gloss)
+		n.normalizeAll(sense.Alternatives)
+		n.normalizeAll(sense.Labels)
+		n.normalizeAll(sense.Domains)
+		n.normalizeAll(sense.Examples)
+		n.normalizeAll(sense.Supplements)
+	}
+}
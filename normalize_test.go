@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTextNormalizer は指定した形式で文字列が正規化されることを検証します。
+func TestTextNormalizer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     ParseOptions
+		input    string
+		expected string
+	}{
+		{"NFCで合成済みの文字にする", ParseOptions{Normalize: NormalizeNFC}, "café", "café"},
+		{"NFCでは全角英字を変えない", ParseOptions{Normalize: NormalizeNFC}, "ＣＤ", "ＣＤ"},
+		{"NFKCで全角英字と丸数字を変換する", ParseOptions{Normalize: NormalizeNFKC}, "ＣＤ①", "CD1"},
+		{"全角英数字・記号のみを半角にする", ParseOptions{HalfwidthASCII: true}, "ＣＤ－ＲＯＭ（１枚）　ｶﾞ", "CD-ROM(1枚)　ｶﾞ"},
+		{"NFCと半角化の併用", ParseOptions{Normalize: NormalizeNFC, HalfwidthASCII: true}, "Ｃafé", "Café"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			normalizer, err := newTextNormalizer(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := normalizer.String(tc.input); got != tc.expected {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+
+	if normalizer, err := newTextNormalizer(ParseOptions{}); normalizer != nil || err != nil {
+		t.Errorf("正規化しない場合は nil を返すはずです: %v %v", normalizer, err)
+	}
+	if _, err := newTextNormalizer(ParseOptions{Normalize: "nfd"}); err == nil {
+		t.Error("未対応の形式でエラーが返されていません")
+	}
+}
+
+// TestParseWithNormalization は見出し語・変化形・リンク先が同じ規則で正規化され、参照を解決できることを検証します。
+func TestParseWithNormalization(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■ｇｏ {動} : 行く【変化】《動》ｇｏｅｓ | ｗｅｎｔ",
+		"■ＣＤ {名} : 譲渡性預金",
+	}, "\n"))
+
+	entries, err := parseEijiro(path, ParseOptions{Normalize: NormalizeNFKC})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	final := resolveAndMergeEntries(entries)
+	definitions := make(map[string]string)
+	for _, entry := range final {
+		definitions[entry.Headword] = entry.Definition
+	}
+	if !strings.Contains(definitions["cd"], "譲渡性預金") {
+		t.Errorf("見出し語が半角に正規化されていません: %v", definitions)
+	}
+	if !strings.Contains(definitions["went"], "行く") {
+		t.Errorf("変化形のリンクが正規化した見出し語に解決されていません: %v", definitions)
+	}
+}
+
+// TestNormalizeKeepsRuby は -normalize と -halfwidth-ascii を指定しても、-html で読み仮名がルビとして描画されることを検証します。
+func TestNormalizeKeepsRuby(t *testing.T) {
+	path := writeEijiroTestFile(t, "■ｄｏｏｒ {名} : 扉｛とびら｝\n")
+	for _, opts := range []ParseOptions{{Normalize: NormalizeNFKC}, {HalfwidthASCII: true}} {
+		entries, err := parseEijiro(path, opts)
+		if err != nil {
+			t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+		}
+		renderer, err := newRenderer(WriteOptions{HTML: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		final := resolveAndMergeEntries(entries)
+		if len(final) != 1 || final[0].Headword != "door" {
+			t.Fatalf("見出し語が正規化されていません: %+v", final)
+		}
+		if got := renderer.Render(final[0]); !strings.Contains(got, "<ruby>扉<rt>とびら</rt></ruby>") {
+			t.Errorf("%+v: 読み仮名がルビになっていません: %q", opts, got)
+		}
+	}
+}
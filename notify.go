@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// RunSummary は変換処理の実行結果の概要
+// 完了通知ではこの構造体をJSONに変換して送信する
+type RunSummary struct {
+	Status          string       `json:"status"` // "success" または "failure"
+	Input           string       `json:"input"`
+	OutputDir       string       `json:"output_dir"`
+	BookName        string       `json:"book_name"`
+	Version         string       `json:"version,omitempty"`
+	ParsedEntries   int          `json:"parsed_entries"`
+	FinalEntries    int          `json:"final_entries"`
+	Phases          []PhaseCount `json:"phases,omitempty"` // 各段階を終えた時点のエントリ数
+	StartedAt       time.Time    `json:"started_at"`
+	FinishedAt      time.Time    `json:"finished_at"`
+	DurationSeconds float64      `json:"duration_seconds"`
+	Error           string       `json:"error,omitempty"`
+}
+
+// finish は終了時刻と処理結果を記録する
+func (s *RunSummary) finish(err error) {
+	s.FinishedAt = time.Now()
+	s.DurationSeconds = s.FinishedAt.Sub(s.StartedAt).Seconds()
+	s.Status = "success"
+	if err != nil {
+		s.Status = "failure"
+		s.Error = err.Error()
+	}
+}
+
+// notifyTimeout は完了通知の送信を待つ最大時間
+const notifyTimeout = 30 * time.Second
+
+// Notifier は変換処理の終了をWebhookやメールで通知する
+// 長時間の変換をヘッドレスなサーバーで実行する場合に利用する
+type Notifier struct {
+	WebhookURL string   // 実行結果のJSONをPOSTするURL
+	SMTPAddr   string   // メール送信に使うSMTPサーバー (host:port)
+	SMTPUser   string   // SMTP認証のユーザー名 (パスワードは環境変数 EIJIRO_SMTP_PASSWORD から読む)
+	From       string   // 通知メールの送信元
+	To         []string // 通知メールの宛先
+}
+
+// Enabled は通知先が一つでも設定されているかを返す
+func (n Notifier) Enabled() bool {
+	return n.WebhookURL != "" || n.SMTPAddr != ""
+}
+
+// Notify は設定されたすべての通知先に実行結果を送信する
+// 一部の送信に失敗した場合も、残りの通知先への送信は行う
+func (n Notifier) Notify(summary RunSummary) error {
+	body, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if n.WebhookURL != "" {
+		if err := n.postWebhook(body); err != nil {
+			errs = append(errs, errorf("Webhook: %w", err))
+		}
+	}
+	if n.SMTPAddr != "" {
+		if err := n.sendMail(summary, body); err != nil {
+			errs = append(errs, errorf("メール: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postWebhook は実行結果のJSONをWebhookにPOSTする
+func (n Notifier) postWebhook(body []byte) error {
+	client := &http.Client{Timeout: notifyTimeout}
+	resp, err := client.Post(n.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errorf("予期しないステータス: %s", resp.Status)
+	}
+	return nil
+}
+
+// sendMail は実行結果のJSONを本文とする通知メールを送信する
+func (n Notifier) sendMail(summary RunSummary, body []byte) error {
+	if n.From == "" || len(n.To) == 0 {
+		return errors.New(tr("送信元(-notify-from)と宛先(-notify-to)の指定が必要です"))
+	}
+	host, _, err := net.SplitHostPort(n.SMTPAddr)
+	if err != nil {
+		return errorf("SMTPサーバーの指定が不正です: %w", err)
+	}
+
+	var auth smtp.Auth
+	if n.SMTPUser != "" {
+		auth = smtp.PlainAuth("", n.SMTPUser, os.Getenv("EIJIRO_SMTP_PASSWORD"), host)
+	}
+	return smtp.SendMail(n.SMTPAddr, auth, n.From, n.To, buildNotifyMail(n.From, n.To, summary, body))
+}
+
+// buildNotifyMail は通知メールのメッセージ(ヘッダーと本文)を組み立てる
+func buildNotifyMail(from string, to []string, summary RunSummary, body []byte) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: eijiro-converter: %s (%s)\r\n", summary.Status, summary.BookName)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: application/json; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.Write(bytes.ReplaceAll(body, []byte("\n"), []byte("\r\n")))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
+
+// splitList はカンマ区切りの文字列を、空の要素を除いたスライスに分割する
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
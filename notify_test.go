@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNotifierWebhook は実行結果のJSONがWebhookにPOSTされることを検証します。
+func TestNotifierWebhook(t *testing.T) {
+	var received RunSummary
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("不正なリクエストです: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("JSONとして読み込めません: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	summary := RunSummary{BookName: "Eijiro", StartedAt: time.Now()}
+	summary.finish(errors.New("パースに失敗しました"))
+
+	notifier := Notifier{WebhookURL: server.URL}
+	if !notifier.Enabled() {
+		t.Fatalf("通知先が設定されているのに無効になっています")
+	}
+	if err := notifier.Notify(summary); err != nil {
+		t.Fatalf("Notifyでエラーが発生しました: %v", err)
+	}
+	if received.Status != "failure" || received.Error != "パースに失敗しました" {
+		t.Errorf("受信した実行結果が不正です: %+v", received)
+	}
+}
+
+// TestBuildNotifyMail は通知メールのヘッダーと本文を検証します。
+func TestBuildNotifyMail(t *testing.T) {
+	summary := RunSummary{Status: "success", BookName: "Eijiro"}
+	msg := string(buildNotifyMail("from@example.com", []string{"a@example.com", "b@example.com"}, summary, []byte("{\n}")))
+
+	for _, part := range []string{"To: a@example.com, b@example.com\r\n", "Subject: eijiro-converter: success (Eijiro)\r\n", "\r\n\r\n{\r\n}\r\n"} {
+		if !strings.Contains(msg, part) {
+			t.Errorf("メールに '%q' が含まれていません:\n%s", part, msg)
+		}
+	}
+	if (Notifier{SMTPAddr: "localhost:25"}).sendMail(summary, nil) == nil {
+		t.Errorf("宛先が未指定でもエラーになりませんでした")
+	}
+}
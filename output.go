@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stagingDirPattern は出力先ディレクトリ内に作る、書き出し中のファイルを置く一時ディレクトリの名前
+const stagingDirPattern = ".partial-*"
+
+// partialSuffix は出力先ディレクトリ以外に書き出すファイルの、書き出し中の名前に付ける接尾辞
+const partialSuffix = ".partial"
+
+// starDictBookExtensions は一つの辞書を構成するファイルの拡張子
+// 以前の変換で作られ、今回は作られなかったファイルを取り除くために使う
+var starDictBookExtensions = []string{".idx", ".idx.gz", ".dict", ".dict.dz", ".syn", ".css"}
+
+// pendingOutputs は変換中に書き出したファイルを一時的な名前で保持し、変換の成功時にまとめて本来の名前に置き換える
+// 中断や失敗の場合は一時的なファイルを削除するため、途中までしか書き出されていない辞書が有効な辞書に見えることはない
+// 名前の置き換えは同じディレクトリ内の rename で行うため、置き換えの途中で中断されても各ファイルは完全な内容のまま残る
+type pendingOutputs struct {
+	stageDir  string   // 出力先ディレクトリに置くファイルの一時ディレクトリ (空の場合は未作成)
+	outputDir string   // 出力先ディレクトリ
+	files     []string // 出力先ディレクトリ以外に書き出すファイルの本来のパス (一時的な名前は partialSuffix を付けたもの)
+}
+
+// stage は出力先ディレクトリ内に一時ディレクトリを作り、そのパスを返す
+// 出力先ディレクトリに置くファイルは、すべてこのディレクトリに書き出す
+func (p *pendingOutputs) stage(outputDir string) (string, error) {
+	dir, err := os.MkdirTemp(outputDir, stagingDirPattern)
+	if err != nil {
+		return "", errorf("一時ディレクトリの作成に失敗しました: %w", err)
+	}
+	p.stageDir, p.outputDir = dir, outputDir
+	return dir, nil
+}
+
+// file は出力先ディレクトリ以外に書き出すファイルの、書き出し中に使う一時的なパスを返す
+func (p *pendingOutputs) file(path string) string {
+	p.files = append(p.files, path)
+	return path + partialSuffix
+}
+
+// commit は一時的な名前で書き出したファイルを、本来の名前に置き換える
+func (p *pendingOutputs) commit() error {
+	for _, path := range p.files {
+		if err := os.Rename(path+partialSuffix, path); err != nil {
+			return errorf("%s の書き出しの確定に失敗しました: %w", path, err)
+		}
+	}
+	p.files = nil
+	if p.stageDir == "" {
+		return nil
+	}
+
+	staged := make(map[string]bool)
+	err := filepath.WalkDir(p.stageDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == p.stageDir {
+			return err
+		}
+		rel, err := filepath.Rel(p.stageDir, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(p.outputDir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		staged[rel] = true
+		return os.Rename(path, dest)
+	})
+	if err != nil {
+		return errorf("出力先への書き出しの確定に失敗しました: %w", err)
+	}
+
+	// 以前の変換で作られた同じ辞書のファイルのうち、今回作られなかったもの (例: 圧縮の有無を変えた場合の .dict.dz) を取り除く
+	// 辞書アプリが古いファイルを優先して読み込むことがあるため
+	for rel := range staged {
+		base, isBook := strings.CutSuffix(rel, ".ifo")
+		if !isBook {
+			continue
+		}
+		for _, ext := range starDictBookExtensions {
+			if staged[base+ext] {
+				continue
+			}
+			if err := os.Remove(filepath.Join(p.outputDir, base+ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errorf("以前の %s の削除に失敗しました: %w", base+ext, err)
+			}
+		}
+	}
+	err = os.RemoveAll(p.stageDir)
+	p.stageDir = ""
+	return err
+}
+
+// abort は一時的な名前で書き出したファイルをすべて削除する (確定済みのファイルには影響しない)
+func (p *pendingOutputs) abort() {
+	for _, path := range p.files {
+		os.Remove(path + partialSuffix)
+	}
+	p.files = nil
+	if p.stageDir != "" {
+		os.RemoveAll(p.stageDir)
+		p.stageDir = ""
+	}
+}
+
+// checkCanceled は変換が中断された (ctx が取り消された) 場合にエラーを返す
+func checkCanceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errorf("変換を中断しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+)
+
+// listFiles はディレクトリ以下のファイルの相対パスを並べて返します。
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, rel)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files
+}
+
+// TestPendingOutputs は確定時にのみファイルが本来の名前で現れ、中断時には何も残らないことを検証します。
+func TestPendingOutputs(t *testing.T) {
+	t.Run("確定", func(t *testing.T) {
+		dir := t.TempDir()
+		outputDir := filepath.Join(dir, "out")
+		os.MkdirAll(outputDir, 0755)
+		// 以前の変換で作られた圧縮済みの定義ファイルは、今回作られなかったため取り除かれる
+		os.WriteFile(filepath.Join(outputDir, "Test.dict.dz"), []byte("stale"), 0644)
+		os.WriteFile(filepath.Join(outputDir, "Other.dict.dz"), []byte("other"), 0644)
+
+		var outputs pendingOutputs
+		stageDir, err := outputs.stage(outputDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(stageDir, "Test.ifo"), []byte("ifo"), 0644)
+		os.WriteFile(filepath.Join(stageDir, "Test.dict"), []byte("dict"), 0644)
+		os.MkdirAll(filepath.Join(stageDir, "res"), 0755)
+		os.WriteFile(filepath.Join(stageDir, "res", "door.mp3"), []byte("mp3"), 0644)
+		jsonlPath := filepath.Join(dir, "out.jsonl")
+		os.WriteFile(outputs.file(jsonlPath), []byte("{}"), 0644)
+
+		if err := outputs.commit(); err != nil {
+			t.Fatalf("commitでエラーが発生しました: %v", err)
+		}
+		expected := []string{"Other.dict.dz", "Test.dict", "Test.ifo", filepath.Join("res", "door.mp3")}
+		if got := listFiles(t, outputDir); !slices.Equal(got, expected) {
+			t.Errorf("期待値: %q, 実際: %q", expected, got)
+		}
+		if _, err := os.Stat(jsonlPath); err != nil {
+			t.Errorf("出力先ディレクトリ以外のファイルが確定されていません: %v", err)
+		}
+	})
+
+	t.Run("中断", func(t *testing.T) {
+		dir := t.TempDir()
+		var outputs pendingOutputs
+		stageDir, err := outputs.stage(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(stageDir, "Test.idx"), []byte("idx"), 0644)
+		os.WriteFile(outputs.file(filepath.Join(dir, "keys.txt")), []byte("door"), 0644)
+
+		outputs.abort()
+		if got := listFiles(t, dir); len(got) != 0 {
+			t.Errorf("中断後にファイルが残っています: %q", got)
+		}
+	})
+}
+
+// TestRunConversionCanceled は中断された変換で、出力先に辞書やJSONLが一切残らないことを検証します。
+func TestRunConversionCanceled(t *testing.T) {
+	installFakeDictzip(t)
+	dir := t.TempDir()
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := runConversion(ctx, ConvertConfig{
+		InputFile:     path,
+		OutputDir:     filepath.Join(dir, "out"),
+		BookName:      "Test",
+		MergeStrategy: MergeConcat,
+		JSONLPath:     filepath.Join(dir, "out.jsonl"),
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("中断のエラーが返されていません: %v", err)
+	}
+	if got := listFiles(t, dir); len(got) != 0 {
+		t.Errorf("中断後にファイルが残っています: %q", got)
+	}
+}
+
+// TestRunConversionMaxDuration は制限時間を過ぎても、読み込めた分だけの辞書を書き出して正常終了することを検証します。
+func TestRunConversionMaxDuration(t *testing.T) {
+	installFakeDictzip(t)
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n■know {動} : 知っている\n")
+	testCases := []struct {
+		name        string
+		maxDuration time.Duration
+		wordCount   string // 書き出される見出し語の数
+	}{
+		{"時間内に終わる", time.Hour, "2"},
+		{"読み込みの途中で制限時間を過ぎる", time.Nanosecond, "1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputDir := filepath.Join(t.TempDir(), "out")
+			_, err := runConversion(context.Background(), ConvertConfig{
+				InputFile:     path,
+				OutputDir:     outputDir,
+				BookName:      "Test",
+				MergeStrategy: MergeConcat,
+				MaxDuration:   tc.maxDuration,
+			})
+			if err != nil {
+				t.Fatalf("runConversionでエラーが発生しました: %v", err)
+			}
+			info, err := readIfoFile(filepath.Join(outputDir, "Test.ifo"))
+			if err != nil {
+				t.Fatalf("辞書が書き出されていません: %v", err)
+			}
+			if info["wordcount"] != tc.wordCount {
+				t.Errorf("見出し語数の期待値: %s, 実際: %s", tc.wordCount, info["wordcount"])
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// OutputFS は辞書のファイルの書き出し先
+// ディレクトリのほか、メモリ上やオブジェクトストレージなど、ファイル名ごとに書き込める先であればよい
+type OutputFS interface {
+	// Create は name という名前のファイルを作成し、書き込み用に開く
+	// 書き込んだ内容は Close を呼び出した時点で確定する
+	Create(name string) (io.WriteCloser, error)
+}
+
+// DirFS はディレクトリ dir にファイルを書き出す OutputFS を返す
+func DirFS(dir string) OutputFS {
+	return dirFS(dir)
+}
+
+type dirFS string
+
+func (d dirFS) Create(name string) (io.WriteCloser, error) {
+	return os.Create(filepath.Join(string(d), name))
+}
+
+// MemFS はファイルをメモリ上に書き出す OutputFS
+// テストや、書き出した辞書をそのままレスポンスとして返すサーバーで使う
+// 複数のgoroutineから同時に書き出してもよい
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// Create は name という名前のファイルを作成する (同じ名前のファイルがある場合は Close の時点で置き換える)
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	return &memFile{fs: m, name: name}, nil
+}
+
+// File は書き出したファイルの内容を返す (ファイルがない場合は false)
+func (m *MemFS) File(name string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	return data, ok
+}
+
+// memFile は MemFS に書き出し中のファイル
+type memFile struct {
+	bytes.Buffer
+	fs   *MemFS
+	name string
+}
+
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.fs.files == nil {
+		f.fs.files = make(map[string][]byte)
+	}
+	f.fs.files[f.name] = f.Bytes()
+	return nil
+}
+
+// createOutput は out に name という名前のファイルを作成し、write で内容を書き込む
+func createOutput(out OutputFS, name string, write func(io.Writer) error) error {
+	w, err := out.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// WriteStarDict はエントリを StarDict 形式の辞書 (.ifo, .idx, .dict.dz, .syn, .css) として out に書き出す
+// 外部コマンドの dictzip はファイルにしか書き込めないため、.dict.dz は常にプロセス内でgzip互換の形式に圧縮する
+// 書き出し先にある以前の変換のファイルは削除しない (変換では一時ディレクトリに書き出し、pendingOutputs.commit で置き換える)
+func WriteStarDict(out OutputFS, bookName, version string, entries []DictionaryEntry, wopts WriteOptions) error {
+	return writeStarDict(out, bookName, version, entries, wopts, false)
+}
+
+// writeStarDict は WriteStarDict の本体
+// rawDict が true の場合は、圧縮する場合も .dict を非圧縮のまま書き出す (呼び出し側が dictzip で圧縮する)
+func writeStarDict(out OutputFS, bookName, version string, entries []DictionaryEntry, wopts WriteOptions, rawDict bool) error {
+	renderer, err := newRenderer(wopts)
+	if err != nil {
+		return err
+	}
+	// 書き込みを始める前に、テーマの指定に誤りがないことを確認しておく
+	var themeCSS []byte
+	if wopts.HTML {
+		if themeCSS, err = loadThemeCSS(wopts.Theme, wopts.AccentColor); err != nil {
+			return err
+		}
+	}
+	data, err := buildStarDictData(entries, renderer)
+	if err != nil {
+		return err
+	}
+
+	writeBytes := func(b []byte) func(io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := w.Write(b)
+			return err
+		}
+	}
+	writeCompressed := func(b []byte) func(io.Writer) error {
+		return func(w io.Writer) error { return writeGzip(w, b) }
+	}
+
+	if wopts.NoCompress || rawDict {
+		err = createOutput(out, bookName+".dict", writeBytes(data.dict))
+	} else {
+		err = createOutput(out, bookName+".dict.dz", writeCompressed(data.dict))
+	}
+	if err != nil {
+		return errorf(".dict ファイルの書き込みに失敗: %w", err)
+	}
+	// .ifo の idxfilesize は、.idx.gz に圧縮する場合も圧縮前の大きさのままとする
+	if wopts.CompressIdx {
+		err = createOutput(out, bookName+".idx.gz", writeCompressed(data.idx))
+	} else {
+		err = createOutput(out, bookName+".idx", writeBytes(data.idx))
+	}
+	if err != nil {
+		return errorf(".idx ファイルの書き込みに失敗: %w", err)
+	}
+	// HTML形式の場合は、辞書と同じ名前のスタイルシートを添える
+	// .ifo と同じ階層に置かれた同名の .css は、KOReaderやGoldenDictなどの辞書アプリが読み込む
+	if themeCSS != nil {
+		if err := createOutput(out, bookName+".css", writeBytes(themeCSS)); err != nil {
+			return errorf("スタイルシートの書き込みに失敗: %w", err)
+		}
+	}
+	// キーワードがある場合のみ .syn ファイルを書き込み
+	if len(data.synonyms) > 0 {
+		if err := createOutput(out, bookName+".syn", writeBytes(encodeSyn(data.synonyms))); err != nil {
+			return errorf(".syn ファイルの書き込みに失敗: %w", err)
+		}
+	}
+	info := newStarDictInfo(bookName, version, len(entries), data, renderer, wopts)
+	return createOutput(out, bookName+".ifo", func(w io.Writer) error { return writeIfo(w, info) })
+}
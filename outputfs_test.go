@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// TestParseFromAndWriteStarDict はメモリ上のデータを解析し、メモリ上に書き出した辞書がファイルに書き出した辞書と同じになることを検証します。
+func TestParseFromAndWriteStarDict(t *testing.T) {
+	content := strings.Join([]string{
+		"■door {名} : 扉【変化】《複》doors",
+		"■know {動} : 知っている、【＠】ノウ",
+	}, "\n")
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := ParseOptions{KeepStrippedKeywords: true, StripKatakana: true}
+	entries, err := ParseFrom(strings.NewReader(encoded), opts)
+	if err != nil {
+		t.Fatalf("ParseFromでエラーが発生しました: %v", err)
+	}
+	fromFile, err := parseEijiro(writeEijiroTestFile(t, content), opts)
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != len(fromFile) || entries[0].Definition != fromFile[0].Definition {
+		t.Fatalf("ファイルから読み込んだ結果と異なります: %+v", entries)
+	}
+
+	final := resolveAndMergeEntries(entries)
+	wopts := WriteOptions{NoCompress: true, HTML: true}
+	var mem MemFS
+	if err := WriteStarDict(&mem, "Test", "1.0", final, wopts); err != nil {
+		t.Fatalf("WriteStarDictでエラーが発生しました: %v", err)
+	}
+	dir := t.TempDir()
+	if err := writeStarDictFiles(dir, "Test", "1.0", final, wopts); err != nil {
+		t.Fatalf("writeStarDictFilesでエラーが発生しました: %v", err)
+	}
+	for _, name := range []string{"Test.ifo", "Test.idx", "Test.dict", "Test.syn", "Test.css"} {
+		want, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := mem.File(name)
+		if !ok {
+			t.Errorf("%s が書き出されていません", name)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s の内容がファイルに書き出したものと異なります", name)
+		}
+	}
+}
+
+// TestWriteStarDictCompressed は圧縮する場合に .dict.dz と .idx.gz が書き出されることを検証します。
+func TestWriteStarDictCompressed(t *testing.T) {
+	var mem MemFS
+	entries := []DictionaryEntry{{Headword: "door", Definition: "扉"}}
+	if err := WriteStarDict(&mem, "Test", "1.0", entries, WriteOptions{CompressIdx: true}); err != nil {
+		t.Fatalf("WriteStarDictでエラーが発生しました: %v", err)
+	}
+	for _, name := range []string{"Test.ifo", "Test.idx.gz", "Test.dict.dz"} {
+		if _, ok := mem.File(name); !ok {
+			t.Errorf("%s が書き出されていません", name)
+		}
+	}
+	if _, ok := mem.File("Test.idx"); ok {
+		t.Errorf("圧縮しない .idx が書き出されています")
+	}
+}
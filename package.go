@@ -0,0 +1,224 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 辞書をまとめるアーカイブの形式 (ConvertConfig.Package に指定する値)
+const (
+	PackageZip    = "zip"
+	PackageTarBz2 = "tar.bz2"
+)
+
+// packageReadmeName はアーカイブに同梱する、変換の設定を記したファイルの名前
+const packageReadmeName = "README.txt"
+
+// validatePackageFormat はアーカイブの形式の指定が正しいかを確認する
+func validatePackageFormat(format string) error {
+	switch format {
+	case "", PackageZip, PackageTarBz2:
+		return nil
+	}
+	return errorf("未対応のアーカイブ形式です: %s (%s または %s を指定してください)", format, PackageZip, PackageTarBz2)
+}
+
+// packageName はアーカイブと、その中の最上位のディレクトリの名前を返す
+// StarDict の配布物の慣例 (stardict-<辞書名>-<バージョン>) に合わせ、展開したディレクトリをそのまま辞書のディレクトリに置けるようにする
+func packageName(bookName, version string) string {
+	return "stardict-" + bookName + "-" + version
+}
+
+// packageFile はアーカイブに収めるファイル
+type packageFile struct {
+	name    string // アーカイブ内のパス ("/" 区切り)
+	data    []byte
+	modTime time.Time
+}
+
+// collectPackageFiles は dir 以下のファイルを、アーカイブ内の root 以下のパスとして名前順に集める
+func collectPackageFiles(dir, root string) ([]packageFile, error) {
+	var files []packageFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, packageFile{name: path.Join(root, filepath.ToSlash(rel)), data: data, modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// packageReadme は変換の設定を記した README.txt の内容を作る
+func packageReadme(cfg ConvertConfig, version string, files []packageFile) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s (英辞郎 %s から変換)\n\n", cfg.BookName, version)
+	fmt.Fprintf(&b, "入力ファイル: %s\n", filepath.Base(cfg.InputFile))
+	if buildTime := cfg.WriteOptions.BuildTime; !buildTime.IsZero() {
+		fmt.Fprintf(&b, "作成日時: %s\n", buildTime.Format(time.RFC3339))
+	}
+	b.WriteString("変換の設定:")
+	if len(cfg.BuildFlags) == 0 {
+		b.WriteString(" (既定の設定)\n")
+	} else {
+		b.WriteString("\n")
+		for _, flag := range cfg.BuildFlags {
+			fmt.Fprintf(&b, "  %s\n", flag)
+		}
+	}
+	b.WriteString("\n収録ファイル:\n")
+	for _, file := range files {
+		fmt.Fprintf(&b, "  %s\n", file.name)
+	}
+	b.WriteString("\nインストール: 展開したディレクトリを StarDict の辞書ディレクトリ (~/.stardict/dic など) または GoldenDict の辞書のフォルダに置いてください。\n")
+	b.WriteString("英辞郎のデータは著作物です。配布する場合は利用条件を確認してください。\n")
+	return b.Bytes()
+}
+
+// buildFlags はコマンドラインで指定したフラグを "-name=value" の形式で返す (README.txt に記録する)
+// 完了通知の宛先やWebhookのURLは秘密の情報を含みうるため、またログや表示言語の指定は辞書の内容に影響しないため除く
+func buildFlags(fs *flag.FlagSet) []string {
+	var flags []string
+	fs.Visit(func(f *flag.Flag) {
+		switch {
+		case strings.HasPrefix(f.Name, "notify-"), f.Name == "lang", f.Name == "v", f.Name == "vv", f.Name == "quiet", f.Name == "log-json":
+			return
+		}
+		flags = append(flags, "-"+f.Name+"="+f.Value.String())
+	})
+	return flags
+}
+
+// writePackage は dir 以下に書き出した辞書のファイルを、README.txt とともに一つのアーカイブにまとめる
+// アーカイブ内ではすべてのファイルを root ディレクトリの下に置き、日時は書き出した時刻に関わらず modTime とする
+func writePackage(dir, archivePath, format, root string, modTime time.Time, readme func([]packageFile) []byte) error {
+	files, err := collectPackageFiles(dir, root)
+	if err != nil {
+		return errorf("アーカイブに収めるファイルの読み込みに失敗しました: %w", err)
+	}
+	for i := range files {
+		files[i].modTime = modTime
+	}
+	files = append(files, packageFile{name: path.Join(root, packageReadmeName), data: readme(files), modTime: modTime})
+
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case PackageZip:
+		err = writeZipArchive(out, files)
+	case PackageTarBz2:
+		err = writeTarBz2Archive(out, files)
+	default:
+		err = validatePackageFormat(format)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeZipArchive はファイルをZIP形式で書き出す
+// 既に圧縮されているファイル (.dict.dz, .idx.gz, 音声など) は、圧縮し直さずにそのまま格納する
+func writeZipArchive(w io.Writer, files []packageFile) error {
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		header := &zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: file.modTime}
+		if isCompressedFile(file.name) {
+			header.Method = zip.Store
+		}
+		header.SetMode(0644)
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// isCompressedFile は圧縮済みの形式のファイルかどうかを拡張子から判断する
+func isCompressedFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".dz", ".gz", ".mp3", ".ogg", ".oga", ".m4a", ".opus":
+		return true
+	}
+	return false
+}
+
+// writeTarBz2Archive はファイルをtar形式にまとめ、bzip2 コマンドで圧縮して書き出す
+// Goの標準ライブラリには bzip2 の圧縮処理がないため、dictzip と同様に外部コマンドを使う
+func writeTarBz2Archive(w io.Writer, files []packageFile) error {
+	if _, err := exec.LookPath("bzip2"); err != nil {
+		return errorf("bzip2が見つかりません。bzip2 をインストールするか、-package %s を指定してください", PackageZip)
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	modTime := time.Now()
+	if len(files) > 0 {
+		modTime = files[0].modTime
+	}
+	// 展開したときにディレクトリの権限が正しく設定されるよう、ディレクトリの項目も書き出す
+	written := map[string]bool{}
+	var writeDir func(dir string) error
+	writeDir = func(dir string) error {
+		if dir == "." || written[dir] {
+			return nil
+		}
+		if err := writeDir(path.Dir(dir)); err != nil {
+			return err
+		}
+		written[dir] = true
+		return tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir + "/", Mode: 0755, ModTime: modTime})
+	}
+	for _, file := range files {
+		if err := writeDir(path.Dir(file.name)); err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: file.name, Mode: 0644, Size: int64(len(file.data)), ModTime: file.modTime}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("bzip2", "-c")
+	cmd.Stdin = &buf
+	cmd.Stdout = w
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errorf("bzip2の実行に失敗: %w\n%s", err, stderr.String())
+	}
+	return nil
+}
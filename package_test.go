@@ -0,0 +1,134 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readPackage はアーカイブを読み込み、ファイル名と内容の対応を返します。
+func readPackage(t *testing.T, path, format string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	switch format {
+	case PackageZip:
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatalf("ZIPファイルを開けません: %v", err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(data)
+		}
+	case PackageTarBz2:
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("アーカイブを開けません: %v", err)
+		}
+		defer file.Close()
+		tr := tar.NewReader(bzip2.NewReader(file))
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("tarの読み込みに失敗しました: %v", err)
+			}
+			data, _ := io.ReadAll(tr)
+			files[header.Name] = string(data)
+		}
+	}
+	return files
+}
+
+// TestRunConversionWithPackage は書き出した辞書と README.txt が、一つのディレクトリの下にまとめられることを検証します。
+func TestRunConversionWithPackage(t *testing.T) {
+	for _, format := range []string{PackageZip, PackageTarBz2} {
+		t.Run(format, func(t *testing.T) {
+			if format == PackageTarBz2 {
+				if _, err := exec.LookPath("bzip2"); err != nil {
+					t.Skip("bzip2 が見つからないため、テストをスキップします")
+				}
+			}
+			installFakeDictzip(t)
+			dir := t.TempDir()
+			path := writeEijiroTestFile(t, "■door {名} : 扉\n")
+			outputDir := filepath.Join(dir, "out")
+			_, err := runConversion(context.Background(), ConvertConfig{
+				InputFile:     path,
+				OutputDir:     outputDir,
+				BookName:      "Test",
+				MergeStrategy: MergeConcat,
+				ReverseIndex:  true,
+				Package:       format,
+				BuildFlags:    []string{"-reverse-index=true"},
+			})
+			if err != nil {
+				t.Fatalf("runConversionでエラーが発生しました: %v", err)
+			}
+
+			files := readPackage(t, filepath.Join(outputDir, "stardict-Test-1.0."+format), format)
+			var names []string
+			for name := range files {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			expected := []string{
+				"stardict-Test-1.0/README.txt",
+				"stardict-Test-1.0/Test-waei.dict.dz",
+				"stardict-Test-1.0/Test-waei.idx",
+				"stardict-Test-1.0/Test-waei.ifo",
+				"stardict-Test-1.0/Test.dict.dz",
+				"stardict-Test-1.0/Test.idx",
+				"stardict-Test-1.0/Test.ifo",
+			}
+			if format == PackageTarBz2 {
+				expected = append([]string{"stardict-Test-1.0/"}, expected...)
+			}
+			if !slices.Equal(names, expected) {
+				t.Errorf("期待値: %q, 実際: %q", expected, names)
+			}
+
+			ifo, _ := os.ReadFile(filepath.Join(outputDir, "Test.ifo"))
+			if files["stardict-Test-1.0/Test.ifo"] != string(ifo) {
+				t.Errorf("アーカイブ内の .ifo が書き出した内容と異なります")
+			}
+			readme := files["stardict-Test-1.0/README.txt"]
+			for _, want := range []string{"入力ファイル: EIJIRO-TEST.TXT", "  -reverse-index=true", "  stardict-Test-1.0/Test.ifo"} {
+				if !strings.Contains(readme, want) {
+					t.Errorf("README.txt に %q がありません:\n%s", want, readme)
+				}
+			}
+		})
+	}
+}
+
+// TestValidatePackageFormat は未対応のアーカイブ形式や、アーカイブを作れない指定をエラーにすることを検証します。
+func TestValidatePackageFormat(t *testing.T) {
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n")
+	for _, cfg := range []ConvertConfig{
+		{Package: "rar"},
+		{Package: PackageZip, DryRun: true},
+		{Package: PackageZip, DerivedOnly: true},
+	} {
+		cfg.InputFile, cfg.OutputDir, cfg.BookName, cfg.MergeStrategy = path, t.TempDir(), "Test", MergeConcat
+		if _, err := runConversion(context.Background(), cfg); err == nil {
+			t.Errorf("%+v: エラーになりませんでした", cfg)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseEijiroDeadline は時間制限を過ぎると見出し語の区切りで読み込みが打ち切られることを検証します。
+func TestParseEijiroDeadline(t *testing.T) {
+	path := writeEijiroTestFile(t, "■door {名} : 扉\n■door {動} : 戸を付ける\n■know {動} : 知っている【変化】《動》knows | knew\n■gate {名} : 門\n")
+
+	entries, err := parseEijiro(path, ParseOptions{Deadline: time.Now().Add(-time.Second)})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	// 最初の見出し語は同じ見出し語の行も含めて読み込み、打ち切った見出し語の変化形は残さない
+	if len(entries) != 1 || entries[0].Headword != "door" || entries[0].Definition != "{名} 扉\n{動} 戸を付ける" {
+		t.Errorf("打ち切り後のエントリが不正です: %+v", entries)
+	}
+
+	entries, err = parseEijiro(path, ParseOptions{Deadline: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Errorf("制限時間内ならすべて読み込まれるはずです: %d件", len(entries))
+	}
+}
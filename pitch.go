@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pitchAccentLabel は逆引き辞書の定義に追記するアクセントの行の先頭に付けるラベル
+const pitchAccentLabel = "【アクセント】"
+
+// contractedKana は直前の仮名とあわせて1拍に数える小書きの仮名 (促音の っ は1拍に数える)
+const contractedKana = "ぁぃぅぇぉゃゅょゎァィゥェォャュョヮ"
+
+// pitchAccent は語の読みと、そのアクセント核の位置 (0 は平板型) の一覧
+type pitchAccent struct {
+	Reading string
+	Accents []int
+}
+
+// pitchAccentIndex は表記 (漢字表記または読み) からアクセントを引くための索引
+type pitchAccentIndex map[string][]pitchAccent
+
+// loadPitchAccents はアクセントのデータ (UTF-8のTSV) を読み込む
+// 各行は "表記<TAB>読み<TAB>アクセント" (kanjium の accents.txt の形式) または "読み<TAB>アクセント" とし、
+// アクセントはアクセント核の位置 (0 は平板型) をカンマ区切りで並べる (例: "日本<TAB>にほん<TAB>2")
+func loadPitchAccents(path string) (pitchAccentIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	index := make(pitchAccentIndex)
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		var surface, reading, accentStr string
+		switch len(fields) {
+		case 3:
+			surface, reading, accentStr = fields[0], fields[1], fields[2]
+		case 2:
+			surface, reading, accentStr = fields[0], fields[0], fields[1]
+		default:
+			return nil, errorf("%s:%d: アクセントの行は \"表記<TAB>読み<TAB>アクセント\" または \"読み<TAB>アクセント\" の形式で指定してください", path, lineNo)
+		}
+		accent := pitchAccent{Reading: strings.TrimSpace(reading)}
+		for _, s := range strings.Split(accentStr, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || n < 0 {
+				return nil, errorf("%s:%d: アクセントは0以上の整数をカンマ区切りで指定してください: %q", path, lineNo, accentStr)
+			}
+			accent.Accents = appendUniqueInt(accent.Accents, n)
+		}
+		surface = strings.TrimSpace(surface)
+		if surface == "" || accent.Reading == "" {
+			continue
+		}
+		index[surface] = append(index[surface], accent)
+		if accent.Reading != surface {
+			index[accent.Reading] = append(index[accent.Reading], accent)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// appendUniqueInt は list にない値のみを追加する
+func appendUniqueInt(list []int, values ...int) []int {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// countMorae は仮名の読みの拍の数を数える (小書きの ゃ などは直前の仮名とあわせて1拍)
+func countMorae(reading string) int {
+	morae := 0
+	for _, r := range reading {
+		if !strings.ContainsRune(contractedKana, r) {
+			morae++
+		}
+	}
+	return morae
+}
+
+// pitchAccentPattern はアクセント核の位置と拍の数から、アクセントの型 (平板型・頭高型・中高型・尾高型) を返す
+func pitchAccentPattern(accent, morae int) string {
+	switch {
+	case accent == 0:
+		return "平板型"
+	case accent == 1:
+		return "頭高型"
+	case accent >= morae:
+		return "尾高型"
+	}
+	return "中高型"
+}
+
+// formatPitchAccents はアクセントを1行にする
+// 例: "【アクセント】にほん［2］中高型、にっぽん［3］中高型"
+func formatPitchAccents(accents []pitchAccent) string {
+	var parts []string
+	for _, accent := range accents {
+		for _, n := range accent.Accents {
+			parts = append(parts, accent.Reading+"［"+strconv.Itoa(n)+"］"+pitchAccentPattern(n, countMorae(accent.Reading)))
+		}
+	}
+	return pitchAccentLabel + strings.Join(parts, "、")
+}
+
+// attachPitchAccents は見出し語 (日本語) がアクセントのデータの表記または読みと一致するエントリに、
+// アクセントを「【アクセント】」の行として定義の先頭に加え、加えたエントリの数を返す
+// 読みは検索用キーワードにも加え、読みからも引けるようにする
+func attachPitchAccents(entries []DictionaryEntry, index pitchAccentIndex) (matched int) {
+	for i := range entries {
+		accents := index[entries[i].Headword]
+		if len(accents) == 0 {
+			continue
+		}
+		for _, accent := range accents {
+			if accent.Reading != entries[i].Headword {
+				entries[i].Keywords = appendUnique(entries[i].Keywords, accent.Reading)
+			}
+		}
+		entries[i].Definition = formatPitchAccents(accents) + "\n" + entries[i].Definition
+		matched++
+	}
+	return matched
+}
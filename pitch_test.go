@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadPitchAccents はアクセントのデータが表記と読みの両方から引けるように読み込まれ、形式の誤りがエラーになることを検証します。
+func TestLoadPitchAccents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "accents.txt")
+	os.WriteFile(path, []byte("# コメント\n日本\tにほん\t2\n日本\tにっぽん\t3\nはし\t1,2\n"), 0644)
+	index, err := loadPitchAccents(path)
+	if err != nil {
+		t.Fatalf("loadPitchAccentsでエラーが発生しました: %v", err)
+	}
+	expected := pitchAccentIndex{
+		"日本":   {{Reading: "にほん", Accents: []int{2}}, {Reading: "にっぽん", Accents: []int{3}}},
+		"にほん":  {{Reading: "にほん", Accents: []int{2}}},
+		"にっぽん": {{Reading: "にっぽん", Accents: []int{3}}},
+		"はし":   {{Reading: "はし", Accents: []int{1, 2}}},
+	}
+	if !reflect.DeepEqual(index, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, index)
+	}
+
+	invalid := filepath.Join(dir, "invalid.txt")
+	os.WriteFile(invalid, []byte("日本\tにほん\t二\n"), 0644)
+	if _, err := loadPitchAccents(invalid); err == nil {
+		t.Error("アクセントが整数でない行でエラーになりませんでした")
+	}
+}
+
+// TestPitchAccentPattern はアクセント核の位置と拍の数からアクセントの型が決まることを検証します。
+func TestPitchAccentPattern(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reading  string
+		accent   int
+		expected string
+	}{
+		{"平板型", "さくら", 0, "平板型"},
+		{"頭高型", "いのち", 1, "頭高型"},
+		{"中高型", "にほん", 2, "中高型"},
+		{"尾高型", "いもうと", 4, "尾高型"},
+		{"促音は1拍", "にっぽん", 3, "中高型"},
+		{"拗音は1拍", "きょう", 1, "頭高型"},
+		{"拗音を含む尾高型", "でんしゃ", 3, "尾高型"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pitchAccentPattern(tc.accent, countMorae(tc.reading)); got != tc.expected {
+				t.Errorf("期待値: %s, 実際: %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestAttachPitchAccents は一致する見出し語の定義の先頭にアクセントの行が加わり、読みが検索用キーワードになることを検証します。
+func TestAttachPitchAccents(t *testing.T) {
+	index := pitchAccentIndex{"日本": {{Reading: "にほん", Accents: []int{2}}, {Reading: "にっぽん", Accents: []int{3}}}}
+	entries := []DictionaryEntry{
+		{Headword: "日本", Definition: "Japan : {名} 日本"},
+		{Headword: "扉", Definition: "door : {名} 扉"},
+	}
+	if matched := attachPitchAccents(entries, index); matched != 1 {
+		t.Errorf("アクセントを加えたエントリの数が違います: %d", matched)
+	}
+	expected := "【アクセント】にほん［2］中高型、にっぽん［3］中高型\nJapan : {名} 日本"
+	if entries[0].Definition != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, entries[0].Definition)
+	}
+	if !reflect.DeepEqual(entries[0].Keywords, []string{"にほん", "にっぽん"}) {
+		t.Errorf("検索用キーワードが違います: %v", entries[0].Keywords)
+	}
+	if entries[1].Definition != "door : {名} 扉" {
+		t.Errorf("一致しない見出し語の定義が変わりました: %q", entries[1].Definition)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "strings"
+
+// headwordPlaceholders は成句の見出し語で目的語などの入る位置を表す記号
+const headwordPlaceholders = "~～〜"
+
+// placeholderVariants は "account for ~" のように記号を含む成句の見出し語から、記号を除いた検索用の形を返す
+// "account for" のように記号を入力せずに検索しても成句が見つかるようにする
+// 記号を含まない場合や、記号を除くと何も残らない場合は nil を返す
+// 例: "take ~ to court" -> ["take to court"]
+func placeholderVariants(headword string) []string {
+	if !strings.ContainsAny(headword, headwordPlaceholders) {
+		return nil
+	}
+	removed := collapseSpaces(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(headwordPlaceholders, r) {
+			return ' '
+		}
+		return r
+	}, headword))
+	// "~'s" のように記号に続く所有格だけが残った場合も取り除く
+	removed = collapseSpaces(strings.ReplaceAll(" "+removed+" ", " 's ", " "))
+	if removed == "" || removed == headword {
+		return nil
+	}
+	return []string{removed}
+}
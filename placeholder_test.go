@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPlaceholderVariants は成句の見出し語から記号を除いた検索用の形が作られることを検証します。
+func TestPlaceholderVariants(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"記号なし", "account", nil},
+		{"末尾の記号", "account for ~", []string{"account for"}},
+		{"先頭の記号", "～ of choice", []string{"of choice"}},
+		{"途中の記号", "take 〜 to court", []string{"take to court"}},
+		{"所有格", "at ~'s disposal", []string{"at disposal"}},
+		{"記号のみ", "~", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := placeholderVariants(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithPlaceholderKeys は記号を除いた形が検索用キーワードとして .syn に書き出されることを検証します。
+func TestParseWithPlaceholderKeys(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■account for ～ : ～を説明する",
+		"■(be) fond of ～ : ～が好きである",
+	}, "\n"))
+
+	entries, err := parseEijiro(path, ParseOptions{PlaceholderKeys: true, ExpandBrackets: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if !reflect.DeepEqual(entries[0].Keywords, []string{"account for"}) {
+		t.Errorf("検索用キーワードが不正です: %q", entries[0].Keywords)
+	}
+	// 括弧を展開した形からも記号を除く
+	expected := []string{"fond of ～", "(be) fond of ～", "be fond of", "fond of", "(be) fond of"}
+	if !reflect.DeepEqual(entries[1].Keywords, expected) {
+		t.Errorf("期待値: %q, 実際: %q", expected, entries[1].Keywords)
+	}
+}
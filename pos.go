@@ -0,0 +1,99 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// posCategories は品詞の指定に使える英語の名前と、それに該当する英辞郎の品詞情報 ({名} の "名" など)
+var posCategories = map[string][]string{
+	"noun":    {"名"},
+	"verb":    {"動", "自動", "他動", "句動"},
+	"adj":     {"形"},
+	"adv":     {"副"},
+	"prep":    {"前"},
+	"conj":    {"接"},
+	"pron":    {"代"},
+	"interj":  {"間", "間投"},
+	"aux":     {"助", "助動"},
+	"article": {"冠"},
+	"prefix":  {"接頭"},
+	"suffix":  {"接尾"},
+	"abbr":    {"略"},
+}
+
+// posFilter は品詞情報によって、定義行を残すかどうかを決める
+type posFilter struct {
+	include []string // 空でない場合、いずれかの品詞の行のみを残す (英辞郎の品詞情報の表記)
+	exclude []string // いずれかの品詞の行を除外する
+}
+
+// newPOSFilter はカンマ区切りで指定した品詞から posFilter を作る
+// 品詞は英語の名前 (noun, verb, adj など) または英辞郎の品詞情報の表記 (名, 他動 など。{} は省略できる) で指定する
+// どちらも指定しない場合は nil を返す
+func newPOSFilter(include, exclude string) (*posFilter, error) {
+	includeTags, err := resolvePOSNames(splitList(include))
+	if err != nil {
+		return nil, err
+	}
+	excludeTags, err := resolvePOSNames(splitList(exclude))
+	if err != nil {
+		return nil, err
+	}
+	if len(includeTags) == 0 && len(excludeTags) == 0 {
+		return nil, nil
+	}
+	return &posFilter{include: includeTags, exclude: excludeTags}, nil
+}
+
+// resolvePOSNames は品詞の指定を、英辞郎の品詞情報の表記の一覧にする
+// 英字のみからなる名前が posCategories にない場合は、綴りの誤りとみなしてエラーにする
+func resolvePOSNames(names []string) ([]string, error) {
+	var tags []string
+	for _, name := range names {
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "{"), "}")
+		if category, ok := posCategories[strings.ToLower(name)]; ok {
+			tags = appendUnique(tags, category...)
+			continue
+		}
+		if !strings.ContainsFunc(name, func(r rune) bool { return r >= 0x80 }) {
+			names := make([]string, 0, len(posCategories))
+			for known := range posCategories {
+				names = append(names, known)
+			}
+			slices.Sort(names)
+			return nil, errorf("未対応の品詞です: %s (%s のいずれか、または英辞郎の品詞情報の表記(名、他動など)を指定してください)", name, strings.Join(names, ", "))
+		}
+		tags = appendUnique(tags, name)
+	}
+	return tags, nil
+}
+
+// posTags は見出し語から分離した品詞情報 (例: "{他動-1}") を、語義の番号を除いた表記の一覧にする
+// "{名・形}" のように "・" で並べた品詞情報は、それぞれを品詞とみなす
+func posTags(pos string) []string {
+	pos = strings.TrimSuffix(strings.TrimPrefix(pos, "{"), "}")
+	if i := strings.IndexByte(pos, '-'); i >= 0 {
+		pos = pos[:i]
+	}
+	if pos == "" {
+		return nil
+	}
+	return strings.Split(pos, "・")
+}
+
+// keep は品詞情報が pos である定義行を残すかどうかを返す
+// 品詞情報のない定義行 (成句など) は、残す品詞を指定した場合は除外し、除外する品詞のみを指定した場合は残す
+func (f *posFilter) keep(pos string) bool {
+	if f == nil {
+		return true
+	}
+	tags := posTags(pos)
+	matches := func(list []string) bool {
+		return slices.ContainsFunc(tags, func(tag string) bool { return slices.Contains(list, tag) })
+	}
+	if matches(f.exclude) {
+		return false
+	}
+	return len(f.include) == 0 || matches(f.include)
+}
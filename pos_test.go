@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPOSFilterKeep は英語の名前または英辞郎の表記で指定した品詞によって、残す定義行が決まることを検証します。
+func TestPOSFilterKeep(t *testing.T) {
+	testCases := []struct {
+		name             string
+		include, exclude string
+		pos              string
+		expected         bool
+	}{
+		{"英語の名前", "verb", "", "{他動-1}", true},
+		{"該当しない品詞", "verb", "", "{名}", false},
+		{"英辞郎の表記", "{名}", "", "{名-2}", true},
+		{"複数の品詞", "noun,adj", "", "{形}", true},
+		{"・で並べた品詞", "adj", "", "{名・形}", true},
+		{"品詞情報がない行を残す品詞の指定", "noun", "", "", false},
+		{"品詞情報がない行を除外する品詞の指定", "", "noun", "", true},
+		{"除外", "", "abbr", "{略}", false},
+		{"両方に該当", "verb", "他動", "{他動}", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newPOSFilter(tc.include, tc.exclude)
+			if err != nil {
+				t.Fatalf("newPOSFilterでエラーが発生しました: %v", err)
+			}
+			if got := f.keep(tc.pos); got != tc.expected {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+
+	if f, err := newPOSFilter("", ""); f != nil || err != nil {
+		t.Errorf("指定がない場合は nil を返すはずです: %v, %v", f, err)
+	}
+	if _, err := newPOSFilter("nuon", ""); err == nil {
+		t.Error("英語の名前の綴り誤りでエラーになりませんでした")
+	}
+}
+
+// TestParsePOSFilter は品詞で除外した定義行が、その用例とともに読み飛ばされることを検証します。
+func TestParsePOSFilter(t *testing.T) {
+	path := writeEijiroTestFile(t, `■run {自動-1} : 走る■・run fast : 速く走る
+■run {名-1} : 走ること■・a long run : 長い走り
+■run for : ～に立候補する
+■door {名} : 扉
+`)
+	entries, err := parseEijiro(path, ParseOptions{IncludePOS: "verb"})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	var headwords []string
+	for _, entry := range entries {
+		headwords = append(headwords, entry.Headword)
+	}
+	if !reflect.DeepEqual(headwords, []string{"run"}) {
+		t.Fatalf("見出し語が違います: %v", headwords)
+	}
+	if entries[0].Definition != "{自動-1} 走る\n■run fast : 速く走る" {
+		t.Errorf("定義が違います: %q", entries[0].Definition)
+	}
+
+	entries, err = parseEijiro(path, ParseOptions{ExcludePOS: "verb,noun"})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Headword != "run for" {
+		t.Errorf("エントリが違います: %+v", entries)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// domainPenalty は専門分野のラベルが付いた語義を後ろに回すための重み
+// 訳語の長さによる重みよりも十分大きくし、ラベルの有無を優先して並べる
+const domainPenalty = 1000
+
+// senseRankScore は語義の並べ替えに使う点数を返す (小さいほど前に並ぶ)
+// 短い訳語ほど一般的な語義である可能性が高いとみなし、専門分野のラベルが付いたものは後ろに回す
+func senseRankScore(gloss string) int {
+	score := utf8.RuneCountInString(gloss)
+	if reDomainLabel.MatchString(gloss) {
+		score += domainPenalty
+	}
+	return score
+}
+
+// rankSenses はポップアップ辞書など先頭の数行しか表示されない環境向けに、
+// 各エントリの語義を有用と思われる順に並べ替える
+// 定義文字列は見出し語自身の部分のみを並べ替え、リンク先(原形)の定義や関連語の行は元の位置に残す
+func rankSenses(entries []DictionaryEntry) {
+	for i := range entries {
+		own, linked, hasLinked := strings.Cut(entries[i].Definition, "\n"+mergeSeparator+"\n")
+
+		// 関連語の行は並べ替えの対象外とし、末尾に残す
+		var trailing []string
+		var blocks []senseBlock
+		for _, block := range splitSenseBlocks(own) {
+			if strings.HasPrefix(block.lines[0], "【関連語】") {
+				trailing = append(trailing, block.lines...)
+				continue
+			}
+			blocks = append(blocks, block)
+		}
+		sort.SliceStable(blocks, func(a, b int) bool {
+			return senseRankScore(blocks[a].lines[0]) < senseRankScore(blocks[b].lines[0])
+		})
+
+		var lines []string
+		for _, block := range blocks {
+			lines = append(lines, block.lines...)
+		}
+		lines = append(lines, trailing...)
+		def := strings.Join(lines, "\n")
+		if hasLinked {
+			def += "\n" + mergeSeparator + "\n" + linked
+		}
+		entries[i].Definition = def
+
+		// 語義のスライスは参照の解決前のエントリと共有しているため、複製してから並べ替える
+		senses := slices.Clone(entries[i].Senses)
+		entries[i].Senses = senses
+		sort.SliceStable(senses, func(a, b int) bool {
+			return senseRankScore(senses[a].Gloss) < senseRankScore(senses[b].Gloss)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+// TestRankSenses は短い訳語が前に、専門分野の語義が後ろに並ぶことを検証します。
+func TestRankSenses(t *testing.T) {
+	entries := []DictionaryEntry{{
+		Headword:   "drive",
+		Definition: "{名} 《コ》ドライブ装置\n{動} 車を運転して人を送り届ける\n■drive someone home\n{動} 運転する\n---\n{名} 原形の定義",
+		Senses: []Sense{
+			{POS: "名", Gloss: "《コ》ドライブ装置"},
+			{POS: "動", Gloss: "車を運転して人を送り届ける"},
+			{POS: "動", Gloss: "運転する"},
+		},
+	}}
+	rankSenses(entries)
+
+	expected := "{動} 運転する\n{動} 車を運転して人を送り届ける\n■drive someone home\n{名} 《コ》ドライブ装置\n---\n{名} 原形の定義"
+	if entries[0].Definition != expected {
+		t.Errorf("定義の並びが不正です。\n期待値: %q\n実際: %q", expected, entries[0].Definition)
+	}
+	if entries[0].Senses[0].Gloss != "運転する" || entries[0].Senses[2].Gloss != "《コ》ドライブ装置" {
+		t.Errorf("語義の並びが不正です: %+v", entries[0].Senses)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxRelatedWords は一つのエントリに添える関連語の最大数
+const maxRelatedWords = 10
+
+// reSimpleWord は関連語をまとめる対象とする、英字のみからなる単語
+var reSimpleWord = regexp.MustCompile(`^[a-z]+$`)
+
+// stemSuffixRules は語幹を取り出すために取り除く接尾辞と、その置き換え
+// 長いものから順に調べ、最初に一致した規則を一度だけ適用する
+var stemSuffixRules = []struct {
+	suffix      string
+	replacement string
+}{
+	{"iness", "y"},
+	{"ation", "e"},
+	{"ement", "e"},
+	{"ness", ""},
+	{"ment", ""},
+	{"less", ""},
+	{"iest", "y"},
+	{"ily", "y"},
+	{"ies", "y"},
+	{"ied", "y"},
+	{"ier", "y"},
+	{"ful", ""},
+	{"ing", ""},
+	{"est", ""},
+	{"ly", ""},
+	{"ed", ""},
+	{"er", ""},
+	{"es", ""},
+	{"s", ""},
+}
+
+// negativePrefixes は派生語の判定で取り除く否定の接頭辞
+var negativePrefixes = []string{"un", "in", "dis", "non"}
+
+// minStemLength は語幹として認める最小の文字数
+const minStemLength = 3
+
+// stemWord は単語から接尾辞を取り除いた簡易的な語幹を返す (例: "happiness" -> "happy")
+func stemWord(word string) string {
+	for _, rule := range stemSuffixRules {
+		if strings.HasSuffix(word, rule.suffix) && len(word)-len(rule.suffix) >= minStemLength {
+			return strings.TrimSuffix(word, rule.suffix) + rule.replacement
+		}
+	}
+	return word
+}
+
+// addRelatedWords は語幹を共有する見出し語 (happy, happiness, unhappily など) をまとめ、
+// 各エントリの定義に「【関連語】」としてPDICリンク形式で追記する
+// PDICリンクはHTML形式ではそのまま参照先へのリンクになる
+func addRelatedWords(entries []DictionaryEntry) {
+	families := make(map[string][]int)
+	stems := make([]string, len(entries))
+	for i, entry := range entries {
+		word := strings.ToLower(entry.Headword)
+		if !reSimpleWord.MatchString(word) {
+			continue
+		}
+		stems[i] = stemWord(word)
+		families[stems[i]] = append(families[stems[i]], i)
+	}
+
+	// 否定の接頭辞を持つ単語は、接頭辞を除いた語幹の家族が存在する場合にのみそちらに移す
+	// ("under" のような、接頭辞ではない綴りを誤って分解しないため)
+	for i, entry := range entries {
+		if stems[i] == "" {
+			continue
+		}
+		word := strings.ToLower(entry.Headword)
+		for _, prefix := range negativePrefixes {
+			rest := strings.TrimPrefix(word, prefix)
+			if rest == word || len(rest) < minStemLength {
+				continue
+			}
+			if stem := stemWord(rest); stem != stems[i] && len(families[stem]) > 0 {
+				families[stems[i]] = removeIndex(families[stems[i]], i)
+				families[stem] = append(families[stem], i)
+				stems[i] = stem
+				break
+			}
+		}
+	}
+
+	for _, members := range families {
+		if len(members) < 2 {
+			continue
+		}
+		headwords := make([]string, 0, len(members))
+		for _, i := range members {
+			headwords = append(headwords, entries[i].Headword)
+		}
+		sort.Strings(headwords)
+
+		for _, i := range members {
+			var links []string
+			for _, headword := range headwords {
+				if headword != entries[i].Headword && len(links) < maxRelatedWords {
+					links = append(links, "<→"+headword+">")
+				}
+			}
+			if len(links) > 0 {
+				entries[i].Definition += "\n【関連語】" + strings.Join(links, "、")
+			}
+		}
+	}
+}
+
+// removeIndex はスライスから値を一つ取り除く
+func removeIndex(list []int, value int) []int {
+	for j, v := range list {
+		if v == value {
+			return append(list[:j:j], list[j+1:]...)
+		}
+	}
+	return list
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestStemWord は簡易的な語幹の取り出しを検証します。
+func TestStemWord(t *testing.T) {
+	testCases := map[string]string{
+		"happy":     "happy",
+		"happiness": "happy",
+		"happily":   "happy",
+		"doors":     "door",
+		"kindness":  "kind",
+		"cat":       "cat",
+	}
+	for word, expected := range testCases {
+		if got := stemWord(word); got != expected {
+			t.Errorf("%s: 期待値: %s, 実際: %s", word, expected, got)
+		}
+	}
+}
+
+// TestAddRelatedWords は語幹を共有する見出し語が関連語としてリンクされることを検証します。
+func TestAddRelatedWords(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "happy", Definition: "{形} 幸せな"},
+		{Headword: "happiness", Definition: "{名} 幸福"},
+		{Headword: "unhappily", Definition: "{副} 不幸にも"},
+		{Headword: "under", Definition: "{前} ～の下に"},
+		{Headword: "kick the bucket", Definition: "死ぬ"},
+	}
+	addRelatedWords(entries)
+
+	if !strings.HasSuffix(entries[0].Definition, "\n【関連語】<→happiness>、<→unhappily>") {
+		t.Errorf("happy の関連語が不正です: %q", entries[0].Definition)
+	}
+	if !strings.HasSuffix(entries[2].Definition, "\n【関連語】<→happiness>、<→happy>") {
+		t.Errorf("unhappily の関連語が不正です: %q", entries[2].Definition)
+	}
+	for _, entry := range entries[3:] {
+		if strings.Contains(entry.Definition, "【関連語】") {
+			t.Errorf("%s に関連語が追加されています: %q", entry.Headword, entry.Definition)
+		}
+	}
+}
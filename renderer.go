@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+)
+
+// mergeSeparator は定義の中で、見出し語自身の定義とリンク先(原形)の定義を区切る行
+const mergeSeparator = "---"
+
+// Renderer はエントリの定義を出力形式に応じた文字列に変換する
+// 定義は改行区切りの段落の並びとして扱い、段落のつなぎ方は出力形式ごとに決める
+type Renderer interface {
+	// Render はエントリの定義を出力形式の文字列に変換する
+	Render(entry DictionaryEntry) string
+	// TypeSequence は .ifo の sametypesequence に書き込む値を返す
+	TypeSequence() string
+}
+
+// newRenderer は出力オプションに対応する Renderer を返す
+// PhoneticField が有効な場合は、本体の Renderer の前に発音記号の項目を加える
+func newRenderer(wopts WriteOptions) (Renderer, error) {
+	body, err := newBodyRenderer(wopts)
+	if err != nil || !wopts.PhoneticField {
+		return body, err
+	}
+	return phoneticRenderer{body: body}, nil
+}
+
+// newBodyRenderer は定義本体を出力する Renderer を返す
+func newBodyRenderer(wopts WriteOptions) (Renderer, error) {
+	if !wopts.HTML {
+		return plainRenderer{}, nil
+	}
+	switch wopts.ParagraphStyle {
+	case "", "br":
+		return htmlRenderer{paragraphTag: false, accessiblePronunciation: wopts.AccessiblePronunciation, hyphenation: wopts.Hyphenation}, nil
+	case "p":
+		return htmlRenderer{paragraphTag: true, accessiblePronunciation: wopts.AccessiblePronunciation, hyphenation: wopts.Hyphenation}, nil
+	default:
+		return nil, errorf("未対応の段落の形式です: %s", wopts.ParagraphStyle)
+	}
+}
+
+// splitParagraphs は定義を段落に分割する
+// 空の段落は出力時に余計な空行になるため取り除く
+func splitParagraphs(def string) []string {
+	var paragraphs []string
+	for _, line := range strings.Split(def, "\n") {
+		if strings.TrimSpace(line) != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return paragraphs
+}
+
+// plainRenderer は定義をプレーンテキストとして出力する (段落は改行で区切る)
+type plainRenderer struct{}
+
+func (plainRenderer) Render(entry DictionaryEntry) string {
+	return strings.Join(splitParagraphs(entry.Definition), "\n")
+}
+
+func (plainRenderer) TypeSequence() string {
+	return "g" // 'g' はdictzip圧縮されたUTF-8テキストを意味する
+}
+
+// phoneticRenderer は発音記号を定義本体とは別の 't' (発音記号) の項目として出力する
+// sametypesequence は 't' に本体の形式を続けたもの (例: "tg", "th") になり、辞書アプリは発音記号を別に装飾できる
+// sametypesequence では全エントリが同じ項目の並びを持つ必要があるため、発音記号のないエントリも空の項目を出力する
+type phoneticRenderer struct {
+	body Renderer
+}
+
+func (r phoneticRenderer) Render(entry DictionaryEntry) string {
+	// 本体からは、't' の項目に移した発音記号を取り除く
+	if entry.Pronunciation != "" {
+		entry.Definition = rePronunciation.ReplaceAllString(entry.Definition, "")
+	}
+	// 最後以外の項目はNUL文字で終端する
+	return entry.Pronunciation + "\x00" + r.body.Render(entry)
+}
+
+func (r phoneticRenderer) TypeSequence() string {
+	return "t" + r.body.TypeSequence()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// manifestFileName は出力先に書き出す、辞書のファイルのチェックサムと変換の設定を記したファイルの名前
+const manifestFileName = "manifest.json"
+
+// reproducibleEpoch は作成日時の指定がない場合に、アーカイブや EPUB、dictzip のヘッダーに記録する固定の日時
+// ZIP で表せる最も古い日時とする
+var reproducibleEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// resolveBuildTime は -build-time の指定 (RFC 3339 の日時、"2006-01-02" の形式の日付、または "now") から、辞書に記録する作成日時を返す
+// 指定がない場合は環境変数 SOURCE_DATE_EPOCH (UNIX時間) を使い、それもない場合はゼロ値を返す
+// ゼロ値の場合は、同じ入力と設定からは常に同じバイト列の辞書を書き出す
+func resolveBuildTime(value string, getenv func(string) string) (time.Time, error) {
+	switch value {
+	case "":
+		epoch := getenv("SOURCE_DATE_EPOCH")
+		if epoch == "" {
+			return time.Time{}, nil
+		}
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, errorf("環境変数 SOURCE_DATE_EPOCH はUNIX時間(秒)で指定してください: %q", epoch)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	case "now":
+		return time.Now(), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errorf("-build-time は RFC 3339 の日時(2006-01-02T15:04:05Z)、日付(2006-01-02)、または now で指定してください: %q", value)
+}
+
+// fileTimestamp はアーカイブなどに記録するファイルの日時を返す (作成日時の指定がない場合は reproducibleEpoch)
+func fileTimestamp(buildTime time.Time) time.Time {
+	if buildTime.IsZero() {
+		return reproducibleEpoch
+	}
+	return buildTime
+}
+
+// buildManifest は書き出した辞書を検証するための、入力・設定・各ファイルのチェックサムの記録
+// 同じ英辞郎ファイルを同じ設定で変換した辞書は、files のチェックサムがすべて一致する
+type buildManifest struct {
+	Input     manifestFile   `json:"input"`
+	Version   string         `json:"version"`
+	BuildTime string         `json:"build_time,omitempty"`
+	Options   []string       `json:"options"`
+	Files     []manifestFile `json:"files"`
+}
+
+// manifestFile はファイルの名前・大きさ・SHA-256 のチェックサム
+type manifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// checksumFile はファイルの大きさと SHA-256 のチェックサムを求める
+func checksumFile(path, name string) (manifestFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return manifestFile{}, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return manifestFile{}, err
+	}
+	return manifestFile{Name: name, Size: size, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// newBuildManifest は dir 以下に書き出したファイル (名前順) と、入力ファイルのチェックサム、変換の設定を記録する
+// 設定はコマンドラインで指定したフラグ (BuildFlags) で、アーカイブの README.txt と同じものを記録する
+func newBuildManifest(dir string, cfg ConvertConfig, version string) (buildManifest, error) {
+	manifest := buildManifest{Version: version, Options: cfg.BuildFlags}
+	if manifest.Options == nil {
+		manifest.Options = []string{}
+	}
+	if !cfg.WriteOptions.BuildTime.IsZero() {
+		manifest.BuildTime = cfg.WriteOptions.BuildTime.UTC().Format(time.RFC3339)
+	}
+	source := cfg.InputFile
+	if cfg.FromCache != "" && cfg.Patch == "" {
+		source = cfg.FromCache // 英辞郎ファイルを読まない場合は、読み込んだキャッシュを記録する
+	}
+	var err error
+	if manifest.Input, err = checksumFile(source, filepath.Base(source)); err != nil {
+		return buildManifest{}, errorf("%s のチェックサムの計算に失敗: %w", source, err)
+	}
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == manifestFileName {
+			return err
+		}
+		file, err := checksumFile(p, filepath.ToSlash(rel))
+		manifest.Files = append(manifest.Files, file)
+		return err
+	})
+	if err != nil {
+		return buildManifest{}, errorf("書き出したファイルのチェックサムの計算に失敗: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeManifestFile は記録を JSON として書き出す
+func writeManifestFile(path string, manifest buildManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestResolveBuildTime は -build-time と SOURCE_DATE_EPOCH の指定から作成日時が決まり、指定がない場合はゼロ値になることを検証します。
+func TestResolveBuildTime(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		epoch    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"指定なし", "", "", time.Time{}, false},
+		{"SOURCE_DATE_EPOCH", "", "1700000000", time.Unix(1700000000, 0).UTC(), false},
+		{"日付", "2026-10-16", "1700000000", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), false},
+		{"日時", "2026-10-16T09:30:00Z", "", time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), false},
+		{"形式の誤り", "2026/10/16", "", time.Time{}, true},
+		{"SOURCE_DATE_EPOCHの誤り", "", "yesterday", time.Time{}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			getenv := func(string) string { return tc.epoch }
+			got, err := resolveBuildTime(tc.value, getenv)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("エラーの有無が違います: %v", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestReproducibleBuild は同じ入力と設定 (作成日時の指定を含む) から、時刻によらず同じバイト列の辞書とアーカイブが書き出され、
+// manifest.json のチェックサムが一致することを検証します。
+func TestReproducibleBuild(t *testing.T) {
+	path := writeEijiroTestFile(t, `■door {名} : 扉
+■Door {名} : ドア
+■bear {名} : 熊
+■bear {動} : 耐える
+■bore : <→bear>
+`)
+	root := t.TempDir()
+	build := func(name string) (string, buildManifest) {
+		dir := filepath.Join(root, name)
+		cfg := ConvertConfig{
+			InputFile: path, OutputDir: dir, BookName: "Test", MergeStrategy: MergeConcat,
+			WriteOptions: WriteOptions{HTML: true, NoCompress: true, BuildTime: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}, ReverseIndex: true, Package: PackageZip, Manifest: true,
+		}
+		if _, err := runConversion(context.Background(), cfg); err != nil {
+			t.Fatalf("runConversionでエラーが発生しました: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+		if err != nil {
+			t.Fatalf("%s が書き出されていません: %v", manifestFileName, err)
+		}
+		var manifest buildManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			t.Fatal(err)
+		}
+		return dir, manifest
+	}
+	first, firstManifest := build("first")
+	// ファイルの更新日時が変わっても、同じ内容が書き出されることを確かめるため、時刻をずらす
+	time.Sleep(1100 * time.Millisecond)
+	second, secondManifest := build("second")
+
+	firstJSON, _ := json.Marshal(firstManifest)
+	secondJSON, _ := json.Marshal(secondManifest)
+	if !bytes.Equal(firstJSON, secondJSON) {
+		t.Errorf("manifest.json の内容が一致しません:\n%s\n%s", firstJSON, secondJSON)
+	}
+	if len(firstManifest.Files) == 0 || firstManifest.Input.Name != filepath.Base(path) || firstManifest.Input.SHA256 == "" {
+		t.Errorf("manifest.json の記録が不足しています: %+v", firstManifest)
+	}
+	archive := packageName("Test", firstManifest.Version) + ".zip"
+	a, _ := os.ReadFile(filepath.Join(first, archive))
+	b, _ := os.ReadFile(filepath.Join(second, archive))
+	if len(a) == 0 || !bytes.Equal(a, b) {
+		t.Errorf("アーカイブのバイト列が一致しません")
+	}
+}
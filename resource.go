@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// StarDict のリソースデータベース (res/ ディレクトリの代わりに、リソースファイルを一つにまとめたもの) のファイル名
+const (
+	resourceInfoName  = "res.rifo"
+	resourceIndexName = "res.ridx"
+	resourceDataName  = "res.rdic"
+)
+
+// copyResources は srcDir 以下のファイル (画像や音声など) を、ディレクトリの構成を保ったまま出力先の res/ ディレクトリにコピーする
+// HTML形式の定義からは、res/ からの相対パス (例: <img src="animals/cat.png">) で参照できる
+// コピーしたファイルの数を返す
+func copyResources(srcDir, outputDir string) (int, error) {
+	resDir := filepath.Join(outputDir, resourceDirName)
+	copied := 0
+	err := filepath.WalkDir(srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(resDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(p, dst); err != nil {
+			return errorf("'%s' のコピーに失敗: %w", rel, err)
+		}
+		copied++
+		return nil
+	})
+	return copied, err
+}
+
+// resourceDatabase は res.ridx と res.rdic に書き出す内容
+type resourceDatabase struct {
+	index      []byte
+	data       []byte
+	fileCount  int
+	offsetBits int // res.ridx に記録した res.rdic 内の位置のビット数 (32 または 64)
+}
+
+// buildResourceDatabase はリソースファイルを res.rdic に連結し、ファイル名からその位置を引く res.ridx を組み立てる
+// res.ridx は .idx と同じ形式 (ファイル名、位置、大きさ) で、ファイル名のバイト列の順 (strcmp の順) に並べる
+func buildResourceDatabase(files []packageFile) resourceDatabase {
+	files = slices.Clone(files)
+	slices.SortFunc(files, func(a, b packageFile) int {
+		return bytes.Compare([]byte(a.name), []byte(b.name))
+	})
+	var data bytes.Buffer
+	offsets := make([]uint64, len(files))
+	for i, f := range files {
+		offsets[i] = uint64(data.Len())
+		data.Write(f.data)
+	}
+	offsetBits := idxOffsetBits(int64(data.Len()))
+	var index bytes.Buffer
+	for i, f := range files {
+		appendIdxEntry(&index, f.name, offsets[i], uint32(len(f.data)), offsetBits)
+	}
+	return resourceDatabase{index: index.Bytes(), data: data.Bytes(), fileCount: len(files), offsetBits: offsetBits}
+}
+
+// writeResourceDatabase は出力先の res/ ディレクトリのファイルを、StarDict 3.0 のリソースデータベース
+// (res.rifo, res.ridx, res.rdic.dz) にまとめ、res/ ディレクトリを削除する
+// 多数の小さな音声や画像のファイルを一つにまとめることで、辞書の配布やコピーが容易になる
+// res/ ディレクトリがない場合は何もせず、まとめたファイルの数 (0) を返す
+func writeResourceDatabase(outputDir string, noCompress bool, modTime time.Time) (int, error) {
+	resDir := filepath.Join(outputDir, resourceDirName)
+	if _, err := os.Stat(resDir); os.IsNotExist(err) {
+		return 0, nil
+	}
+	files, err := collectPackageFiles(resDir, "")
+	if err != nil {
+		return 0, errorf("リソースファイルの読み込みに失敗: %w", err)
+	}
+	db := buildResourceDatabase(files)
+	if err := writeDictFile(filepath.Join(outputDir, resourceDataName), db.data, noCompress, modTime); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, resourceIndexName), db.index, 0644); err != nil {
+		return 0, errorf("%s の書き込みに失敗: %w", resourceIndexName, err)
+	}
+	file, err := os.Create(filepath.Join(outputDir, resourceInfoName))
+	if err != nil {
+		return 0, err
+	}
+	if err := writeResourceInfo(file, db); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	// 辞書アプリがデータベースと res/ ディレクトリのどちらを読むか迷わないよう、まとめ終えたディレクトリは削除する
+	return db.fileCount, os.RemoveAll(resDir)
+}
+
+// writeResourceInfo は res.rifo の内容を w に書き出す
+func writeResourceInfo(w io.Writer, db resourceDatabase) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "StarDict's storage ifo file")
+	fmt.Fprintln(writer, "version=3.0.0")
+	fmt.Fprintf(writer, "filecount=%d\n", db.fileCount)
+	fmt.Fprintf(writer, "ridxfilesize=%d\n", len(db.index))
+	if db.offsetBits == 64 {
+		fmt.Fprintln(writer, "idxoffsetbits=64")
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// TestBuildResourceDatabase はリソースファイルがファイル名の順に連結され、res.ridx から位置と大きさを引けることを検証します。
+func TestBuildResourceDatabase(t *testing.T) {
+	db := buildResourceDatabase([]packageFile{
+		{name: "door.mp3", data: []byte("DOOR")},
+		{name: "animals/cat.png", data: []byte("CAT")},
+	})
+	if string(db.data) != "CATDOOR" || db.fileCount != 2 || db.offsetBits != 32 {
+		t.Fatalf("リソースデータベースの内容が違います: %q, %d件, %dビット", db.data, db.fileCount, db.offsetBits)
+	}
+	words, err := parseIdxData(db.index, db.offsetBits)
+	if err != nil {
+		t.Fatalf("res.ridx を解析できません: %v", err)
+	}
+	expected := []idxWord{{Word: "animals/cat.png", Offset: 0, Size: 3}, {Word: "door.mp3", Offset: 3, Size: 4}}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, words)
+	}
+}
+
+// TestWriteResourceDatabase は res/ のファイルがリソースデータベースにまとめられ、res/ ディレクトリが削除されることを検証します。
+func TestWriteResourceDatabase(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "animals"), 0755)
+	os.WriteFile(filepath.Join(src, "animals", "cat.png"), []byte("CAT"), 0644)
+	os.WriteFile(filepath.Join(src, "door.mp3"), []byte("DOOR"), 0644)
+
+	out := t.TempDir()
+	copied, err := copyResources(src, out)
+	if err != nil || copied != 2 {
+		t.Fatalf("copyResourcesの結果が違います: %d件, %v", copied, err)
+	}
+	if _, err := os.Stat(filepath.Join(out, resourceDirName, "animals", "cat.png")); err != nil {
+		t.Fatalf("ディレクトリの構成を保ってコピーされていません: %v", err)
+	}
+
+	packed, err := writeResourceDatabase(out, true, reproducibleEpoch)
+	if err != nil || packed != 2 {
+		t.Fatalf("writeResourceDatabaseの結果が違います: %d件, %v", packed, err)
+	}
+	index, _ := os.ReadFile(filepath.Join(out, resourceIndexName))
+	info, _ := os.ReadFile(filepath.Join(out, resourceInfoName))
+	expectedInfo := "StarDict's storage ifo file\nversion=3.0.0\nfilecount=2\nridxfilesize=" + strconv.Itoa(len(index)) + "\n"
+	if string(info) != expectedInfo {
+		t.Errorf("%s の期待値: %q, 実際: %q", resourceInfoName, expectedInfo, info)
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, resourceDataName)); string(data) != "CATDOOR" {
+		t.Errorf("%s の内容が違います: %q", resourceDataName, data)
+	}
+	if _, err := os.Stat(filepath.Join(out, resourceDirName)); !os.IsNotExist(err) {
+		t.Error("res/ ディレクトリが削除されていません")
+	}
+
+	// res/ ディレクトリがない場合は何も書き出さない
+	empty := t.TempDir()
+	if packed, err := writeResourceDatabase(empty, true, reproducibleEpoch); err != nil || packed != 0 {
+		t.Errorf("res/ がない場合の結果が違います: %d件, %v", packed, err)
+	}
+	if _, err := os.Stat(filepath.Join(empty, resourceInfoName)); !os.IsNotExist(err) {
+		t.Errorf("res/ がない場合に %s が書き出されました", resourceInfoName)
+	}
+}
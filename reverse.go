@@ -0,0 +1,116 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// reverseBookSuffix は逆引き(和英)辞書のファイル名に付ける接尾辞
+const reverseBookSuffix = "-waei"
+
+// maxReverseKeyLength は逆引きの見出し語として採用する訳語の最大文字数
+// これより長いものは説明文とみなして索引に含めない
+const maxReverseKeyLength = 20
+
+// 訳語を取り出す際に利用する正規表現
+var (
+	reReverseBrackets = regexp.MustCompile(`\{.*?\}|｛.*?｝|〔.*?〕|《.*?》|（.*?）|\(.*?\)|<→.*?>`)
+	reReverseLabels   = regexp.MustCompile(`【.*$|◆.*$`)
+	reReverseSplit    = regexp.MustCompile(`[、，,;；／/]`)
+)
+
+// buildReverseEntries は英和のエントリから日本語の訳語をキーとする逆引きエントリを生成する
+// 各エントリの定義は「英語の見出し語 : 訳語を含む定義行」の一覧になる
+// expandがtrueの場合、言い換え ([…]) を含む訳語は展開したそれぞれの訳語をキーとする
+func buildReverseEntries(entries []DictionaryEntry, expand bool) []DictionaryEntry {
+	type reverseRef struct {
+		headword string
+		line     string
+	}
+	index := make(map[string][]reverseRef)
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		// リンクでマージされた原形の定義("---"以降)は、その見出し語自身の訳語ではないので対象外とする
+		ownDef, _, _ := strings.Cut(entry.Definition, "\n---\n")
+		for _, line := range strings.Split(ownDef, "\n") {
+			// 用例(■)と補足説明(◆)の行は訳語ではないので対象外とする
+			if line == "" || strings.HasPrefix(line, "■") || strings.HasPrefix(line, "◆") || strings.HasPrefix(line, "@@@LINK=") {
+				continue
+			}
+			keys := extractJapaneseGlosses(line)
+			if expand {
+				keys = expandReverseKeys(keys)
+			}
+			for _, key := range keys {
+				pair := key + "\x00" + entry.Headword
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+				index[key] = append(index[key], reverseRef{headword: entry.Headword, line: line})
+			}
+		}
+	}
+
+	reverseEntries := make([]DictionaryEntry, 0, len(index))
+	for key, refs := range index {
+		lines := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			lines = append(lines, ref.headword+" : "+ref.line)
+		}
+		reverseEntries = append(reverseEntries, DictionaryEntry{Headword: key, Definition: strings.Join(lines, "\n")})
+	}
+	// マップの走査順に依存しないよう、見出し語順に並べておく
+	sort.Slice(reverseEntries, func(i, j int) bool {
+		return reverseEntries[i].Headword < reverseEntries[j].Headword
+	})
+	return reverseEntries
+}
+
+// extractJapaneseGlosses は定義行から逆引きのキーとなる日本語の訳語を取り出す
+// 例: "{名} 扉、ドア【レベル】1" -> ["扉", "ドア"]
+func extractJapaneseGlosses(line string) []string {
+	line = reReverseLabels.ReplaceAllString(line, "")
+	line = reReverseBrackets.ReplaceAllString(line, "")
+
+	var glosses []string
+	for _, part := range reReverseSplit.Split(line, -1) {
+		gloss := strings.TrimSpace(part)
+		if gloss == "" || utf8.RuneCountInString(gloss) > maxReverseKeyLength || !containsJapanese(gloss) {
+			continue
+		}
+		glosses = append(glosses, gloss)
+	}
+	return glosses
+}
+
+// expandReverseKeys は言い換えを含む訳語を展開した訳語に置き換える
+func expandReverseKeys(glosses []string) []string {
+	var keys []string
+	for _, gloss := range glosses {
+		if !reAlternative.MatchString(gloss) {
+			keys = append(keys, gloss)
+			continue
+		}
+		for _, key := range expandAlternatives(gloss) {
+			if utf8.RuneCountInString(key) <= maxReverseKeyLength && containsJapanese(key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// containsJapanese は文字列にひらがな・カタカナ・漢字が含まれるかを判定する
+func containsJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
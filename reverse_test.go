@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExtractJapaneseGlosses は定義行から日本語の訳語が取り出されることを検証します。
+func TestExtractJapaneseGlosses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     string
+		expected []string
+	}{
+		{
+			name:     "品詞とラベルが取り除かれる",
+			line:     "{名} 扉、ドア【レベル】1",
+			expected: []string{"扉", "ドア"},
+		},
+		{
+			name:     "補足の括弧が取り除かれる",
+			line:     "{動} 〔人・動物〕を追い払う、運転する",
+			expected: []string{"を追い払う", "運転する"},
+		},
+		{
+			name:     "英語のみの部分は対象外",
+			line:     "{名} NASA、米航空宇宙局",
+			expected: []string{"米航空宇宙局"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := extractJapaneseGlosses(tc.line)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestBuildReverseEntries は逆引きエントリが訳語ごとに英語の見出し語をまとめることを検証します。
+func TestBuildReverseEntries(t *testing.T) {
+	entries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉、ドア\n■Close the door. : 扉を閉めて。"},
+		{Headword: "gate", Definition: "{名} 門、扉"},
+		{Headword: "doors", Definition: "{名} ドアーズ\n---\n{名} 扉、ドア"},
+	}
+
+	result := make(map[string]string)
+	for _, entry := range buildReverseEntries(entries, false) {
+		result[entry.Headword] = entry.Definition
+	}
+
+	if def := result["扉"]; !strings.Contains(def, "door : ") || !strings.Contains(def, "gate : ") {
+		t.Errorf("'扉' の逆引きに door と gate が含まれていません: %q", def)
+	}
+	if strings.Contains(result["扉"], "doors") {
+		t.Errorf("マージされた原形の定義は逆引きの対象外のはずです: %q", result["扉"])
+	}
+	if _, ok := result["扉を閉めて。"]; ok {
+		t.Errorf("用例の訳文が逆引きのキーになっています")
+	}
+}
+
+// TestSortEntriesForStarDict は索引がStarDictの比較規則で並ぶことを検証します。
+func TestSortEntriesForStarDict(t *testing.T) {
+	entries := []DictionaryEntry{{Headword: "b"}, {Headword: "B"}, {Headword: "a"}, {Headword: "ab"}}
+	var got []string
+	for _, entry := range sortEntriesForStarDict(entries) {
+		got = append(got, entry.Headword)
+	}
+	expected := []string{"a", "ab", "B", "b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, got)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// headwordSelector は入力の一部の見出し語のみを変換するための絞り込み
+// テンプレートの調整や特定の文字の見出し語の不具合調査で、変換全体をやり直さずに済むよう、
+// 見出し語の先頭の文字の範囲で絞り込んだうえで、offset 件を読み飛ばし、続く limit 件のみを対象にする
+// 件数は品詞などが異なる連続した行をまとめた見出し語の単位で数える
+type headwordSelector struct {
+	from, to rune // 先頭の文字の範囲 (0の場合は絞り込まない)
+	offset   int
+	limit    int // 0の場合は件数で絞り込まない
+
+	count int    // 範囲内で読み込んだ見出し語の数
+	last  string // 直前の行の見出し語
+	keep  bool   // 直前の行の見出し語を対象にするかどうか
+	done  bool   // limit 件を読み込み終えた
+}
+
+// newHeadwordSelector は絞り込みの指定から headwordSelector を作成する (何も指定しない場合は nil)
+// headwordRange は "a-c" のような先頭の文字の範囲、または "x" のような1文字
+func newHeadwordSelector(headwordRange string, offset, limit int) (*headwordSelector, error) {
+	if headwordRange == "" && offset == 0 && limit == 0 {
+		return nil, nil
+	}
+	if offset < 0 || limit < 0 {
+		return nil, errorf("-offset と -limit には0以上の値を指定してください")
+	}
+	s := &headwordSelector{offset: offset, limit: limit}
+	if headwordRange != "" {
+		from, to, found := strings.Cut(strings.ToLower(headwordRange), "-")
+		if !found {
+			to = from
+		}
+		if utf8.RuneCountInString(from) != 1 || utf8.RuneCountInString(to) != 1 {
+			return nil, errorf("見出し語の範囲は a-c のように先頭の文字で指定してください: %s", headwordRange)
+		}
+		s.from, _ = utf8.DecodeRuneInString(from)
+		s.to, _ = utf8.DecodeRuneInString(to)
+		if s.from > s.to {
+			return nil, errorf("見出し語の範囲は a-c のように先頭の文字で指定してください: %s", headwordRange)
+		}
+	}
+	return s, nil
+}
+
+// accept は見出し語の行を対象にするかどうかを判断する
+// headword は品詞情報を除く前の見出し語でよい (同じ見出し語の連続した行は、最初の行と同じ結果になる)
+func (s *headwordSelector) accept(headword string) bool {
+	if base, _, found := strings.Cut(headword, "{"); found {
+		headword = base
+	}
+	headword = strings.TrimSpace(headword)
+	if headword == s.last {
+		return s.keep
+	}
+	s.last = headword
+	s.keep = false
+	if s.from != 0 && !s.inRange(headword) {
+		return false
+	}
+	s.count++
+	if s.count <= s.offset {
+		return false
+	}
+	if s.limit > 0 && s.count > s.offset+s.limit {
+		s.done = true
+		return false
+	}
+	s.keep = true
+	return true
+}
+
+// inRange は見出し語の最初の英字 ("(be) fond of ～" の場合は b) が範囲内にあるかどうかを判断する
+func (s *headwordSelector) inRange(headword string) bool {
+	for _, r := range headword {
+		if unicode.IsLetter(r) {
+			r = unicode.ToLower(r)
+			return r >= s.from && r <= s.to
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestHeadwordSelector は見出し語の範囲・読み飛ばす数・最大数で、変換する見出し語が絞り込まれることを検証します。
+func TestHeadwordSelector(t *testing.T) {
+	path := writeEijiroTestFile(t, `■apple {名} : りんご
+■bear {名} : 熊
+■bear {動} : 耐える
+■(be) fond of ～ : ～が好きである
+■Cat {名} : 猫
+■door {名} : 扉
+`)
+	testCases := []struct {
+		name     string
+		opts     ParseOptions
+		expected []string
+	}{
+		{"範囲", ParseOptions{HeadwordRange: "b-c"}, []string{"bear", "(be) fond of ～", "Cat"}},
+		{"1文字の範囲", ParseOptions{HeadwordRange: "D"}, []string{"door"}},
+		{"読み飛ばす数と最大数", ParseOptions{Offset: 1, Limit: 2}, []string{"bear", "(be) fond of ～"}},
+		{"範囲内で数える", ParseOptions{HeadwordRange: "b-d", Offset: 2, Limit: 1}, []string{"Cat"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := parseEijiro(path, tc.opts)
+			if err != nil {
+				t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Headword)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestNewHeadwordSelector は絞り込みの指定の誤りがエラーになることを検証します。
+func TestNewHeadwordSelector(t *testing.T) {
+	testCases := []struct {
+		name          string
+		headwordRange string
+		offset, limit int
+		wantErr       bool
+	}{
+		{"指定なし", "", 0, 0, false},
+		{"範囲", "a-c", 0, 0, false},
+		{"範囲が逆順", "c-a", 0, 0, true},
+		{"2文字", "ab", 0, 0, true},
+		{"負の値", "", -1, 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := newHeadwordSelector(tc.headwordRange, tc.offset, tc.limit); (err != nil) != tc.wantErr {
+				t.Errorf("エラーの有無が違います: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// reSenseNumber は訳語中の番号付きの語義の区切り (例: "1. 取る、2. 持って行く" の "1." と "2.") に一致する
+// 小数 ("1.5倍") と区別するため、番号の直後に数字が続くものや、語の途中にあるものは区切りとみなさない
+var reSenseNumber = regexp.MustCompile(`(?:^|[\s、，,；;])([1-9１-９][0-9０-９]?)[.．]\s*`)
+
+// splitNumberedSenses は "1. 取る、2. 持って行く" のように一行に番号付きで並べた訳語を、語義ごとに分ける
+// 番号が 1 から始まり、1 ずつ増える場合のみ分け、そうでない場合は nil を返す
+// 語義の間の読点やセミコロンは、区切りの一部として取り除く
+// 例: "1. 取る、2. 持って行く" -> ["取る", "持って行く"]
+func splitNumberedSenses(gloss string) []string {
+	matches := reSenseNumber.FindAllStringSubmatchIndex(gloss, -1)
+	if len(matches) < 2 || strings.TrimSpace(gloss[:matches[0][0]]) != "" {
+		return nil
+	}
+	var senses []string
+	for i, m := range matches {
+		number, err := strconv.Atoi(width.Narrow.String(gloss[m[2]:m[3]]))
+		if err != nil || number != i+1 {
+			return nil
+		}
+		end := len(gloss)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		// 番号の直後に数字が続く場合は小数なので、区切りとみなさない
+		text := gloss[m[1]:end]
+		if text == "" || strings.ContainsAny(text[:1], "0123456789") {
+			return nil
+		}
+		senses = append(senses, strings.TrimSpace(strings.TrimRight(text, " 、，,；;")))
+	}
+	return senses
+}
+
+// splitSense は番号付きの訳語を持つ語義を、番号ごとの語義に分ける
+// 品詞とラベル、分野・用法のラベルは分けた語義のすべてに、行内の補足説明は最後の語義に付ける
+// 番号付きの訳語がない場合は、元の語義のみを返す
+func splitSense(sense Sense, opts ParseOptions) []Sense {
+	glosses := splitNumberedSenses(sense.Gloss)
+	if glosses == nil {
+		return []Sense{sense}
+	}
+	senses := make([]Sense, len(glosses))
+	for i, gloss := range glosses {
+		senses[i] = Sense{POS: sense.POS, Gloss: gloss, Labels: slices.Clone(sense.Labels), Domains: slices.Clone(sense.Domains), Number: i + 1}
+		if opts.ExpandAlternatives && reAlternative.MatchString(gloss) {
+			senses[i].Alternatives = expandAlternatives(gloss)
+		}
+	}
+	senses[len(senses)-1].Supplements = sense.Supplements
+	return senses
+}
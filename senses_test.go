@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitNumberedSenses は一行に番号付きで並べた訳語が語義ごとに分けられることを検証します。
+func TestSplitNumberedSenses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"番号なし", "取る、持って行く", nil},
+		{"読点で区切る", "1. 取る、2. 持って行く", []string{"取る", "持って行く"}},
+		{"空白で区切る", "1.取る 2.持って行く 3.連れて行く", []string{"取る", "持って行く", "連れて行く"}},
+		{"全角の番号", "１．取る、２．持って行く", []string{"取る", "持って行く"}},
+		{"番号が1から始まらない", "2. 取る、3. 持って行く", nil},
+		{"番号の前に訳語がある", "取る、1. 持って行く、2. 連れて行く", nil},
+		{"小数", "1. 約1.5倍、2. 2倍", nil},
+		{"番号が一つだけ", "1. 取る", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := splitNumberedSenses(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestParseWithSplitSenses は -split-senses 指定時に番号付きの語義が別々の語義になることを検証します。
+func TestParseWithSplitSenses(t *testing.T) {
+	path := writeEijiroTestFile(t, "■take {他動} : 1. 取る、2. 持って行く【大学入試】◆補足■・Take it. : それを取りなさい。\n")
+
+	entries, err := parseEijiro(path, ParseOptions{SplitSenses: true})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	expected := []Sense{
+		{POS: "他動", Gloss: "取る", Labels: []string{"大学入試"}, Number: 1},
+		{POS: "他動", Gloss: "持って行く", Labels: []string{"大学入試"}, Number: 2, Supplements: []string{"◆補足"}, Examples: []string{"Take it. : それを取りなさい。"}},
+	}
+	if !reflect.DeepEqual(entries[0].Senses, expected) {
+		t.Errorf("語義が不正です。\n期待値: %+v\n実際: %+v", expected, entries[0].Senses)
+	}
+
+	// オプションを指定しない場合は一つの語義のまま
+	entries, err = parseEijiro(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	if len(entries[0].Senses) != 1 || entries[0].Senses[0].Number != 0 {
+		t.Errorf("語義が分けられています: %+v", entries[0].Senses)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+)
+
+// runServeCommand は serve サブコマンドを実行する
+// 英辞郎ファイルを変換と同じオプションで読み込み、指定されたプロトコルで検索を提供する
+// 複数のサーバーを指定した場合は同時に起動し、いずれかが停止した時点で終了する
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter serve [-dict] [-http] [オプション]"))
+		fs.PrintDefaults()
+	}
+	inputFile := fs.String("i", "EIJIRO-1448.TXT", "提供する英辞郎ファイル名")
+	mergeStrategy := fs.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)")
+	dictMode := fs.Bool("dict", false, "DICTプロトコル (RFC 2229) のサーバーを起動する")
+	dictAddr := fs.String("dict-addr", dictDefaultAddr, "DICTプロトコルのサーバーが待ち受けるアドレス")
+	httpMode := fs.Bool("http", false, "JSONでエントリを返すHTTPサーバーを起動する")
+	httpAddr := fs.String("http-addr", httpDefaultAddr, "HTTPサーバーが待ち受けるアドレス")
+	parseOptions := registerParseFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if !*dictMode && !*httpMode {
+		fs.Usage()
+		return errorf("起動するサーバーを指定してください (-dict または -http)")
+	}
+
+	log.Print(sprintf("%s を読み込んでいます...", *inputFile))
+	dict, err := loadEijiroDictionary(*inputFile, parseOptions(), *mergeStrategy)
+	if err != nil {
+		return err
+	}
+	log.Print(sprintf("%d件のエントリを読み込みました。", dict.Len()))
+
+	// 待ち受けの失敗はすぐに報告できるよう、サーバーを動かす前にすべて待ち受けを始めておく
+	errc := make(chan error, 2)
+	if *dictMode {
+		ln, err := net.Listen("tcp", *dictAddr)
+		if err != nil {
+			return errorf("DICTサーバーを起動できません: %w", err)
+		}
+		log.Print(sprintf("DICTサーバーを %s で起動しました。", ln.Addr()))
+		server := &dictServer{dict: dict, renderer: plainRenderer{}}
+		go func() { errc <- server.serve(ln) }()
+	}
+	if *httpMode {
+		ln, err := net.Listen("tcp", *httpAddr)
+		if err != nil {
+			return errorf("HTTPサーバーを起動できません: %w", err)
+		}
+		log.Print(sprintf("HTTPサーバーを %s で起動しました。", ln.Addr()))
+		server := &apiServer{dict: dict}
+		go func() { errc <- http.Serve(ln, server.handler()) }()
+	}
+	return <-errc
+}
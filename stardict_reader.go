@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ifoMagic は .ifo ファイルの先頭行
+const ifoMagic = "StarDict's dict ifo file"
+
+// idxWord は .idx ファイルの1エントリ（見出し語と、.dict 内の定義の位置）
+type idxWord struct {
+	Word   string
+	Offset uint64
+	Size   uint32
+}
+
+// StarDictBook は書き出し済みのStarDict辞書 (.ifo/.idx/.dict(.dz)/.syn) を読み込んだもの
+// 検証やコマンドラインでの検索に使うため、ファイルの内容はすべてメモリに読み込む
+// ファイルの内容に矛盾があっても読み込みは行い、矛盾の検出は validateStarDictBook に任せる
+type StarDictBook struct {
+	Path     string            // .ifo ファイルのパス
+	Info     map[string]string // .ifo のキーと値
+	IdxSize  int               // .idx ファイルの大きさ (バイト数)
+	Words    []idxWord
+	Synonyms []synonymEntry
+	dict     []byte // 展開済みの .dict の内容
+}
+
+// openStarDict は .ifo ファイルのパスを受け取り、同じ名前の .idx/.dict(.dz)/.syn とあわせて読み込む
+func openStarDict(ifoPath string) (*StarDictBook, error) {
+	base := strings.TrimSuffix(ifoPath, ".ifo")
+	book := &StarDictBook{Path: ifoPath}
+
+	info, err := readIfoFile(ifoPath)
+	if err != nil {
+		return nil, errorf(".ifo ファイルの読み込みに失敗: %w", err)
+	}
+	book.Info = info
+
+	idxData, err := readIdxData(base)
+	if err != nil {
+		return nil, err
+	}
+	book.IdxSize = len(idxData)
+	// idxoffsetbits=64 の辞書では、.dict 内の位置が64ビットで記録されている
+	offsetBits := 32
+	if info["idxoffsetbits"] == "64" {
+		offsetBits = 64
+	}
+	if book.Words, err = parseIdxData(idxData, offsetBits); err != nil {
+		return nil, errorf(".idx ファイルの解析に失敗: %w", err)
+	}
+
+	if book.dict, err = readDictData(base); err != nil {
+		return nil, err
+	}
+
+	synData, err := os.ReadFile(base + ".syn")
+	if err == nil {
+		if book.Synonyms, err = parseSynData(synData); err != nil {
+			return nil, errorf(".syn ファイルの解析に失敗: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, errorf(".syn ファイルの読み込みに失敗: %w", err)
+	}
+	return book, nil
+}
+
+// readIfoFile は .ifo ファイルを読み込み、キーと値の組を返す
+func readIfoFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != ifoMagic {
+		return nil, errorf("先頭行が '%s' ではありません", ifoMagic)
+	}
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if found {
+			info[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return info, scanner.Err()
+}
+
+// parseIdxData は .idx ファイルの内容を見出し語の並びに分解する
+// offsetBits は .dict 内の位置のビット数 (.ifo の idxoffsetbits。32 または 64)
+func parseIdxData(data []byte, offsetBits int) ([]idxWord, error) {
+	var words []idxWord
+	err := walkIdxData(data, offsetBits, func(word []byte, offset uint64, size uint32) {
+		words = append(words, idxWord{Word: string(word), Offset: offset, Size: size})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// walkIdxData は .idx ファイルの内容の各エントリについて、見出し語と .dict 内の位置、大きさを fn に渡す
+// word は data の一部を指すため、fn の外で使う場合は複写すること
+func walkIdxData(data []byte, offsetBits int, fn func(word []byte, offset uint64, size uint32)) error {
+	offsetSize := offsetBits / 8
+	for n := 1; len(data) > 0; n++ {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 || len(data) < end+1+offsetSize+4 {
+			return errorf("%d件目のエントリが途中で終わっています", n)
+		}
+		var offset uint64
+		if offsetBits == 64 {
+			offset = binary.BigEndian.Uint64(data[end+1:])
+		} else {
+			offset = uint64(binary.BigEndian.Uint32(data[end+1:]))
+		}
+		fn(data[:end], offset, binary.BigEndian.Uint32(data[end+1+offsetSize:]))
+		data = data[end+1+offsetSize+4:]
+	}
+	return nil
+}
+
+// countIdxRecords は書き出した辞書 (base は拡張子を除いたパス) の .idx の見出し語の数を数える
+// 見出し語の文字列は作らないため、大きな辞書でもメモリをほとんど使わない
+func countIdxRecords(base string) (int, error) {
+	info, err := readIfoFile(base + ".ifo")
+	if err != nil {
+		return 0, errorf(".ifo ファイルの読み込みに失敗: %w", err)
+	}
+	data, err := readIdxData(base)
+	if err != nil {
+		return 0, err
+	}
+	offsetBits := 32
+	if info["idxoffsetbits"] == "64" {
+		offsetBits = 64
+	}
+	count := 0
+	if err := walkIdxData(data, offsetBits, func([]byte, uint64, uint32) { count++ }); err != nil {
+		return 0, errorf(".idx ファイルの解析に失敗: %w", err)
+	}
+	return count, nil
+}
+
+// parseSynData は .syn ファイルの内容を同義語の並びに分解する
+func parseSynData(data []byte) ([]synonymEntry, error) {
+	var synonyms []synonymEntry
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 || len(data) < end+5 {
+			return nil, errorf("%d件目のエントリが途中で終わっています", len(synonyms)+1)
+		}
+		synonyms = append(synonyms, synonymEntry{
+			Word:  string(data[:end]),
+			Index: binary.BigEndian.Uint32(data[end+1:]),
+		})
+		data = data[end+5:]
+	}
+	return synonyms, nil
+}
+
+// readDictData は .dict.dz (dictzip形式はgzipと互換) または非圧縮の .dict を読み込む
+func readDictData(base string) ([]byte, error) {
+	file, err := os.Open(base + ".dict.dz")
+	if errors.Is(err, os.ErrNotExist) {
+		data, err := os.ReadFile(base + ".dict")
+		if err != nil {
+			return nil, errorf(".dict.dz または .dict ファイルの読み込みに失敗: %w", err)
+		}
+		return data, nil
+	} else if err != nil {
+		return nil, errorf(".dict.dz ファイルの読み込みに失敗: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errorf(".dict.dz ファイルの展開に失敗: %w", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errorf(".dict.dz ファイルの展開に失敗: %w", err)
+	}
+	return data, nil
+}
+
+// readIdxData は .idx.gz があれば展開して、なければ .idx をそのまま読み込む
+func readIdxData(base string) ([]byte, error) {
+	file, err := os.Open(base + ".idx.gz")
+	if errors.Is(err, os.ErrNotExist) {
+		data, err := os.ReadFile(base + ".idx")
+		if err != nil {
+			return nil, errorf(".idx ファイルの読み込みに失敗: %w", err)
+		}
+		return data, nil
+	} else if err != nil {
+		return nil, errorf(".idx.gz ファイルの読み込みに失敗: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errorf(".idx.gz ファイルの展開に失敗: %w", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errorf(".idx.gz ファイルの展開に失敗: %w", err)
+	}
+	return data, nil
+}
+
+// infoInt は .ifo の数値の項目を返す (項目がない場合は found が false)
+func (b *StarDictBook) infoInt(key string) (value int, found bool, err error) {
+	s, found := b.Info[key]
+	if !found {
+		return 0, false, nil
+	}
+	value, err = strconv.Atoi(s)
+	if err != nil {
+		return 0, true, errorf("%s の値 '%s' が数値ではありません", key, s)
+	}
+	return value, true, nil
+}
+
+// Definition は i 番目の見出し語の定義を返す
+func (b *StarDictBook) Definition(i int) ([]byte, error) {
+	w := b.Words[i]
+	end := w.Offset + uint64(w.Size)
+	if end > uint64(len(b.dict)) {
+		return nil, errorf("'%s' の定義の位置 (%d+%d) が .dict の大きさ (%d) を超えています", w.Word, w.Offset, w.Size, len(b.dict))
+	}
+	return b.dict[w.Offset:end], nil
+}
+
+// definitionField は sametypesequence で区切られた定義データの一つの項目
+type definitionField struct {
+	Type byte   // 項目の種類 (例: 't' は発音記号, 'g'・'m' はテキスト, 'h' はHTML)
+	Data []byte // 項目の内容
+}
+
+// DefinitionFields は i 番目の見出し語の定義データを、.ifo の sametypesequence に従って項目に分けて返す
+// sametypesequence がない辞書は、定義データ全体を一つの項目として返す
+// 最後以外の項目はNUL文字で終端されている必要があり、大きさを前置する大文字の種類の項目には対応しない
+func (b *StarDictBook) DefinitionFields(i int) ([]definitionField, error) {
+	data, err := b.Definition(i)
+	if err != nil {
+		return nil, err
+	}
+	seq := b.Info["sametypesequence"]
+	if seq == "" {
+		return []definitionField{{Type: 0, Data: data}}, nil
+	}
+	fields := make([]definitionField, 0, len(seq))
+	for j := 0; j < len(seq); j++ {
+		t := seq[j]
+		if t < 'a' || t > 'z' {
+			return nil, errorf("sametypesequence の項目 '%c' には対応していません", t)
+		}
+		if j == len(seq)-1 {
+			fields = append(fields, definitionField{Type: t, Data: data})
+			break
+		}
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			return nil, errorf("'%s' の定義に sametypesequence (%s) の %d 番目の項目の終端がありません", b.Words[i].Word, seq, j+1)
+		}
+		fields = append(fields, definitionField{Type: t, Data: data[:end]})
+		data = data[end+1:]
+	}
+	return fields, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestParseIdxDataTruncated は途中で終わっている .idx が読み込みエラーになることを検証します。
+func TestParseIdxDataTruncated(t *testing.T) {
+	if _, err := parseIdxData([]byte("door\x00\x00\x00"), 32); err == nil {
+		t.Errorf("途中で終わっている .idx でエラーが返されていません")
+	}
+}
+
+// TestIdxOffsetBits は .dict が32ビットで表せる大きさを超えた場合のみ64ビットの位置を使うことを検証します。
+func TestIdxOffsetBits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dictSize int64
+		expected int
+	}{
+		{"小さい辞書", 1024, 32},
+		{"32ビットの上限", math.MaxUint32, 32},
+		{"32ビットの上限を超える", math.MaxUint32 + 1, 64},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := idxOffsetBits(tc.dictSize); got != tc.expected {
+				t.Errorf("期待値: %d, 実際: %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestIdxEntry64 は64ビットの位置で書き込んだ .idx と .ifo を読み込めることを検証します。
+func TestIdxEntry64(t *testing.T) {
+	var buf bytes.Buffer
+	appendIdxEntry(&buf, "door", 5<<32, 10, 64)
+	appendIdxEntry(&buf, "know", 7, 3, 64)
+	words, err := parseIdxData(buf.Bytes(), 64)
+	if err != nil {
+		t.Fatalf("parseIdxDataでエラーが発生しました: %v", err)
+	}
+	expected := []idxWord{{Word: "door", Offset: 5 << 32, Size: 10}, {Word: "know", Offset: 7, Size: 3}}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("期待値: %+v, 実際: %+v", expected, words)
+	}
+
+	path := filepath.Join(t.TempDir(), "Test.ifo")
+	writeIfoTestFile := func(info StarDictInfo) {
+		var buf bytes.Buffer
+		if err := writeIfo(&buf, info); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeIfoTestFile(StarDictInfo{Version: "1.0", BookName: "Test", IdxOffsetBits: 64})
+	info, err := readIfoFile(path)
+	if err != nil || info["idxoffsetbits"] != "64" {
+		t.Errorf(".ifo に idxoffsetbits=64 が書き出されていません: %v %v", err, info)
+	}
+	writeIfoTestFile(StarDictInfo{Version: "1.0", BookName: "Test", IdxOffsetBits: 32})
+	if info, _ := readIfoFile(path); info["idxoffsetbits"] != "" {
+		t.Errorf("32ビットの場合は idxoffsetbits を書き出さないはずです: %v", info)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// statsReport は変換結果の統計情報
+// オプションの組み合わせによる違いを比べられるよう、表またはJSONで出力する
+type statsReport struct {
+	Input                   string         `json:"input"`
+	Lines                   int            `json:"lines"`
+	ParsedEntries           int            `json:"parsed_entries"`
+	Headwords               int            `json:"headwords"`
+	SkippedLines            int            `json:"skipped_lines"`
+	IgnoredLines            int            `json:"ignored_lines"`
+	DuplicateHeadwords      int            `json:"duplicate_headwords"`
+	FinalEntries            int            `json:"final_entries"`
+	Senses                  int            `json:"senses"`
+	Examples                int            `json:"examples"`
+	Supplements             int            `json:"supplements"`
+	AverageDefinitionLength float64        `json:"average_definition_length"`
+	LinkEntries             int            `json:"link_entries"`
+	LinkTargets             int            `json:"link_targets"`
+	UnresolvedLinks         int            `json:"unresolved_links"`
+	POS                     map[string]int `json:"pos"`
+	Levels                  map[string]int `json:"levels"`
+}
+
+// buildStatsReport はパース結果と、参照の解決・定義のまとめを終えたエントリから統計情報を集計する
+func buildStatsReport(input string, parsed []DictionaryEntry, stats ParseStats, final []DictionaryEntry) statsReport {
+	report := parsedStatsReport(input, parsed, stats)
+	report.addFinal(final)
+	return report
+}
+
+// parsedStatsReport はパース結果のみから求まる統計情報を集計する
+// 参照の解決でパース結果の領域を再利用する場合は、参照の解決の前に集計しておく
+func parsedStatsReport(input string, parsed []DictionaryEntry, stats ParseStats) statsReport {
+	report := statsReport{
+		Input:         input,
+		Lines:         stats.Lines,
+		ParsedEntries: len(parsed),
+		Headwords:     stats.Headwords,
+		SkippedLines:  stats.SkippedLines,
+		IgnoredLines:  stats.IgnoredLines,
+		LinkEntries:   stats.LinkEntries,
+		POS:           make(map[string]int),
+		Levels:        make(map[string]int),
+	}
+	_, report.DuplicateHeadwords = countMergeKeys(parsed)
+
+	keys := make(map[string]bool, len(parsed))
+	for _, entry := range parsed {
+		keys[strings.ToLower(entry.Headword)] = true
+	}
+	for _, entry := range parsed {
+		for _, sense := range entry.Senses {
+			report.Senses++
+			report.Examples += len(sense.Examples)
+			report.Supplements += len(sense.Supplements)
+			if sense.POS != "" {
+				report.POS[sense.POS]++
+			}
+		}
+		if entry.Level != "" {
+			report.Levels[entry.Level]++
+		}
+		for _, target := range splitLinks(entry.Definition).targets {
+			report.LinkTargets++
+			// 参照の解決と同じく、リンク先は小文字に統一した見出し語とそのまま照合する
+			if !keys[target] {
+				report.UnresolvedLinks++
+			}
+		}
+	}
+	return report
+}
+
+// addFinal は参照の解決・定義のまとめを終えたエントリから求まる統計情報を加える
+func (r *statsReport) addFinal(final []DictionaryEntry) {
+	r.FinalEntries = len(final)
+	totalLength := 0
+	for _, entry := range final {
+		totalLength += utf8.RuneCountInString(entry.Definition)
+	}
+	if len(final) > 0 {
+		r.AverageDefinitionLength = float64(totalLength) / float64(len(final))
+	}
+}
+
+// writeStatsReport は統計情報を format ("table" または "json") の形式で書き出す
+func writeStatsReport(w io.Writer, report statsReport, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(report)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		rows := []struct {
+			label string
+			value any
+		}{
+			{"入力ファイル", report.Input},
+			{"読み込んだ行数", report.Lines},
+			{"エントリ数 (パース直後)", report.ParsedEntries},
+			{"見出し語", report.Headwords},
+			{"除外した行", report.SkippedLines},
+			{"無視した行", report.IgnoredLines},
+			{"重複した見出し語", report.DuplicateHeadwords},
+			{"エントリ数 (最終)", report.FinalEntries},
+			{"語義", report.Senses},
+			{"用例", report.Examples},
+			{"補足説明", report.Supplements},
+			{"定義の平均文字数", fmt.Sprintf("%.1f", report.AverageDefinitionLength)},
+			{"変化形のリンク", report.LinkEntries},
+			{"リンクの参照", report.LinkTargets},
+			{"未解決のリンク", report.UnresolvedLinks},
+		}
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%v\n", tr(row.label), row.value)
+		}
+		for _, dist := range []struct {
+			label  string
+			counts map[string]int
+		}{{"品詞", report.POS}, {"レベル", report.Levels}} {
+			for _, key := range sortedByCount(dist.counts) {
+				fmt.Fprintf(tw, "%s: %s\t%d\n", tr(dist.label), key, dist.counts[key])
+			}
+		}
+		return tw.Flush()
+	default:
+		return errorf("未対応の出力形式です: %s (table または json を指定してください)", format)
+	}
+}
+
+// sortedByCount は件数の多い順 (同数の場合はキー順) にキーを並べて返す
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// runStatsCommand は stats サブコマンドを実行する
+// 英辞郎ファイルを変換と同じオプションで処理し、統計情報を出力する
+func runStatsCommand(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter stats [オプション]"))
+		fs.PrintDefaults()
+	}
+	inputFile := fs.String("i", "EIJIRO-1448.TXT", "集計する英辞郎ファイル名")
+	mergeStrategy := fs.String("merge", MergeConcat, "同じ見出し語の定義のまとめ方 (concat, numbered, pos, separate, split-pos)")
+	format := fs.String("format", "table", "出力形式 (table: 表, json: JSON)")
+	parseOptions := registerParseFlags(fs)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if *format != "table" && *format != "json" {
+		return errorf("未対応の出力形式です: %s (table または json を指定してください)", *format)
+	}
+	if err := validateMergeStrategy(*mergeStrategy); err != nil {
+		return err
+	}
+	entries, stats, err := parseEijiroWithStats(*inputFile, parseOptions())
+	if err != nil {
+		return errorf("英辞郎ファイルのパースに失敗しました: %w", err)
+	}
+	final := applyMergeStrategy(resolveAndMergeEntries(entries), *mergeStrategy)
+	return writeStatsReport(os.Stdout, buildStatsReport(*inputFile, entries, stats, final), *format)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestBuildStatsReport はパース結果から統計情報が集計されることを検証します。
+func TestBuildStatsReport(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■door {名} : 扉【レベル】1■・Close the door. : 扉を閉めて。",
+		"◆補足",
+		"■door {動} : 戸を付ける",
+		"■know {動} : 知っている【レベル】1【変化】《動》knows | knew",
+		"■Door {名} : ドア",
+		"■gone {形} : 過ぎ去った <→go>",
+	}, "\n"))
+	entries, stats, err := parseEijiroWithStats(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseEijiroWithStatsでエラーが発生しました: %v", err)
+	}
+	entries = append(entries, DictionaryEntry{Headword: "went", Definition: "@@@LINK=go"})
+	final := resolveAndMergeEntries(entries)
+
+	report := buildStatsReport("test.txt", entries, stats, final)
+	if report.Headwords != 4 || report.LinkEntries != 2 || report.DuplicateHeadwords != 1 || report.FinalEntries != 6 {
+		t.Errorf("エントリ数の集計が不正です: %+v", report)
+	}
+	if report.Senses != 5 || report.Examples != 1 || report.Supplements != 1 {
+		t.Errorf("語義の集計が不正です: %+v", report)
+	}
+	if report.LinkTargets != 3 || report.UnresolvedLinks != 1 {
+		t.Errorf("リンクの集計が不正です: %+v", report)
+	}
+	if expected := map[string]int{"名": 2, "動": 2, "形": 1}; !reflect.DeepEqual(report.POS, expected) {
+		t.Errorf("品詞の分布が不正です: %v", report.POS)
+	}
+	if expected := map[string]int{"1": 2}; !reflect.DeepEqual(report.Levels, expected) {
+		t.Errorf("レベルの分布が不正です: %v", report.Levels)
+	}
+}
+
+// TestWriteStatsReport は統計情報を表とJSONで出力できることを検証します。
+func TestWriteStatsReport(t *testing.T) {
+	report := statsReport{Input: "test.txt", FinalEntries: 3, POS: map[string]int{"名": 1, "動": 2}, Levels: map[string]int{}}
+
+	var buf bytes.Buffer
+	if err := writeStatsReport(&buf, report, "json"); err != nil {
+		t.Fatalf("JSONの出力でエラーが発生しました: %v", err)
+	}
+	var decoded statsReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, report) {
+		t.Errorf("JSONから元の統計情報を復元できません: %v %+v", err, decoded)
+	}
+
+	buf.Reset()
+	if err := writeStatsReport(&buf, report, "table"); err != nil {
+		t.Fatalf("表の出力でエラーが発生しました: %v", err)
+	}
+	table := buf.String()
+	if !strings.Contains(table, "エントリ数 (最終)") || strings.Index(table, "品詞: 動") > strings.Index(table, "品詞: 名") {
+		t.Errorf("表の内容が不正です:\n%s", table)
+	}
+
+	if err := writeStatsReport(&buf, report, "csv"); err == nil {
+		t.Errorf("未対応の形式でエラーが返されていません")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Sense は語義一つ分 (英辞郎の ■ の1行分) の構造化データ
+type Sense struct {
+	POS          string   `json:"pos,omitempty"`          // 品詞 (例: "名", "動")
+	Gloss        string   `json:"gloss"`                  // ラベルや補足を除いた訳語
+	Alternatives []string `json:"alternatives,omitempty"` // 言い換え ([…]) を展開した訳語の一覧
+	Labels       []string `json:"labels,omitempty"`       // その他のラベル (例: "大学入試")
+	Examples     []string `json:"examples,omitempty"`     // 用例 (■・)
+	Supplements  []string `json:"supplements,omitempty"`  // 補足説明 (◆)
+	Number       int      `json:"number,omitempty"`       // 一行に番号付きで並べた語義を分けた場合の番号 (1始まり。分けていない語義は0)
+	Domains      []string `json:"domains,omitempty"`      // 分野・用法のラベル (例: "医", "俗")
+}
+
+// 定義行を構造化するための正規表現
+var (
+	reSenseLabel = regexp.MustCompile(`【(.*?)】([^【】]*)`)
+	reCrossRef   = regexp.MustCompile(`<→(.*?)>`)
+)
+
+// applySense は定義行を解析して語義を追加し、発音やレベルなどエントリ全体の情報を補う
+// pos は見出し語から分離した品詞情報 (例: "{名}")、text は品詞情報と用例を除いた定義行
+// 削除オプションが有効な情報は、構造化データにも含めない
+func applySense(entry *DictionaryEntry, pos, text string, opts ParseOptions) {
+	sense := Sense{POS: strings.TrimSuffix(strings.TrimPrefix(pos, "{"), "}"), Domains: extractDomains(text)}
+
+	// 行内の補足説明 (◆以降) を分離する
+	if body, supplement, found := strings.Cut(text, "◆"); found {
+		text = body
+		if !opts.StripSupplement {
+			sense.Supplements = append(sense.Supplements, "◆"+supplement)
+		}
+	}
+
+	// ラベル (【…】) とその内容を振り分ける
+	body := text
+	if i := strings.Index(text, "【"); i >= 0 {
+		body = text[:i]
+	}
+	for _, match := range reSenseLabel.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		content := strings.Trim(match[2], " 、,")
+		switch name {
+		case "発音", "発音!", "発音！":
+			if !opts.StripPronunciation && entry.Pronunciation == "" && content != "" {
+				if opts.PronunciationIPA {
+					content = eijiroToIPA(content)
+				}
+				entry.Pronunciation = content
+			}
+		case "＠":
+			if !opts.StripKatakana && entry.Katakana == "" {
+				entry.Katakana = content
+			}
+		case "レベル":
+			if !opts.StripLevel && entry.Level == "" {
+				entry.Level = content
+			}
+		case "分節":
+			if !opts.StripSyllabification && entry.Syllabification == "" {
+				entry.Syllabification = content
+				entry.Hyphenation = splitSyllables(content)
+			}
+		case "変化":
+			// 変化形は同義語エントリとして別途処理する
+		default:
+			if !opts.StripOtherLabels {
+				sense.Labels = appendUnique(sense.Labels, name)
+			}
+		}
+	}
+
+	if !opts.StripPDICLink {
+		for _, match := range reCrossRef.FindAllStringSubmatch(body, -1) {
+			entry.CrossRefs = appendUnique(entry.CrossRefs, match[1])
+		}
+	} else {
+		body = rePDICLink.ReplaceAllString(body, "")
+	}
+	if opts.StripRuby {
+		body = reRuby.ReplaceAllString(body, "")
+	}
+	sense.Gloss = strings.TrimSpace(reTrimChars.ReplaceAllString(reSpaces.ReplaceAllString(body, " "), ""))
+	if opts.ExpandAlternatives && reAlternative.MatchString(sense.Gloss) {
+		sense.Alternatives = expandAlternatives(sense.Gloss)
+	}
+
+	if opts.SplitSenses {
+		entry.Senses = append(entry.Senses, splitSense(sense, opts)...)
+		return
+	}
+	entry.Senses = append(entry.Senses, sense)
+}
+
+// lastSense は最後に追加された語義を返す (語義がない場合は nil)
+func (e *DictionaryEntry) lastSense() *Sense {
+	if len(e.Senses) == 0 {
+		return nil
+	}
+	return &e.Senses[len(e.Senses)-1]
+}
+
+// cloneSenses は語義のスライスを、内部のスライスを含めて複製する
+func cloneSenses(senses []Sense) []Sense {
+	if senses == nil {
+		return nil
+	}
+	cloned := make([]Sense, len(senses))
+	for i, sense := range senses {
+		sense.Labels = slices.Clone(sense.Labels)
+		sense.Domains = slices.Clone(sense.Domains)
+		sense.Alternatives = slices.Clone(sense.Alternatives)
+		sense.Examples = slices.Clone(sense.Examples)
+		sense.Supplements = slices.Clone(sense.Supplements)
+		cloned[i] = sense
+	}
+	return cloned
+}
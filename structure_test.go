@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestStructuredEntry はパース時に語義・発音・レベルなどが構造化されることを検証します。
+func TestStructuredEntry(t *testing.T) {
+	path := writeEijiroTestFile(t, strings.Join([]string{
+		"■know {動} : 知っている、分かっている【大学入試】、【発音！】no'u、【＠】ノウ、【レベル】1、【分節】know■・I know. : 知っています。",
+		"■know {名} : 知識<→knowledge>◆古語",
+		"◆in the know の形で",
+	}, "\n"))
+
+	entries, err := parseEijiro(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	entry := entries[0]
+
+	expectedSenses := []Sense{
+		{POS: "動", Gloss: "知っている、分かっている", Labels: []string{"大学入試"}, Examples: []string{"I know. : 知っています。"}},
+		{POS: "名", Gloss: "知識<→knowledge>", Supplements: []string{"◆古語", "◆in the know の形で"}},
+	}
+	if !reflect.DeepEqual(entry.Senses, expectedSenses) {
+		t.Errorf("語義が不正です。\n期待値: %+v\n実際: %+v", expectedSenses, entry.Senses)
+	}
+	if entry.Pronunciation != "no'u" || entry.Katakana != "ノウ" || entry.Level != "1" || entry.Syllabification != "know" {
+		t.Errorf("エントリ全体の情報が不正です: %+v", entry)
+	}
+	if !reflect.DeepEqual(entry.CrossRefs, []string{"knowledge"}) {
+		t.Errorf("PDICリンクの参照先が不正です: %v", entry.CrossRefs)
+	}
+}
+
+// TestStructuredEntryRespectsStripOptions は削除オプションが構造化データにも反映されることを検証します。
+func TestStructuredEntryRespectsStripOptions(t *testing.T) {
+	path := writeEijiroTestFile(t, "■know {動} : 知っている｛し｝<→knowledge>【大学入試】、【発音！】no'u、【レベル】1■・I know. : 知っています。")
+	opts := ParseOptions{StripExamples: true, StripRuby: true, StripPDICLink: true, StripPronunciation: true, StripLevel: true, StripOtherLabels: true}
+
+	entries, err := parseEijiro(path, opts)
+	if err != nil {
+		t.Fatalf("parseEijiroでエラーが発生しました: %v", err)
+	}
+	expected := DictionaryEntry{
+		Headword:   "know",
+		Definition: entries[0].Definition,
+		Senses:     []Sense{{POS: "動", Gloss: "知っている"}},
+		SourceLine: 1,
+	}
+	if !reflect.DeepEqual(entries[0], expected) {
+		t.Errorf("期待値: %+v\n実際: %+v", expected, entries[0])
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+// subcommands は第1引数で指定するサブコマンドと、その実行関数の対応
+// サブコマンドが指定されなかった場合は、従来どおり英辞郎ファイルの変換を行う
+var subcommands = map[string]func(args []string) error{
+	"bench":     runBenchCommand,
+	"browse":    runBrowseCommand,
+	"diff":      runDiffCommand,
+	"gensample": runGenSampleCommand,
+	"lint":      runLintCommand,
+	"lookup":    runLookupCommand,
+	"serve":     runServeCommand,
+	"stats":     runStatsCommand,
+	"validate":  runValidateCommand,
+}
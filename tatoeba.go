@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tatoebaExamplePrefix は Tatoeba から補った用例の行の先頭に付ける印
+// 英辞郎の用例 (■・) と区別できるようにする
+const tatoebaExamplePrefix = "■〔Tatoeba〕"
+
+// tatoebaPair は Tatoeba の英日の対訳文一組
+type tatoebaPair struct {
+	English  string
+	Japanese string
+}
+
+// tatoebaIndex は対訳文を英文の単語から引くための索引
+// 単語ごとの対訳文の一覧は、学習者に読みやすい短い英文から順に並べておく
+type tatoebaIndex struct {
+	pairs   []tatoebaPair
+	tokens  [][]string       // 各対訳文の英文の単語 (小文字)
+	byToken map[string][]int // 単語から、その単語を含む対訳文の位置の一覧
+}
+
+// loadTatoebaPairs は Tatoeba からダウンロードした英日の対訳文 (UTF-8のTSV) を読み込む
+// 各行は "英文ID<TAB>英文<TAB>和文ID<TAB>和文" (Tatoeba の Sentence pairs の形式) または "英文<TAB>和文" とする
+func loadTatoebaPairs(path string) ([]tatoebaPair, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var pairs []tatoebaPair
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var pair tatoebaPair
+		switch fields := strings.Split(line, "\t"); len(fields) {
+		case 4:
+			pair = tatoebaPair{English: fields[1], Japanese: fields[3]}
+		case 2:
+			pair = tatoebaPair{English: fields[0], Japanese: fields[1]}
+		default:
+			return nil, errorf("%s:%d: 対訳文の行は \"英文ID<TAB>英文<TAB>和文ID<TAB>和文\" または \"英文<TAB>和文\" の形式で指定してください", path, lineNo)
+		}
+		pair.English = strings.TrimSpace(pair.English)
+		pair.Japanese = strings.TrimSpace(pair.Japanese)
+		if pair.English != "" && pair.Japanese != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// newTatoebaIndex は対訳文の索引を作成する
+func newTatoebaIndex(pairs []tatoebaPair) *tatoebaIndex {
+	sorted := make([]tatoebaPair, len(pairs))
+	copy(sorted, pairs)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return utf8.RuneCountInString(sorted[a].English) < utf8.RuneCountInString(sorted[b].English)
+	})
+
+	index := &tatoebaIndex{pairs: sorted, tokens: make([][]string, len(sorted)), byToken: make(map[string][]int)}
+	for i, pair := range sorted {
+		index.tokens[i] = tokenizeEnglish(pair.English)
+		seen := make(map[string]bool)
+		for _, token := range index.tokens[i] {
+			if !seen[token] {
+				seen[token] = true
+				index.byToken[token] = append(index.byToken[token], i)
+			}
+		}
+	}
+	return index
+}
+
+// tokenizeEnglish は英文を小文字の単語に分ける (単語の途中のアポストロフィやハイフンは単語の一部とする)
+// 例: "Don't touch the well-known door." -> ["don't", "touch", "the", "well-known", "door"]
+func tokenizeEnglish(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+}
+
+// find は見出し語の単語の並びをそのまま含む対訳文を、短い英文から順に最大 limit 件返す
+func (x *tatoebaIndex) find(headword string, limit int) []tatoebaPair {
+	words := tokenizeEnglish(headword)
+	// 記号 (~ など) を含む成句は単語の並びとして照合できないため対象外とする
+	if len(words) == 0 || strings.Join(words, " ") != strings.ToLower(strings.TrimSpace(headword)) {
+		return nil
+	}
+	var found []tatoebaPair
+	for _, i := range x.byToken[words[0]] {
+		if containsSequence(x.tokens[i], words) {
+			found = append(found, x.pairs[i])
+			if len(found) >= limit {
+				break
+			}
+		}
+	}
+	return found
+}
+
+// containsSequence は tokens が words の並びを連続して含むかどうかを返す
+func containsSequence(tokens, words []string) bool {
+	for start := 0; start+len(words) <= len(tokens); start++ {
+		if slices.Equal(tokens[start:start+len(words)], words) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOwnExamples はエントリが英辞郎の用例を持つかどうかを返す (リンク先の定義の用例も含む)
+func hasOwnExamples(entry DictionaryEntry) bool {
+	if len(entry.Examples) > 0 {
+		return true
+	}
+	for _, sense := range entry.Senses {
+		if len(sense.Examples) > 0 {
+			return true
+		}
+	}
+	for _, line := range strings.Split(entry.Definition, "\n") {
+		if strings.HasPrefix(line, "■") {
+			return true
+		}
+	}
+	return false
+}
+
+// attachTatoebaExamples は用例のないエントリに、見出し語を含む Tatoeba の対訳文を最大 limit 件ずつ用例として加える
+// 用例は tatoebaExamplePrefix を付けて見出し語自身の定義の末尾 (リンク先の定義との区切りの前) に追記し、
+// 英辞郎の用例と同じく英文と和文を全角スペースで区切る
+func attachTatoebaExamples(entries []DictionaryEntry, index *tatoebaIndex, limit int) (attached int) {
+	for i := range entries {
+		if hasOwnExamples(entries[i]) {
+			continue
+		}
+		pairs := index.find(entries[i].Headword, limit)
+		if len(pairs) == 0 {
+			continue
+		}
+		lines := make([]string, len(pairs))
+		for j, pair := range pairs {
+			lines[j] = tatoebaExamplePrefix + pair.English + "　" + pair.Japanese
+		}
+		examples := strings.Join(lines, "\n")
+
+		own, linked, hasLinked := strings.Cut(entries[i].Definition, "\n"+mergeSeparator+"\n")
+		def := own + "\n" + examples
+		if hasLinked {
+			def += "\n" + mergeSeparator + "\n" + linked
+		}
+		entries[i].Definition = def
+		attached++
+	}
+	return attached
+}
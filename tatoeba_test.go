@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadTatoebaPairs は Tatoeba の対訳文の2つの形式の読み込みと、形式の誤りの検出を検証します。
+func TestLoadTatoebaPairs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected []tatoebaPair
+		wantErr  bool
+	}{
+		{"Sentence pairsの形式", "1276\tLet's try something.\t4727\t何かしてみましょう。\n", []tatoebaPair{{"Let's try something.", "何かしてみましょう。"}}, false},
+		{"英文と和文のみ", "Open the door.\tドアを開けて。\r\n\n", []tatoebaPair{{"Open the door.", "ドアを開けて。"}}, false},
+		{"列の数が違う", "Open the door.\n", nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pairs.tsv")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			pairs, err := loadTatoebaPairs(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("エラーの有無が違います: %v", err)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(pairs, tc.expected) {
+				t.Errorf("期待値: %q, 実際: %q", tc.expected, pairs)
+			}
+		})
+	}
+}
+
+// TestAttachTatoebaExamples は用例のないエントリにのみ、見出し語を含む短い対訳文が印を付けて加わることを検証します。
+func TestAttachTatoebaExamples(t *testing.T) {
+	index := newTatoebaIndex([]tatoebaPair{
+		{"Please open the door for me.", "ドアを開けてください。"},
+		{"Open the door.", "ドアを開けて。"},
+		{"The doorbell rang.", "玄関のベルが鳴った。"},
+		{"Never give up.", "決して諦めるな。"},
+		{"Knock on the door.", "ドアをノックして。"},
+	})
+	entries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉"},
+		{Headword: "give up", Definition: "諦める"},
+		{Headword: "know", Definition: "{動} 知っている\n■I know him.　彼を知っている。"},
+		{Headword: "doors", Definition: "{名} doorの複数形\n" + mergeSeparator + "\n{名} 扉"},
+		{Headword: "account for ~", Definition: "～を説明する"},
+	}
+
+	if attached := attachTatoebaExamples(entries, index, 2); attached != 2 {
+		t.Errorf("用例を加えたエントリ数が違います: %d", attached)
+	}
+	expected := []string{
+		// 短い英文から順に、単語として一致するもののみを加える (doorbell は一致しない)
+		"{名} 扉\n■〔Tatoeba〕Open the door.　ドアを開けて。\n■〔Tatoeba〕Knock on the door.　ドアをノックして。",
+		"諦める\n■〔Tatoeba〕Never give up.　決して諦めるな。",
+		"{動} 知っている\n■I know him.　彼を知っている。",
+		"{名} doorの複数形\n" + mergeSeparator + "\n{名} 扉",
+		"～を説明する",
+	}
+	for i, entry := range entries {
+		if entry.Definition != expected[i] {
+			t.Errorf("%s: 期待値: %q, 実際: %q", entry.Headword, expected[i], entry.Definition)
+		}
+	}
+}
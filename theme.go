@@ -0,0 +1,37 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"regexp"
+)
+
+// themeFS はHTML出力用のスタイルシート
+//
+//go:embed themes/*.css
+var themeFS embed.FS
+
+// reAccentColor はアクセントカラーとして受け付ける値 (#rgb または #rrggbb)
+var reAccentColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// defaultTheme はテーマが指定されていない場合に使うテーマ
+const defaultTheme = "light"
+
+// loadThemeCSS はテーマ名に対応するスタイルシートを返す
+// accentColor が空でない場合は、アクセントカラーの変数を上書きする
+func loadThemeCSS(theme, accentColor string) ([]byte, error) {
+	if theme == "" {
+		theme = defaultTheme
+	}
+	css, err := themeFS.ReadFile("themes/" + theme + ".css")
+	if err != nil {
+		return nil, errorf("未対応のテーマです: %s (light または dark を指定してください)", theme)
+	}
+	if accentColor != "" {
+		if !reAccentColor.MatchString(accentColor) {
+			return nil, errorf("アクセントカラーの指定が不正です: %s (#rrggbb の形式で指定してください)", accentColor)
+		}
+		css = append(css, fmt.Sprintf(":root { --accent: %s; }\n", accentColor)...)
+	}
+	return css, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadThemeCSS はテーマとアクセントカラーの指定を検証します。
+func TestLoadThemeCSS(t *testing.T) {
+	testCases := []struct {
+		name        string
+		theme       string
+		accentColor string
+		contains    string
+		wantErr     bool
+	}{
+		{name: "既定はライトテーマ", theme: "", contains: "ライトテーマ"},
+		{name: "ダークテーマ", theme: "dark", contains: "ダークテーマ"},
+		{name: "アクセントカラーの上書き", theme: "dark", accentColor: "#ff8800", contains: "--accent: #ff8800;"},
+		{name: "未対応のテーマ", theme: "sepia", wantErr: true},
+		{name: "不正なアクセントカラー", theme: "light", accentColor: "red;}", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			css, err := loadThemeCSS(tc.theme, tc.accentColor)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("期待するエラーの有無: %v, 実際のエラー: %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && !strings.Contains(string(css), tc.contains) {
+				t.Errorf("スタイルシートに %q が含まれていません:\n%s", tc.contains, css)
+			}
+		})
+	}
+}
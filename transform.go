@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// EntryTransformer はパースとマージを終えたエントリを、書き出す前に加工する拡張ポイント
+// 独自の整形や情報の付加を行う場合に ConvertConfig.Transformers に登録する
+// 返したエントリがそのまま書き出されるため、エントリの追加や削除も行える
+type EntryTransformer interface {
+	Transform(entries []DictionaryEntry) ([]DictionaryEntry, error)
+}
+
+// EntryTransformerFunc は関数を EntryTransformer として扱うためのアダプタ
+type EntryTransformerFunc func(entries []DictionaryEntry) ([]DictionaryEntry, error)
+
+// Transform は f(entries) を呼び出す
+func (f EntryTransformerFunc) Transform(entries []DictionaryEntry) ([]DictionaryEntry, error) {
+	return f(entries)
+}
+
+// CommandTransformer は外部コマンドにエントリを通して加工する EntryTransformer
+// エントリはJSONL出力と同じ形式(schema/entry.schema.json)で標準入力に渡され、
+// コマンドは加工したエントリを同じ形式で標準出力に書き出す
+// コマンドの標準エラー出力はそのまま表示する
+type CommandTransformer struct {
+	Command string // シェル経由で実行するコマンド (例: "jq -c 'select(.level != \"\")'")
+}
+
+// Transform は外部コマンドを実行し、その出力をエントリとして読み込む
+func (c CommandTransformer) Transform(entries []DictionaryEntry) ([]DictionaryEntry, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", c.Command)
+	} else {
+		cmd = exec.Command("sh", "-c", c.Command)
+	}
+	cmd.Stderr = os.Stderr
+
+	var input bytes.Buffer
+	encoder := json.NewEncoder(&input)
+	encoder.SetEscapeHTML(false)
+	for _, entry := range entries {
+		if err := encoder.Encode(newJSONLRecord(entry)); err != nil {
+			return nil, errorf("'%s' のJSON変換に失敗: %w", entry.Headword, err)
+		}
+	}
+	cmd.Stdin = &input
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errorf("フィルターコマンドを起動できません: %w", err)
+	}
+
+	transformed, readErr := readJSONLEntries(stdout)
+	if readErr != nil {
+		// 出力を読み切らずに待つとコマンドが終了しないことがあるため、残りを読み捨てる
+		io.Copy(io.Discard, stdout)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, errorf("フィルターコマンドが失敗しました: %w", err)
+	}
+	if readErr != nil {
+		return nil, errorf("フィルターコマンドの出力を読み込めません: %w", readErr)
+	}
+	return transformed, nil
+}
+
+// readJSONLEntries はJSONL形式のエントリを読み込む
+func readJSONLEntries(r io.Reader) ([]DictionaryEntry, error) {
+	var entries []DictionaryEntry
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for line := 1; ; line++ {
+		var record jsonlRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, errorf("%d件目: %w", line, err)
+		}
+		if record.Headword == "" {
+			return nil, errorf("%d件目: 見出し語がありません", line)
+		}
+		entries = append(entries, record.entry())
+	}
+}
+
+// applyTransformers は登録された EntryTransformer を順に適用する
+func applyTransformers(entries []DictionaryEntry, transformers []EntryTransformer) ([]DictionaryEntry, error) {
+	for _, transformer := range transformers {
+		var err error
+		if entries, err = transformer.Transform(entries); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
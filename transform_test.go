@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestApplyTransformers は登録した加工処理が順に適用されることを検証します。
+func TestApplyTransformers(t *testing.T) {
+	entries := []DictionaryEntry{{Headword: "door", Definition: "{名} 扉"}, {Headword: "gate", Definition: "{名} 門"}}
+	upper := EntryTransformerFunc(func(entries []DictionaryEntry) ([]DictionaryEntry, error) {
+		for i := range entries {
+			entries[i].Headword = strings.ToUpper(entries[i].Headword)
+		}
+		return entries, nil
+	})
+	dropGate := EntryTransformerFunc(func(entries []DictionaryEntry) ([]DictionaryEntry, error) {
+		return entries[:1], nil
+	})
+
+	got, err := applyTransformers(entries, []EntryTransformer{upper, dropGate})
+	if err != nil {
+		t.Fatalf("applyTransformersでエラーが発生しました: %v", err)
+	}
+	expected := []DictionaryEntry{{Headword: "DOOR", Definition: "{名} 扉"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %+v, 実際: %+v", expected, got)
+	}
+
+	failing := EntryTransformerFunc(func([]DictionaryEntry) ([]DictionaryEntry, error) {
+		return nil, errors.New("加工に失敗")
+	})
+	if _, err := applyTransformers(entries, []EntryTransformer{failing}); err == nil {
+		t.Errorf("加工処理のエラーが返されていません")
+	}
+}
+
+// TestCommandTransformer は外部コマンドを通したエントリの加工を検証します。
+func TestCommandTransformer(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh が見つからないため、テストをスキップします")
+	}
+	entries := []DictionaryEntry{
+		{Headword: "door", Definition: "{名} 扉", Level: "1"},
+		{Headword: "gate", Definition: "{名} 門"},
+	}
+
+	testCases := []struct {
+		name      string
+		command   string
+		headwords []string
+		wantErr   bool
+	}{
+		{name: "そのまま返す", command: "cat", headwords: []string{"door", "gate"}},
+		{name: "エントリを絞り込む", command: "grep '\"level\"'", headwords: []string{"door"}},
+		{name: "コマンドの失敗", command: "cat > /dev/null; exit 3", wantErr: true},
+		{name: "不正な出力", command: "echo '{'", wantErr: true},
+		{name: "見出し語の欠落", command: "echo '{\"definition\":\"扉\"}'", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CommandTransformer{Command: tc.command}.Transform(entries)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("期待するエラーの有無: %v, 実際のエラー: %v", tc.wantErr, err)
+			}
+			var headwords []string
+			for _, entry := range got {
+				headwords = append(headwords, entry.Headword)
+			}
+			if !reflect.DeepEqual(headwords, tc.headwords) {
+				t.Errorf("期待値: %v, 実際: %v", tc.headwords, headwords)
+			}
+		})
+	}
+}
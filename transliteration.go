@@ -0,0 +1,56 @@
+package main
+
+import (
+	"html"
+	"strings"
+)
+
+// transliteration は見出し語の読み上げに使う発音情報 (カタカナ発音とIPA)
+type transliteration struct {
+	Headword string
+	Katakana string
+	IPA      string
+}
+
+// entryTransliteration はエントリの発音情報を返す
+// 発音記号は英辞郎の表記のままでもIPAに変換済みでもよい (変換済みの場合はそのまま残る)
+func entryTransliteration(entry DictionaryEntry) transliteration {
+	t := transliteration{Headword: entry.Headword, Katakana: entry.Katakana}
+	if entry.Pronunciation != "" {
+		t.IPA = eijiroToIPA(entry.Pronunciation)
+	}
+	return t
+}
+
+// writeTransliterationFile は「見出し語<TAB>カタカナ発音<TAB>IPA」の対応表を見出し語順に書き出す
+// 読み上げソフトやスクリーンリーダーの発音辞書に取り込むためのもので、
+// カタカナ発音と発音記号のどちらもない見出し語は含めない
+func writeTransliterationFile(path string, entries []DictionaryEntry) error {
+	var lines []string
+	for _, entry := range sortEntriesForStarDict(entries) {
+		t := entryTransliteration(entry)
+		if t.Katakana == "" && t.IPA == "" {
+			continue
+		}
+		lines = append(lines, strings.Join([]string{t.Headword, t.Katakana, t.IPA}, "\t"))
+	}
+	return writeLines(path, lines)
+}
+
+// renderAccessiblePronunciation は発音情報を、支援技術が発音として扱えるHTML要素にする
+// 読み上げ時に記号の羅列にならないよう、IPAには lang="en-fonipa" を付けて言語を明示する
+// 発音情報がない場合は空文字列を返す
+func renderAccessiblePronunciation(entry DictionaryEntry, tag string) string {
+	t := entryTransliteration(entry)
+	var parts []string
+	if t.Katakana != "" {
+		parts = append(parts, `<span class="katakana">`+html.EscapeString(t.Katakana)+"</span>")
+	}
+	if t.IPA != "" {
+		parts = append(parts, `<span class="ipa" lang="en-fonipa">/`+html.EscapeString(t.IPA)+"/</span>")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "<" + tag + ` class="pronunciation" role="note" aria-label="発音">` + strings.Join(parts, " ") + "</" + tag + ">"
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWriteTransliterationFile は発音の対応表が見出し語順に書き出されることを検証します。
+func TestWriteTransliterationFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transliteration.tsv")
+	entries := []DictionaryEntry{
+		{Headword: "know", Katakana: "ノウ", Pronunciation: "no'u"},
+		{Headword: "door", Katakana: "ドー"},
+		{Headword: "NASA"},
+	}
+	if err := writeTransliterationFile(path, entries); err != nil {
+		t.Fatalf("writeTransliterationFileでエラーが発生しました: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "door\tドー\t\nknow\tノウ\tnóu\n"
+	if string(data) != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, string(data))
+	}
+}
+
+// TestRenderAccessiblePronunciation はHTML形式の定義に読み上げ用の発音情報が置かれることを検証します。
+func TestRenderAccessiblePronunciation(t *testing.T) {
+	entry := DictionaryEntry{Headword: "know", Definition: "{動} 知っている", Katakana: "ノウ", Pronunciation: "no'u"}
+
+	got := htmlRenderer{accessiblePronunciation: true}.Render(entry)
+	expected := `<span class="pronunciation" role="note" aria-label="発音"><span class="katakana">ノウ</span> <span class="ipa" lang="en-fonipa">/nóu/</span></span><br>{動} 知っている`
+	if got != expected {
+		t.Errorf("期待値: %q, 実際: %q", expected, got)
+	}
+
+	if got := (htmlRenderer{}).Render(entry); strings.Contains(got, "pronunciation") {
+		t.Errorf("オプションが無効なのに発音情報が出力されています: %q", got)
+	}
+	if got := renderAccessiblePronunciation(DictionaryEntry{Headword: "NASA"}, "p"); got != "" {
+		t.Errorf("発音情報がない場合は空のはずです: %q", got)
+	}
+}
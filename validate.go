@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxStarDictWordLength は StarDict の見出し語の最大バイト数 (終端のNULを含まない)
+const maxStarDictWordLength = 255
+
+// maxReportedProblems は一つの辞書について表示する問題の最大件数
+const maxReportedProblems = 20
+
+// validateStarDictBook は読み込んだ辞書の内容を検証し、見つかった問題の一覧を返す
+// 検証するのは、.ifo の件数・ファイルサイズと実際の内容の一致、索引の並び順、
+// 定義の位置が .dict の範囲内にあること、見出し語と定義がUTF-8として正しいことなど
+func validateStarDictBook(book *StarDictBook) []string {
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, sprintf(format, args...))
+	}
+
+	for _, key := range []string{"version", "bookname", "wordcount", "idxfilesize"} {
+		if _, ok := book.Info[key]; !ok {
+			report(".ifo に必須の項目 '%s' がありません", key)
+		}
+	}
+	if wordCount, found, err := book.infoInt("wordcount"); err != nil {
+		report(".ifo: %v", err)
+	} else if found && wordCount != len(book.Words) {
+		report(".ifo の wordcount (%d) が .idx の見出し語の数 (%d) と一致しません", wordCount, len(book.Words))
+	}
+	if idxFileSize, found, err := book.infoInt("idxfilesize"); err != nil {
+		report(".ifo: %v", err)
+	} else if found && idxFileSize != book.IdxSize {
+		report(".ifo の idxfilesize (%d) が .idx の大きさ (%d) と一致しません", idxFileSize, book.IdxSize)
+	}
+	if offsetBits, found, err := book.infoInt("idxoffsetbits"); err != nil {
+		report(".ifo: %v", err)
+	} else if found && offsetBits != 32 && offsetBits != 64 {
+		report(".ifo の idxoffsetbits (%d) は 32 または 64 である必要があります", offsetBits)
+	}
+	if book.Info["idxoffsetbits"] != "64" && int64(len(book.dict)) > math.MaxUint32 {
+		report(".dict の大きさ (%d) が32ビットの位置で表せる範囲を超えています (.ifo に idxoffsetbits=64 がありません)", len(book.dict))
+	}
+	if synWordCount, _, err := book.infoInt("synwordcount"); err != nil {
+		report(".ifo: %v", err)
+	} else if synWordCount != len(book.Synonyms) {
+		report(".ifo の synwordcount (%d) が .syn の同義語の数 (%d) と一致しません", synWordCount, len(book.Synonyms))
+	}
+
+	for i, w := range book.Words {
+		switch {
+		case w.Word == "":
+			report(".idx の%d件目: 見出し語が空です", i+1)
+		case len(w.Word) > maxStarDictWordLength:
+			report(".idx の%d件目: 見出し語 '%s' が%dバイトを超えています", i+1, w.Word, maxStarDictWordLength)
+		case !utf8.ValidString(w.Word):
+			report(".idx の%d件目: 見出し語 %q が正しいUTF-8ではありません", i+1, w.Word)
+		}
+		if i > 0 && stardictStrcmp(book.Words[i-1].Word, w.Word) > 0 {
+			report(".idx の%d件目: '%s' が直前の '%s' より前に並ぶべき位置にあります", i+1, w.Word, book.Words[i-1].Word)
+		}
+		def, err := book.Definition(i)
+		if err != nil {
+			report(".idx の%d件目: %v", i+1, err)
+		} else if !utf8.Valid(def) {
+			report(".idx の%d件目: '%s' の定義が正しいUTF-8ではありません", i+1, w.Word)
+		} else if _, err := book.DefinitionFields(i); err != nil {
+			report(".idx の%d件目: %v", i+1, err)
+		}
+	}
+
+	for i, syn := range book.Synonyms {
+		if int(syn.Index) >= len(book.Words) {
+			report(".syn の%d件目: '%s' の参照先 (%d) が見出し語の数 (%d) を超えています", i+1, syn.Word, syn.Index, len(book.Words))
+		}
+		if i > 0 && stardictStrcmp(book.Synonyms[i-1].Word, syn.Word) > 0 {
+			report(".syn の%d件目: '%s' が直前の '%s' より前に並ぶべき位置にあります", i+1, syn.Word, book.Synonyms[i-1].Word)
+		}
+	}
+	return problems
+}
+
+// runValidateCommand は validate サブコマンドを実行する
+// 指定された .ifo ファイルごとに辞書を検証し、問題が見つかった場合はエラーを返す
+func runValidateCommand(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), tr("使い方: eijiro-converter validate <辞書名.ifo>..."))
+		fs.PrintDefaults()
+	}
+	localizeFlags(fs)
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errorf("検証する .ifo ファイルを指定してください")
+	}
+
+	var failed []string
+	for _, path := range fs.Args() {
+		book, err := openStarDict(path)
+		if err != nil {
+			log.Print(sprintf("%s: %v", path, err))
+			failed = append(failed, path)
+			continue
+		}
+		problems := validateStarDictBook(book)
+		if len(problems) == 0 {
+			log.Print(sprintf("%s: 問題は見つかりませんでした (%d語)", path, len(book.Words)))
+			continue
+		}
+		failed = append(failed, path)
+		for i, problem := range problems {
+			if i == maxReportedProblems {
+				log.Print(sprintf("%s: ほか%d件の問題があります", path, len(problems)-maxReportedProblems))
+				break
+			}
+			log.Print(sprintf("%s: %s", path, problem))
+		}
+	}
+	if len(failed) > 0 {
+		return errorf("問題のある辞書があります: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
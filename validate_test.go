@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// installFakeDictzip は dictzip の代わりに gzip で .dict.dz を作るコマンドを PATH の先頭に置きます。
+// dictzip形式はgzipと互換のため、読み込み側の検証にはこれで十分です。
+func installFakeDictzip(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("gzip"); err != nil {
+		t.Skip("gzip が見つからないため、テストをスキップします")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\ngzip -c \"$1\" > \"$1.dz\" && rm \"$1\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "dictzip"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// writeTestBook はテスト用の辞書を書き出し、.ifo ファイルのパスを返します。
+func writeTestBook(t *testing.T, entries []DictionaryEntry) string {
+	t.Helper()
+	installFakeDictzip(t)
+	dir := t.TempDir()
+	if err := writeStarDictFiles(dir, "Test", "1.0", entries, WriteOptions{}); err != nil {
+		t.Fatalf("writeStarDictFilesでエラーが発生しました: %v", err)
+	}
+	return filepath.Join(dir, "Test.ifo")
+}
+
+// TestValidateStarDictBook は書き出した辞書が検証を通り、壊れた辞書の問題が検出されることを検証します。
+func TestValidateStarDictBook(t *testing.T) {
+	ifoPath := writeTestBook(t, []DictionaryEntry{
+		{Headword: "know", Definition: "{動} 知っている", Keywords: []string{"knew"}},
+		{Headword: "door", Definition: "{名} 扉"},
+	})
+
+	book, err := openStarDict(ifoPath)
+	if err != nil {
+		t.Fatalf("openStarDictでエラーが発生しました: %v", err)
+	}
+	if problems := validateStarDictBook(book); len(problems) != 0 {
+		t.Fatalf("正しい辞書で問題が報告されました: %v", problems)
+	}
+	if def, _ := book.Definition(1); string(def) != "{動} 知っている" {
+		t.Errorf("定義が正しく読み込まれていません: %q", def)
+	}
+
+	testCases := []struct {
+		name    string
+		corrupt func(b *StarDictBook)
+		problem string
+	}{
+		{
+			name:    "索引の並び順",
+			corrupt: func(b *StarDictBook) { b.Words[0], b.Words[1] = b.Words[1], b.Words[0] },
+			problem: "前に並ぶべき位置",
+		},
+		{
+			name:    "sametypesequenceの項目の終端",
+			corrupt: func(b *StarDictBook) { b.Info["sametypesequence"] = "tg" },
+			problem: "終端がありません",
+		},
+		{
+			name:    "見出し語の数の不一致",
+			corrupt: func(b *StarDictBook) { b.Info["wordcount"] = "3" },
+			problem: "wordcount (3)",
+		},
+		{
+			name:    "索引の大きさの不一致",
+			corrupt: func(b *StarDictBook) { b.IdxSize++ },
+			problem: "idxfilesize",
+		},
+		{
+			name:    "位置のビット数",
+			corrupt: func(b *StarDictBook) { b.Info["idxoffsetbits"] = "16" },
+			problem: "idxoffsetbits (16)",
+		},
+		{
+			name:    "範囲外の定義",
+			corrupt: func(b *StarDictBook) { b.Words[1].Size = 1000 },
+			problem: ".dict の大きさ",
+		},
+		{
+			name:    "不正なUTF-8",
+			corrupt: func(b *StarDictBook) { b.dict[0] = 0xff },
+			problem: "UTF-8",
+		},
+		{
+			name:    "範囲外の同義語",
+			corrupt: func(b *StarDictBook) { b.Synonyms[0].Index = 5 },
+			problem: ".syn の1件目",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			book, err := openStarDict(ifoPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.corrupt(book)
+			problems := strings.Join(validateStarDictBook(book), "\n")
+			if !strings.Contains(problems, tc.problem) {
+				t.Errorf("'%s' を含む問題が報告されていません: %q", tc.problem, problems)
+			}
+		})
+	}
+}
+
+// TestIfoMetadata は指定した辞書の情報と作成日が .ifo に書き出され、未指定の場合は既定値になる (作成日は変換した日になる) ことを検証します。
+func TestIfoMetadata(t *testing.T) {
+	installFakeDictzip(t)
+	entries := []DictionaryEntry{{Headword: "door", Definition: "扉"}}
+	testCases := []struct {
+		name     string
+		wopts    WriteOptions
+		expected map[string]string
+	}{
+		{"指定なし", WriteOptions{}, map[string]string{
+			"author": defaultIfoAuthor, "description": defaultIfoDescription, "email": "", "website": "", "date": time.Now().Format(ifoDateFormat),
+		}},
+		{"指定あり", WriteOptions{Author: "山田", Email: "yamada@example.com", Website: "https://example.com/", Description: "英辞郎\n第十版", BuildTime: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)}, map[string]string{
+			"author": "山田", "description": "英辞郎<br>第十版", "email": "yamada@example.com", "website": "https://example.com/", "date": "2026.10.16",
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := writeStarDictFiles(dir, "Test", "1.0", entries, tc.wopts); err != nil {
+				t.Fatalf("writeStarDictFilesでエラーが発生しました: %v", err)
+			}
+			info, err := readIfoFile(filepath.Join(dir, "Test.ifo"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tc.expected {
+				if info[key] != want {
+					t.Errorf("%s: 期待値: %q, 実際: %q", key, want, info[key])
+				}
+			}
+			if _, err := time.Parse(ifoDateFormat, info["date"]); err != nil {
+				t.Errorf("date が作成日になっていません: %q", info["date"])
+			}
+		})
+	}
+}
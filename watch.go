@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultWatchInterval は -watch で入力ファイルの変更を確認する間隔の既定値
+const defaultWatchInterval = time.Second
+
+// fileState は変更を検出するために記録する、ファイルの大きさと更新日時
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// watchedPaths は変換の入力となるファイルとディレクトリの一覧を返す
+// 英辞郎ファイルのほか、頻度リストや JMdict などの、変換の結果に影響する補助的な入力を含む
+func watchedPaths(cfg ConvertConfig) []string {
+	var paths []string
+	for _, path := range []string{cfg.InputFile, cfg.FrequencyList, cfg.TatoebaFile, cfg.JMdictFile, cfg.PitchAccentFile, cfg.AudioDir, cfg.ResourceDir} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// snapshotFiles は各ファイル (ディレクトリの場合はその中のすべてのファイル) の状態を記録する
+// 存在しないファイルは記録しないため、削除や作成も変更として検出できる
+func snapshotFiles(paths []string) map[string]fileState {
+	states := make(map[string]fileState)
+	for _, path := range paths {
+		filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil // 読めないファイルは記録せず、読めるようになった時点で変更として扱う
+			}
+			if info, err := d.Info(); err == nil {
+				states[p] = fileState{size: info.Size(), modTime: info.ModTime()}
+			}
+			return nil
+		})
+	}
+	return states
+}
+
+// changedFiles は二つの記録の間で、追加・削除・変更されたファイルを名前順に返す
+func changedFiles(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		if old, ok := before[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// watchConversion は変換を実行した後、入力ファイルの変更を interval ごとに確認し、変更があれば変換し直す
+// 英辞郎ファイルが変わっていない場合は、前回のパース結果のキャッシュ (-cache、指定がない場合は一時ファイル) から読み込み、パースを省略する
+// 保存の途中のファイルを読まないよう、変更を検出した後、状態が interval の間変わらなくなるのを待ってから変換する
+// 変換に失敗しても監視は続け、ctx が取り消されるまで戻らない
+func watchConversion(ctx context.Context, cfg ConvertConfig, interval time.Duration, convert func(context.Context, ConvertConfig) (RunSummary, error)) error {
+	if cfg.FromCache != "" || cfg.Patch != "" {
+		return errorf("-watch は -from-cache や -patch と同時に指定できません")
+	}
+	if interval <= 0 {
+		return errorf("-watch-interval は正の時間で指定してください: %s", interval)
+	}
+	cacheFile := cfg.CacheFile
+	if cacheFile == "" {
+		f, err := os.CreateTemp("", "eijiro-watch-*.cache")
+		if err != nil {
+			return errorf("パース結果のキャッシュの作成に失敗しました: %w", err)
+		}
+		f.Close()
+		cacheFile = f.Name()
+		defer os.Remove(cacheFile)
+	}
+
+	paths := watchedPaths(cfg)
+	states := snapshotFiles(paths)
+	cached := false // 前回の変換で、今の英辞郎ファイルのパース結果をキャッシュに書き出せたかどうか
+	run := func(reparse bool) {
+		runCfg := cfg
+		if reparse || !cached {
+			// 読み込みを打ち切った場合などは書き出されないため、古いキャッシュを使わないよう先に削除しておく
+			os.Remove(cacheFile)
+			runCfg.CacheFile = cacheFile
+		} else {
+			runCfg.CacheFile, runCfg.FromCache = "", cacheFile
+		}
+		if _, err := convert(ctx, runCfg); err != nil && ctx.Err() == nil {
+			logger.Error(sprintf("変換に失敗しました: %v", err), "event", "watch_failed")
+		}
+		_, err := os.Stat(cacheFile)
+		cached = err == nil
+	}
+	run(true)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Info(sprintf("入力ファイルの変更を監視しています (Ctrl-C で終了): %s", strings.Join(paths, ", ")), "event", "watching")
+	var pending []string // 検出したが、まだ状態が落ち着いていない変更
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		next := snapshotFiles(paths)
+		changed := changedFiles(states, next)
+		states = next
+		if len(changed) > 0 {
+			pending = appendUnique(pending, changed...)
+			continue
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		logger.Info(sprintf("%s が変更されたため、変換し直します。", strings.Join(pending, ", ")), "event", "watch_changed")
+		run(slices.Contains(pending, cfg.InputFile))
+		pending = nil
+	}
+}
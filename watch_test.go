@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestChangedFiles は追加・削除・変更されたファイルが名前順に検出されることを検証します。
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.mp3", "a")
+	write("b.mp3", "b")
+	before := snapshotFiles([]string{dir, filepath.Join(dir, "missing.txt")})
+	if len(before) != 2 {
+		t.Fatalf("記録したファイルの数が違います: %v", before)
+	}
+	if changed := changedFiles(before, snapshotFiles([]string{dir})); changed != nil {
+		t.Errorf("変更のない状態で変更が検出されました: %v", changed)
+	}
+
+	write("b.mp3", "bb")
+	write("c.mp3", "c")
+	os.Remove(filepath.Join(dir, "a.mp3"))
+	got := changedFiles(before, snapshotFiles([]string{dir}))
+	expected := []string{filepath.Join(dir, "a.mp3"), filepath.Join(dir, "b.mp3"), filepath.Join(dir, "c.mp3")}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期待値: %v, 実際: %v", expected, got)
+	}
+}
+
+// TestWatchConversion は補助的な入力の変更ではキャッシュから、英辞郎ファイルの変更ではパースし直して変換されることを検証します。
+func TestWatchConversion(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "eijiro.txt")
+	freq := filepath.Join(dir, "freq.txt")
+	for _, path := range []string{input, freq} {
+		if err := os.WriteFile(path, []byte("0"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var runs []ConvertConfig
+	ran := make(chan struct{}, 10)
+	convert := func(ctx context.Context, cfg ConvertConfig) (RunSummary, error) {
+		if cfg.CacheFile != "" {
+			os.WriteFile(cfg.CacheFile, []byte("cache"), 0644)
+		}
+		mu.Lock()
+		runs = append(runs, cfg)
+		mu.Unlock()
+		ran <- struct{}{}
+		return RunSummary{}, nil
+	}
+	wait := func() {
+		t.Helper()
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatal("変換が実行されませんでした")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watchConversion(ctx, ConvertConfig{InputFile: input, FrequencyList: freq}, 10*time.Millisecond, convert)
+	}()
+	wait()
+	os.WriteFile(freq, []byte("1 2"), 0644)
+	wait()
+	os.WriteFile(input, []byte("1 2"), 0644)
+	wait()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watchConversionでエラーが発生しました: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(runs) != 3 {
+		t.Fatalf("変換の回数が違います: %d", len(runs))
+	}
+	if runs[0].CacheFile == "" || runs[0].FromCache != "" {
+		t.Errorf("最初の変換でパースしていません: %+v", runs[0])
+	}
+	if runs[1].FromCache != runs[0].CacheFile || runs[1].CacheFile != "" {
+		t.Errorf("頻度リストの変更でキャッシュを使っていません: %+v", runs[1])
+	}
+	if runs[2].CacheFile == "" || runs[2].FromCache != "" {
+		t.Errorf("英辞郎ファイルの変更でパースし直していません: %+v", runs[2])
+	}
+	if _, err := os.Stat(runs[0].CacheFile); !os.IsNotExist(err) {
+		t.Errorf("一時ファイルのキャッシュが削除されていません: %v", err)
+	}
+
+	if err := watchConversion(ctx, ConvertConfig{InputFile: input, FromCache: "x"}, time.Second, convert); err == nil {
+		t.Error("-from-cache との同時指定でエラーになりませんでした")
+	}
+}